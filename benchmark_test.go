@@ -144,6 +144,113 @@ func BenchmarkFormats(b *testing.B) {
 	}
 }
 
+// BenchmarkTimePlaceholder compares custom formats with and without {time},
+// showing the cost of converting and formatting the timestamp
+func BenchmarkTimePlaceholder(b *testing.B) {
+	formatters := []struct {
+		name      string
+		formatter string
+	}{
+		{"WithTime", "{time} {level} {message}"},
+		{"WithoutTime", "{level} {message}"},
+	}
+
+	for _, f := range formatters {
+		b.Run(f.name, func(b *testing.B) {
+			cfg := DefaultConfig()
+			cfg.Console.Color = false
+			cfg.Console.Formatter = f.formatter
+
+			handler, err := newCustomHandler(io.Discard, cfg, &cfg.Console, &slog.HandlerOptions{
+				Level: slog.LevelInfo,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			logger := slog.New(handler)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info(benchmarkMessage)
+			}
+		})
+	}
+}
+
+// BenchmarkGoroutinePlaceholder compares custom formats with and without {goroutine},
+// showing the cost of reading the goroutine ID for every record
+func BenchmarkGoroutinePlaceholder(b *testing.B) {
+	formatters := []struct {
+		name      string
+		formatter string
+	}{
+		{"WithGoroutine", "{goroutine} {level} {message}"},
+		{"WithoutGoroutine", "{level} {message}"},
+	}
+
+	for _, f := range formatters {
+		b.Run(f.name, func(b *testing.B) {
+			cfg := DefaultConfig()
+			cfg.Console.Color = false
+			cfg.Console.Formatter = f.formatter
+
+			handler, err := newCustomHandler(io.Discard, cfg, &cfg.Console, &slog.HandlerOptions{
+				Level: slog.LevelInfo,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			logger := slog.New(handler)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info(benchmarkMessage)
+			}
+		})
+	}
+}
+
+// BenchmarkAttrLogging compares the variadic any API with typed attributes,
+// showing the allocations saved by Infov
+func BenchmarkAttrLogging(b *testing.B) {
+	cfg := DefaultConfig()
+	cfg.Console.Format = FormatJSON
+
+	handler, err := newCustomHandler(io.Discard, cfg, &cfg.Console, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	logger := &Logger{Logger: slog.New(handler)}
+
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Info(benchmarkMessage,
+				"user_id", benchmarkUserID,
+				"request_id", benchmarkReqID,
+				"latency", 150*time.Millisecond,
+			)
+		}
+	})
+
+	b.Run("Infov", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			logger.Infov(benchmarkMessage,
+				slog.Int("user_id", benchmarkUserID),
+				slog.String("request_id", benchmarkReqID),
+				slog.Duration("latency", 150*time.Millisecond),
+			)
+		}
+	})
+}
+
 // BenchmarkColorOverhead compares performance with and without colors
 func BenchmarkColorOverhead(b *testing.B) {
 	b.Run("WithColor", func(b *testing.B) {
@@ -254,6 +361,63 @@ func BenchmarkRotationOverhead(b *testing.B) {
 			}
 		})
 	})
+
+	b.Run("AsyncWrites", func(b *testing.B) {
+		tmpDir := b.TempDir()
+		filePath := tmpDir + "/async_writes.log"
+
+		log, err := New(
+			WithConsole(false),
+			WithFile(true),
+			WithFilePath(filePath),
+			WithFileFormat(FormatJSON),
+			WithMaxSizeMB(100), // Large size, won't rotate during test
+			WithSyncWrites(false),
+			WithLevel(slog.LevelInfo),
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer log.Close()
+
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				log.Info(benchmarkMessage,
+					"user_id", benchmarkUserID,
+					"timestamp", time.Now(),
+				)
+			}
+		})
+	})
+}
+
+// BenchmarkFsync measures the cost of WithFsync, which commits every record to stable storage
+func BenchmarkFsync(b *testing.B) {
+	for _, fsync := range []bool{false, true} {
+		name := "PageCache"
+		if fsync {
+			name = "Fsync"
+		}
+		b.Run(name, func(b *testing.B) {
+			log, err := New(
+				WithConsole(false),
+				WithFilePath(b.TempDir()+"/fsync.log"),
+				WithFileFormat(FormatJSON),
+				WithMaxSizeMB(0),
+				WithFsync(fsync),
+			)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer log.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				log.Info(benchmarkMessage, "user_id", benchmarkUserID)
+			}
+		})
+	}
 }
 
 // =============================================================================
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// BufferedScope holds records in memory until they are flushed or discarded.
+// It embeds a *Logger, so records are logged on the scope directly (scope.Info(...)).
+// This enables transactional logging: buffer a request's lines and only emit them if it fails.
+type BufferedScope struct {
+	*Logger
+	state *bufferState
+}
+
+// bufferState holds the records buffered by a scope and all loggers derived from it
+type bufferState struct {
+	mu      sync.Mutex
+	records []bufferedRecord
+}
+
+// bufferedRecord is a record together with the handler it was logged through
+type bufferedRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// BeginBuffer starts a buffered scope over this logger.
+// Records below the logger's level are dropped immediately and never buffered.
+func (l *Logger) BeginBuffer() *BufferedScope {
+	state := &bufferState{}
+	return &BufferedScope{
+		Logger: &Logger{
+			Logger:     slog.New(&bufferHandler{handler: l.Handler(), state: state}),
+			filePath:   l.filePath,
+			levelVar:   l.levelVar,
+			fileWriter: l.fileWriter,
+			profile:    l.profile,
+		},
+		state: state,
+	}
+}
+
+// Flush emits all buffered records in order and clears the buffer.
+// The scope can keep buffering after Flush.
+func (s *BufferedScope) Flush() error {
+	records := s.state.take()
+
+	var errs []error
+	for _, br := range records {
+		if err := br.handler.Handle(br.ctx, br.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Discard drops all buffered records
+func (s *BufferedScope) Discard() {
+	s.state.take()
+}
+
+// Len returns the number of buffered records
+func (s *BufferedScope) Len() int {
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	return len(s.state.records)
+}
+
+// take removes and returns the buffered records
+func (s *bufferState) take() []bufferedRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := s.records
+	s.records = nil
+	return records
+}
+
+// bufferHandler is a slog.Handler that accumulates records instead of emitting them
+type bufferHandler struct {
+	handler slog.Handler
+	state   *bufferState
+}
+
+// Enabled implements slog.Handler
+func (h *bufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	h.state.records = append(h.state.records, bufferedRecord{
+		ctx:     context.WithoutCancel(ctx),
+		handler: h.handler,
+		record:  r.Clone(),
+	})
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &bufferHandler{handler: h.handler.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements slog.Handler
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &bufferHandler{handler: h.handler.WithGroup(name), state: h.state}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestBufferedScope(t *testing.T) {
+	newTestLogger := func(buf *bytes.Buffer) *Logger {
+		return &Logger{Logger: slog.New(slog.NewTextHandler(buf, nil))}
+	}
+
+	t.Run("Nothing emitted until Flush", func(t *testing.T) {
+		var buf bytes.Buffer
+		scope := newTestLogger(&buf).BeginBuffer()
+
+		scope.Info("one")
+		scope.With("step", 2).Warn("two")
+		scope.WithGroup("req").Error("three", "id", 3)
+
+		if buf.Len() != 0 {
+			t.Fatalf("Expected nothing emitted before Flush, got %q", buf.String())
+		}
+		if scope.Len() != 3 {
+			t.Errorf("Expected 3 buffered records, got %d", scope.Len())
+		}
+
+		if err := scope.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 lines after Flush, got %d: %q", len(lines), buf.String())
+		}
+		if !strings.Contains(lines[0], "msg=one") ||
+			!strings.Contains(lines[1], "step=2") ||
+			!strings.Contains(lines[2], "req.id=3") {
+			t.Errorf("Expected records in order with their attrs, got %q", buf.String())
+		}
+		if scope.Len() != 0 {
+			t.Errorf("Expected empty buffer after Flush, got %d", scope.Len())
+		}
+	})
+
+	t.Run("Nothing emitted after Discard", func(t *testing.T) {
+		var buf bytes.Buffer
+		scope := newTestLogger(&buf).BeginBuffer()
+
+		scope.Info("one")
+		scope.Info("two")
+		scope.Info("three")
+		scope.Discard()
+
+		if err := scope.Flush(); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected nothing emitted after Discard, got %q", buf.String())
+		}
+	})
+
+	t.Run("Disabled levels are not buffered", func(t *testing.T) {
+		var buf bytes.Buffer
+		scope := newTestLogger(&buf).BeginBuffer()
+
+		scope.Debug("below level")
+		if scope.Len() != 0 {
+			t.Errorf("Expected debug record not to be buffered, got %d", scope.Len())
+		}
+	})
+}
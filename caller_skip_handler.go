@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+)
+
+// callerSkipHandler is a slog.Handler that attributes records to a caller further up the stack,
+// for loggers wrapped in helper functions
+type callerSkipHandler struct {
+	handler slog.Handler
+	skip    int
+}
+
+// newCallerSkipHandler wraps a handler so each record's PC moves skip frames up from the
+// function that logged it
+func newCallerSkipHandler(handler slog.Handler, skip int) slog.Handler {
+	return &callerSkipHandler{handler: handler, skip: skip}
+}
+
+// callerSkipDepth is the number of frames between callerSkipHandler.Handle and the logging call
+// searched for the record's PC, enough for the decorators and slog's own frames
+const callerSkipDepth = 16
+
+// skipCallers returns the PC skip frames above pc on the current stack. It returns pc
+// unchanged when pc isn't on the stack, e.g. for records handled on another goroutine,
+// or when the stack is not deep enough.
+func skipCallers(pc uintptr, skip int) uintptr {
+	var buf [2 * callerSkipDepth]uintptr
+	pcs := buf[:]
+	if need := callerSkipDepth + skip; need > len(pcs) {
+		pcs = make([]uintptr, need)
+	}
+	n := runtime.Callers(3, pcs)
+	i := slices.Index(pcs[:n], pc)
+	if i < 0 || i+skip >= n {
+		return pc
+	}
+	return pcs[i+skip]
+}
+
+// Enabled implements slog.Handler
+func (h *callerSkipHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *callerSkipHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.PC != 0 {
+		r.PC = skipCallers(r.PC, h.skip)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *callerSkipHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &callerSkipHandler{handler: h.handler.WithAttrs(attrs), skip: h.skip}
+}
+
+// WithGroup implements slog.Handler
+func (h *callerSkipHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &callerSkipHandler{handler: h.handler.WithGroup(name), skip: h.skip}
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// logFailure is a logging helper whose callers should be reported as the source
+//
+//go:noinline
+func logFailure(log *Logger, msg string) {
+	log.Error(msg)
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(WithConsole(false), WithWriter(&buf, FormatJSON), WithAddSource(true), WithCallerSkip(1))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	_, file, line, _ := runtime.Caller(0)
+	logFailure(log, "through the helper")
+
+	entries := decodeJSONLines(t, &buf)
+	source, ok := entries[0]["source"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected a source object, got %v", entries[0]["source"])
+	}
+	if !strings.HasSuffix(source["function"].(string), ".TestWithCallerSkip") {
+		t.Errorf("Expected the helper's caller as the function, got %v", source["function"])
+	}
+	if source["file"] != file || source["line"] != float64(line+1) {
+		t.Errorf("Expected %s:%d, got %v:%v", file, line+1, source["file"], source["line"])
+	}
+}
+
+func TestWithCallerSkip_CustomFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(
+		WithConsole(false),
+		WithWriter(&buf, FormatCustom),
+		WithFileFormatter("{file} {message}"),
+		WithAddSource(true),
+		WithCallerSkip(1),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	logFailure(log, "custom")
+	if out := buf.String(); !strings.Contains(out, "caller_skip_handler_test.go:logger.TestWithCallerSkip_CustomFormat:") {
+		t.Errorf("Expected the helper's caller in {file}, got %q", out)
+	}
+}
+
+func TestSkipCallers(t *testing.T) {
+	// A PC that isn't on the stack, or a skip beyond the stack, keeps the original caller
+	if got := skipCallers(0x1, 1); got != 0x1 {
+		t.Errorf("Expected an unknown PC to be returned unchanged, got %#x", got)
+	}
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	if got := skipCallers(pcs[0], maxStackFrames); got != pcs[0] {
+		t.Errorf("Expected a skip beyond the stack to return the PC unchanged, got %#x", got)
+	}
+}
+
+func TestWithCallerSkip_Validation(t *testing.T) {
+	if _, err := New(WithCallerSkip(-1)); err == nil {
+		t.Error("Expected an error for a negative caller skip")
+	}
+
+	// Without a source or a stack to report, records aren't rewritten at all
+	cfg := DefaultConfig()
+	WithCallerSkip(1)(cfg)
+	if _, ok := wrapHandler(NewCaptureHandler(slog.LevelInfo), cfg).(*callerSkipHandler); ok {
+		t.Error("Expected no caller skip handler without AddSource or WithStackTrace")
+	}
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record is a captured log record with typed fields, for asserting on output in tests
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Source  *slog.Source // nil when the record carries no caller
+	Attrs   []slog.Attr  // In output order, preset attrs first; groups are slog.KindGroup values
+}
+
+// Attr returns the value of the attribute with the given key.
+// Keys inside groups are addressed by their dotted path, e.g. "request.id".
+func (r Record) Attr(key string) (slog.Value, bool) {
+	return findAttr(r.Attrs, key)
+}
+
+// findAttr looks up a dotted key path in attrs, descending into groups
+func findAttr(attrs []slog.Attr, key string) (slog.Value, bool) {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			if rest, ok := strings.CutPrefix(key, a.Key+"."); ok {
+				if v, ok := findAttr(a.Value.Group(), rest); ok {
+					return v, true
+				}
+			}
+		}
+	}
+	return slog.Value{}, false
+}
+
+// CaptureHandler is a slog.Handler that keeps records in memory instead of writing them.
+// Handlers derived with WithAttrs/WithGroup share the captured records.
+type CaptureHandler struct {
+	state *captureState
+	level slog.Leveler
+	ops   []captureOp // WithAttrs/WithGroup calls, outermost first
+}
+
+type captureState struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// captureOp is either a group name or a set of preset attrs
+type captureOp struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewCaptureHandler creates a CaptureHandler that records levels at or above level.
+// A nil level captures every record.
+func NewCaptureHandler(level slog.Leveler) *CaptureHandler {
+	return &CaptureHandler{state: &captureState{}, level: level}
+}
+
+// Records returns a copy of the records captured so far, oldest first
+func (h *CaptureHandler) Records() []Record {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	records := make([]Record, len(h.state.records))
+	copy(records, h.state.records)
+	return records
+}
+
+// Reset discards the captured records
+func (h *CaptureHandler) Reset() {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+	h.state.records = nil
+}
+
+// Enabled implements slog.Handler
+func (h *CaptureHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.level == nil || level >= h.level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *CaptureHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendResolved(attrs, a)
+		return true
+	})
+
+	// Apply preset attrs and groups from the innermost outwards
+	for i := len(h.ops) - 1; i >= 0; i-- {
+		op := h.ops[i]
+		if op.group == "" {
+			attrs = append(append([]slog.Attr{}, op.attrs...), attrs...)
+		} else if len(attrs) > 0 {
+			attrs = []slog.Attr{{Key: op.group, Value: slog.GroupValue(attrs...)}}
+		}
+	}
+
+	rec := Record{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: attrs}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		rec.Source = &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+	}
+
+	h.state.mu.Lock()
+	h.state.records = append(h.state.records, rec)
+	h.state.mu.Unlock()
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *CaptureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	resolved := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		resolved = appendResolved(resolved, a)
+	}
+	return h.with(captureOp{attrs: resolved})
+}
+
+// WithGroup implements slog.Handler
+func (h *CaptureHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(captureOp{group: name})
+}
+
+func (h *CaptureHandler) with(op captureOp) *CaptureHandler {
+	return &CaptureHandler{
+		state: h.state,
+		level: h.level,
+		ops:   append(h.ops[:len(h.ops):len(h.ops)], op),
+	}
+}
+
+// appendResolved appends a with LogValuers resolved, dropping empty attrs and
+// inlining groups without a key as slog's built-in handlers do
+func appendResolved(attrs []slog.Attr, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return append(attrs, a)
+	}
+	var group []slog.Attr
+	for _, ga := range a.Value.Group() {
+		group = appendResolved(group, ga)
+	}
+	if len(group) == 0 {
+		return attrs
+	}
+	if a.Key == "" {
+		return append(attrs, group...)
+	}
+	return append(attrs, slog.Attr{Key: a.Key, Value: slog.GroupValue(group...)})
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaptureHandler_TypedRecord(t *testing.T) {
+	handler := NewCaptureHandler(nil)
+	logger := slog.New(handler).With("service", "billing").WithGroup("request")
+
+	logger.Warn("slow request",
+		slog.Int("status", 200),
+		slog.Duration("latency", 1500*time.Millisecond),
+		slog.Group("user", slog.String("id", "u-1")),
+	)
+
+	records := handler.Records()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	rec := records[0]
+
+	if rec.Level != slog.LevelWarn {
+		t.Errorf("Expected level WARN, got %v", rec.Level)
+	}
+	if rec.Message != "slow request" {
+		t.Errorf("Expected message %q, got %q", "slow request", rec.Message)
+	}
+	if rec.Time.IsZero() {
+		t.Error("Expected a record time")
+	}
+	if rec.Source == nil || !strings.HasSuffix(rec.Source.File, "capture_handler_test.go") {
+		t.Errorf("Expected source in this file, got %+v", rec.Source)
+	}
+
+	if len(rec.Attrs) != 2 || rec.Attrs[0].Key != "service" || rec.Attrs[1].Key != "request" {
+		t.Fatalf("Expected attrs [service request], got %v", rec.Attrs)
+	}
+	if v, ok := rec.Attr("service"); !ok || v.String() != "billing" {
+		t.Errorf("Expected service=billing, got %v (found %v)", v, ok)
+	}
+	if v, ok := rec.Attr("request.status"); !ok || v.Int64() != 200 {
+		t.Errorf("Expected request.status=200, got %v (found %v)", v, ok)
+	}
+	if v, ok := rec.Attr("request.latency"); !ok || v.Duration() != 1500*time.Millisecond {
+		t.Errorf("Expected request.latency=1.5s, got %v (found %v)", v, ok)
+	}
+	if v, ok := rec.Attr("request.user.id"); !ok || v.String() != "u-1" {
+		t.Errorf("Expected request.user.id=u-1, got %v (found %v)", v, ok)
+	}
+	if _, ok := rec.Attr("status"); ok {
+		t.Error("Expected status to be addressable only inside its group")
+	}
+}
+
+func TestCaptureHandler_LevelAndSharedRecords(t *testing.T) {
+	handler := NewCaptureHandler(slog.LevelInfo)
+	logger := slog.New(handler)
+	child := logger.With("component", "db")
+
+	logger.Debug("dropped")
+	logger.Info("first")
+	child.Error("second", "error", errors.New("boom"))
+
+	records := handler.Records()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[0].Message != "first" || records[1].Message != "second" {
+		t.Errorf("Unexpected messages: %q, %q", records[0].Message, records[1].Message)
+	}
+	if v, ok := records[1].Attr("error"); !ok || v.Any().(error).Error() != "boom" {
+		t.Errorf("Expected error attr to keep its type, got %v", v)
+	}
+
+	handler.Reset()
+	if n := len(handler.Records()); n != 0 {
+		t.Errorf("Expected no records after Reset, got %d", n)
+	}
+}
+
+func TestCaptureHandler_EmptyGroupDropped(t *testing.T) {
+	handler := NewCaptureHandler(nil)
+	slog.New(handler).WithGroup("empty").Info("no attrs", slog.Group("inline", slog.Attr{}))
+
+	rec := handler.Records()[0]
+	if len(rec.Attrs) != 0 {
+		t.Errorf("Expected empty groups to be dropped, got %v", rec.Attrs)
+	}
+}
@@ -0,0 +1,126 @@
+package logger
+
+import "fmt"
+
+// ColorProfile is the set of ANSI styles used by the custom format when color is enabled.
+// An empty style leaves that element uncolored.
+type ColorProfile struct {
+	Debug    string // Level styles
+	Info     string
+	Warn     string
+	Error    string
+	Critical string // Levels above Error
+
+	ErrorMessage string // Message of records at Error and above
+	Muted        string // Time, keys, source and other secondary fields
+	ErrorKey     string // Key of the "error" attribute at Error and above
+	ErrorValue   string // Value of the "error" attribute at Error and above
+
+	WarnErrorKey   string // Key of the "error" attribute from the highlight level (Warn) up to Error
+	WarnErrorValue string // Value of the "error" attribute from the highlight level (Warn) up to Error
+}
+
+// Built-in color profiles for WithColorProfile
+var (
+	// ProfileDefault uses bright ANSI colors with dimmed secondary fields
+	ProfileDefault = ColorProfile{
+		Debug:        ansiBrightCyan,
+		Info:         ansiBrightGreen,
+		Warn:         ansiBrightYellow,
+		Error:        ansiBrightRed,
+		Critical:     ansiBrightMagenta,
+		ErrorMessage: ansiBrightRed,
+		Muted:        ansiFaint,
+		ErrorKey:     ansiBrightRedFaint,
+		ErrorValue:   ansiBrightRed,
+
+		WarnErrorKey:   ansiBrightYellowFaint,
+		WarnErrorValue: ansiBrightYellow,
+	}
+
+	// ProfileSolarized uses the Solarized accent colors (256-color palette)
+	ProfileSolarized = ColorProfile{
+		Debug:        "\033[38;5;37m",  // cyan
+		Info:         "\033[38;5;64m",  // green
+		Warn:         "\033[38;5;136m", // yellow
+		Error:        "\033[38;5;160m", // red
+		Critical:     "\033[38;5;125m", // magenta
+		ErrorMessage: "\033[38;5;160m",
+		Muted:        "\033[38;5;240m", // base01
+		ErrorKey:     "\033[38;5;166m", // orange
+		ErrorValue:   "\033[38;5;160m",
+
+		WarnErrorKey:   "\033[38;5;136;2m", // dim yellow
+		WarnErrorValue: "\033[38;5;136m",
+	}
+
+	// ProfileMonochrome uses bold and dim instead of colors
+	ProfileMonochrome = ColorProfile{
+		Warn:         "\033[1m",
+		Error:        "\033[1m",
+		Critical:     "\033[1;4m",
+		ErrorMessage: "\033[1m",
+		Muted:        ansiFaint,
+		ErrorKey:     "\033[1m",
+		ErrorValue:   "\033[1m",
+
+		WarnErrorValue: "\033[1m",
+	}
+
+	// ProfileHighContrast uses bold colors, inverse backgrounds for errors and no dimming
+	ProfileHighContrast = ColorProfile{
+		Debug:        "\033[1;96m",
+		Info:         "\033[1;92m",
+		Warn:         "\033[1;93m",
+		Error:        "\033[1;97;41m",
+		Critical:     "\033[1;97;45m",
+		ErrorMessage: "\033[1;91m",
+		Muted:        "\033[37m",
+		ErrorKey:     "\033[1;91m",
+		ErrorValue:   "\033[1;91m",
+
+		WarnErrorKey:   "\033[1;93m",
+		WarnErrorValue: "\033[1;93m",
+	}
+)
+
+// colorProfile returns the configured profile, falling back to ProfileDefault when unset
+func (c *Config) colorProfile() *ColorProfile {
+	if c.Colors == (ColorProfile{}) {
+		return &ProfileDefault
+	}
+	return &c.Colors
+}
+
+// ColorTheme holds the ANSI codes of the level buckets, see WithColorTheme. It only covers
+// levels; messages, keys and secondary fields keep the ColorProfile styles.
+type ColorTheme struct {
+	Debug    string // Debug and below
+	Info     string // Above Debug up to Info
+	Warn     string // Above Info up to Warn
+	Error    string // Above Warn up to Error
+	Critical string // Above Error
+}
+
+// DefaultColorTheme returns the level colors of ProfileDefault: cyan, green, yellow, red and magenta
+func DefaultColorTheme() ColorTheme {
+	return ColorTheme{
+		Debug:    ProfileDefault.Debug,
+		Info:     ProfileDefault.Info,
+		Warn:     ProfileDefault.Warn,
+		Error:    ProfileDefault.Error,
+		Critical: ProfileDefault.Critical,
+	}
+}
+
+// validate reports an error naming the first empty code
+func (t *ColorTheme) validate() error {
+	for _, bucket := range []struct{ name, code string }{
+		{"Debug", t.Debug}, {"Info", t.Info}, {"Warn", t.Warn}, {"Error", t.Error}, {"Critical", t.Critical},
+	} {
+		if bucket.code == "" {
+			return fmt.Errorf("invalid color theme: %s has no ANSI code", bucket.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// renderWithProfile renders one record per level through a colored custom handler
+func renderWithProfile(t *testing.T, profile ColorProfile) string {
+	t.Helper()
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.Level = slog.LevelDebug
+	WithColorProfile(profile)(cfg)
+
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		color:     true,
+		formatter: "{level} {message} {attrs}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+	logger.Debug("debug", "k", "v")
+	logger.Info("info", "k", "v")
+	logger.Warn("warn", "k", "v")
+	logger.Error("error", "error", "boom")
+	return buf.String()
+}
+
+func TestColorProfiles_Distinct(t *testing.T) {
+	profiles := map[string]ColorProfile{
+		"Default":      ProfileDefault,
+		"Solarized":    ProfileSolarized,
+		"Monochrome":   ProfileMonochrome,
+		"HighContrast": ProfileHighContrast,
+	}
+
+	outputs := make(map[string]string)
+	for name, profile := range profiles {
+		output := renderWithProfile(t, profile)
+		for other, seen := range outputs {
+			if seen == output {
+				t.Errorf("Profiles %s and %s render identically", name, other)
+			}
+		}
+		outputs[name] = output
+
+		// Each style in the profile shows up in the output
+		for _, style := range []string{profile.Warn, profile.Error, profile.Muted, profile.ErrorValue} {
+			if style != "" && !strings.Contains(output, style) {
+				t.Errorf("Profile %s: expected style %q in output %q", name, style, output)
+			}
+		}
+	}
+}
+
+func TestColorProfiles_MonochromeHasNoColors(t *testing.T) {
+	output := renderWithProfile(t, ProfileMonochrome)
+
+	// SGR foreground/background color parameters: 30-37, 38 (extended), 40-47, 90-97, 100-107
+	colorCode := regexp.MustCompile(`\x1b\[(?:[0-9;]*;)?(?:3[0-8]|4[0-7]|9[0-7]|10[0-7])(?:;[0-9;]*)?m`)
+	if m := colorCode.FindString(output); m != "" {
+		t.Errorf("Expected no color escape sequences in monochrome output, found %q in %q", m, output)
+	}
+	if !strings.Contains(output, "\033[1mWARN") {
+		t.Errorf("Expected bold WARN in monochrome output, got %q", output)
+	}
+	// Uncolored elements carry no escape sequence at all
+	if !strings.HasPrefix(output, "DEBUG debug") {
+		t.Errorf("Expected plain DEBUG level in monochrome output, got %q", output)
+	}
+}
+
+func TestColorProfiles_DefaultUnchanged(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.colorProfile(); *got != ProfileDefault {
+		t.Errorf("Expected unset profile to fall back to ProfileDefault, got %+v", *got)
+	}
+	if DefaultConfig().Colors != ProfileDefault {
+		t.Error("Expected DefaultConfig to use ProfileDefault")
+	}
+}
+
+func TestErrorHighlightLevel(t *testing.T) {
+	render := func(level slog.Level, opts ...Option) string {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		cfg.Level = slog.LevelDebug
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+			format:    FormatCustom,
+			color:     true,
+			formatter: "{attrs}",
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		slog.New(handler).Log(context.Background(), level, "msg", "error", "boom")
+		return buf.String()
+	}
+	highlighted := func(keyColor, valueColor string) string {
+		return keyColor + "error" + ansiReset + keyColor + "=" + ansiReset + valueColor + "boom" + ansiReset + "\n"
+	}
+	muted := ansiFaint + "error" + ansiReset + ansiFaint + "=" + ansiReset + "boom\n"
+
+	if got, want := render(slog.LevelWarn), highlighted(ansiBrightYellowFaint, ansiBrightYellow); got != want {
+		t.Errorf("Warn: expected %q, got %q", want, got)
+	}
+	if got, want := render(slog.LevelError), highlighted(ansiBrightRedFaint, ansiBrightRed); got != want {
+		t.Errorf("Error: expected %q, got %q", want, got)
+	}
+	if got := render(slog.LevelInfo); got != muted {
+		t.Errorf("Info: expected %q, got %q", muted, got)
+	}
+
+	// Raising the threshold restores Error-only highlighting
+	if got := render(slog.LevelWarn, WithErrorHighlightLevel(slog.LevelError)); got != muted {
+		t.Errorf("Warn with Error threshold: expected %q, got %q", muted, got)
+	}
+	if got, want := render(slog.LevelInfo, WithErrorHighlightLevel(slog.LevelInfo)), highlighted(ansiBrightYellowFaint, ansiBrightYellow); got != want {
+		t.Errorf("Info with Info threshold: expected %q, got %q", want, got)
+	}
+}
+
+func TestWithColorTheme(t *testing.T) {
+	theme := DefaultColorTheme()
+	theme.Warn = "\033[38;5;208m" // orange, readable on light backgrounds
+
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	WithColorTheme(theme)(cfg)
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		color:     true,
+		formatter: "{level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	logger.Warn("disk almost full")
+	if want := "\033[38;5;208mWARN" + ansiReset; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected the theme's Warn code, got %q", buf.String())
+	}
+	buf.Reset()
+	logger.Info("ok")
+	if want := ProfileDefault.Info + "INFO" + ansiReset; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected the default Info code, got %q", buf.String())
+	}
+}
+
+func TestColorTheme_DefaultAndValidation(t *testing.T) {
+	cfg := DefaultConfig()
+	WithColorTheme(DefaultColorTheme())(cfg)
+	if cfg.Colors != ProfileDefault {
+		t.Errorf("Expected DefaultColorTheme to match the default profile's level colors, got %+v", cfg.Colors)
+	}
+
+	// The theme edits the current profile, and a later profile replaces it
+	theme := DefaultColorTheme()
+	theme.Warn = "\033[38;5;208m"
+	cfg = DefaultConfig()
+	WithColorProfile(ProfileSolarized)(cfg)
+	WithColorTheme(theme)(cfg)
+	if cfg.Colors.Warn != theme.Warn || cfg.Colors.Muted != ProfileSolarized.Muted {
+		t.Errorf("Expected the theme's Warn code over the Solarized profile, got %+v", cfg.Colors)
+	}
+	WithColorProfile(ProfileMonochrome)(cfg)
+	if cfg.Colors != ProfileMonochrome {
+		t.Errorf("Expected a later WithColorProfile to replace the theme, got %+v", cfg.Colors)
+	}
+
+	theme = DefaultColorTheme()
+	theme.Error = ""
+	_, err := New(WithColorTheme(theme))
+	if err == nil || !strings.Contains(err.Error(), "Error") {
+		t.Errorf("Expected an error naming the empty Error code, got %v", err)
+	}
+}
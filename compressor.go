@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// compressor is a codec for rotated log files registered with WithCompressor
+type compressor struct {
+	name     string
+	compress func(dst io.Writer, src io.Reader) error
+	ext      string // Suffix appended to compressed files, e.g. ".lz4"
+}
+
+// compressors holds the codecs registered across the process, so rotated files with
+// any registered extension are recognized by retention cleanup and RotatedFiles
+var compressors = struct {
+	mu     sync.RWMutex
+	byName map[string]*compressor
+}{
+	byName: make(map[string]*compressor),
+}
+
+// registerCompressor validates c and stores it under its name, replacing any previous codec
+func registerCompressor(c *compressor) error {
+	if c.name == "" {
+		return fmt.Errorf("compressor name must not be empty")
+	}
+	if c.compress == nil {
+		return fmt.Errorf("compressor %q has no compress function", c.name)
+	}
+	if len(c.ext) < 2 || c.ext[0] != '.' || strings.ContainsAny(c.ext, `/\`) {
+		return fmt.Errorf("invalid extension %q for compressor %q (must start with a dot, e.g. \".lz4\")", c.ext, c.name)
+	}
+	compressors.mu.Lock()
+	defer compressors.mu.Unlock()
+	compressors.byName[c.name] = c
+	return nil
+}
+
+// compressedExts returns the suffixes of compressed rotated files: .gz, which
+// OpenLogFile reads, and those of every registered codec
+func compressedExts() []string {
+	compressors.mu.RLock()
+	defer compressors.mu.RUnlock()
+	exts := []string{".gz"}
+	for _, c := range compressors.byName {
+		if !slices.Contains(exts, c.ext) {
+			exts = append(exts, c.ext)
+		}
+	}
+	slices.Sort(exts)
+	return exts
+}
+
+// compressFile compresses the file at path into path+ext and removes the original.
+// Output goes to a temporary file first, so a failed or interrupted compression never
+// leaves a truncated file under a rotated name. The original modification time is kept,
+// so age-based retention is unaffected.
+func (c *compressor) compressFile(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open rotated file: %w", err)
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat rotated file: %w", err)
+	}
+
+	target := path + c.ext
+	tmp := target + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return "", fmt.Errorf("failed to create compressed file: %w", err)
+	}
+	if err := c.compress(dst, src); err != nil {
+		dst.Close()
+		os.Remove(tmp)
+		return "", fmt.Errorf("%s compression failed: %w", c.name, err)
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to close compressed file: %w", err)
+	}
+	_ = os.Chtimes(tmp, info.ModTime(), info.ModTime()) // best effort
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to rename compressed file: %w", err)
+	}
+
+	src.Close() // Windows cannot remove open files
+	if err := os.Remove(path); err != nil {
+		return target, fmt.Errorf("failed to remove uncompressed file: %w", err)
+	}
+	return target, nil
+}
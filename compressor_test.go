@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// prefixCodec is a trivial codec that marks its output with a prefix
+func prefixCodec(dst io.Writer, src io.Reader) error {
+	if _, err := io.WriteString(dst, "codec:"); err != nil {
+		return err
+	}
+	_, err := io.Copy(dst, src)
+	return err
+}
+
+func TestRotatingWriter_Compressor(t *testing.T) {
+	codec := &compressor{name: "prefix", compress: prefixCodec, ext: ".pfx"}
+	if err := registerCompressor(codec); err != nil {
+		t.Fatalf("registerCompressor failed: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "app.log",
+		retentionDays: 7,
+		compressor:    codec,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("rotated line\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	event, err := w.rotate()
+	if err != nil || event == nil {
+		t.Fatalf("rotate() failed: %v", err)
+	}
+
+	if !strings.HasSuffix(event.oldFile, ".log.pfx") {
+		t.Fatalf("Expected the rotated file to carry the codec extension, got %q", event.oldFile)
+	}
+	content, err := os.ReadFile(event.oldFile)
+	if err != nil || string(content) != "codec:rotated line\n" {
+		t.Errorf("Unexpected compressed content %q (err %v)", content, err)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(event.oldFile, codec.ext)); !os.IsNotExist(err) {
+		t.Error("Expected the uncompressed file to be removed")
+	}
+	if matches, _ := filepath.Glob(filepath.Join(tempDir, "*.tmp")); len(matches) != 0 {
+		t.Errorf("Expected no temporary files, got %v", matches)
+	}
+
+	files, err := listRotatedFiles(filepath.Join(tempDir, "app.log"))
+	if err != nil {
+		t.Fatalf("listRotatedFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0].Path != event.oldFile || !files[0].Compressed {
+		t.Fatalf("Expected the compressed file to be listed as compressed, got %+v", files)
+	}
+
+	// Retention cleanup recognizes the extension too
+	old := time.Now().AddDate(0, 0, -30)
+	if err := os.Chtimes(event.oldFile, old, old); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	w.cleanOldLogs(context.Background())
+	if _, err := os.Stat(event.oldFile); !os.IsNotExist(err) {
+		t.Error("Expected the expired compressed file to be removed")
+	}
+}
+
+func TestRotatingWriter_CompressorFailureKeepsFile(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory: tempDir,
+		fileName:  "app.log",
+		compressor: &compressor{name: "broken", ext: ".brk", compress: func(io.Writer, io.Reader) error {
+			return errors.New("codec failure")
+		}},
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("kept\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	event, err := w.rotate()
+	if err != nil || event == nil {
+		t.Fatalf("rotate() failed: %v", err)
+	}
+	if content, err := os.ReadFile(event.oldFile); err != nil || string(content) != "kept\n" {
+		t.Errorf("Expected the rotated file to stay uncompressed, got %q (err %v)", content, err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(tempDir, "*.brk*")); len(matches) != 0 {
+		t.Errorf("Expected no compressed or temporary files, got %v", matches)
+	}
+}
+
+func TestWithCompressor_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		codec    string
+		compress func(io.Writer, io.Reader) error
+		ext      string
+	}{
+		{"empty name", "", prefixCodec, ".pfx"},
+		{"nil function", "nil", nil, ".pfx"},
+		{"missing dot", "nodot", prefixCodec, "pfx"},
+		{"bare dot", "dot", prefixCodec, "."},
+		{"path separator", "slash", prefixCodec, ".a/b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(
+				WithConsole(false),
+				WithFilePath(filepath.Join(t.TempDir(), "app.log")),
+				WithCompressor(tt.codec, tt.compress, tt.ext),
+			)
+			if err == nil {
+				t.Error("Expected an error for an invalid compressor")
+			}
+		})
+	}
+}
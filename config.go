@@ -1,10 +1,16 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,12 +20,21 @@ const (
 	FormatText   OutputFormat = "text"
 	FormatJSON   OutputFormat = "json"
 	FormatCustom OutputFormat = "custom"
+	FormatSyslog OutputFormat = "syslog" // RFC5424 lines with attributes as structured data
+	FormatLogfmt OutputFormat = "logfmt" // key=value lines with quoted values and dotted group keys
 
 	DefaultTimeFormat    = "2006/01/02 15:04:05"
 	DefaultMaxSizeMB     = 10
 	DefaultRetentionDays = 7
+	DefaultBufferSize    = 64 * 1024              // Capacity of the file writer's buffer in bytes
+	DefaultFlushInterval = 200 * time.Millisecond // How often the file buffer is flushed without sync writes
+	DefaultFileMode      = os.FileMode(0o644)     // Permissions of created log files
+	DefaultDirMode       = os.FileMode(0o755)     // Permissions of created log directories
 	DefaultFormatter     = "{time} {level} {message} {file} {attrs}"
+	CanonicalFormatter   = "{time} {level} {file} {message} {attrs}" // Layout used by WithCanonicalOrder
 	DefaultFormat        = FormatText
+
+	DefaultMaxPooledBuilderSize = 64 * 1024
 )
 
 type Config struct {
@@ -29,28 +44,213 @@ type Config struct {
 	TimeFormat string
 	TimeZone   *time.Location
 
+	// TimeZoneInTimestamp appends the zone offset to TimeFormat when it has none
+	TimeZoneInTimestamp bool
+
+	// LevelVar, when set, is the live level threshold and takes precedence over Level
+	LevelVar *slog.LevelVar
+
 	// Configurations for different log destinations
-	Console ConsoleConfig
-	File    FileConfig
+	Console  ConsoleConfig
+	File     FileConfig
+	Journald JournaldConfig
+	Network  NetworkConfig
+	Syslog   SyslogConfig
+	Writers  []WriterConfig // Additional destinations added with WithWriter
+
+	FileOutputs []FileOutput // Additional log files added with WithFileOutput
 
 	// ReplaceAttr is a function that can be used to replace attributes in log messages
 	ReplaceAttr func(groups []string, a slog.Attr) slog.Attr
+
+	// MessageKey emits the message under this key instead of "msg" (JSON/text) or positionally (custom)
+	MessageKey string
+
+	// BadKeyName renames slog's "!BADKEY" attrs (values passed without a key); DropBadKeys removes them
+	BadKeyName  string
+	DropBadKeys bool
+
+	// Encoder, when set, replaces the console and file formats with fully custom serialization
+	Encoder Encoder
+
+	// StrictCustomFormat makes validation fail when FormatCustom has no formatter instead of using DefaultFormatter,
+	// or when a formatter has no {message} placeholder
+	StrictCustomFormat bool
+
+	// AllowMessagelessFormatter accepts custom formatters without {message} silently
+	AllowMessagelessFormatter bool
+
+	// LiteralTemplate renders custom formatters exactly as written, without collapsing spaces around empty placeholders
+	LiteralTemplate bool
+
+	// CanonicalOrder renders custom format lines as CanonicalFormatter with attributes sorted by key
+	CanonicalOrder bool
+
+	// Colors is the ANSI style set used by the custom format when color is enabled
+	Colors ColorProfile
+
+	// ColorDecider returns the ANSI code for a record's level and message, empty keeps the level colors
+	ColorDecider func(r slog.Record) string
+
+	// ErrorHighlightLevel is the lowest level whose "error" attribute is highlighted, nil means Warn
+	ErrorHighlightLevel slog.Leveler
+
+	// GroupPrefixOnce makes the custom format emit the group path once before the attributes instead of on every key
+	GroupPrefixOnce bool
+
+	// UngroupedAttrs lists attribute keys the custom format keeps at the root, without the group prefix
+	UngroupedAttrs []string
+
+	// StringerAsIs renders fmt.Stringer attribute values as their Go value instead of String()
+	StringerAsIs bool
+
+	// MaxLineBytes caps each custom-format line (excluding the newline) at this many bytes, 0 means unlimited
+	MaxLineBytes int
+
+	// PathExpansion expands a leading ~ and $VAR references in file paths
+	PathExpansion bool
+
+	// MaxPooledBuilderSize is the largest builder capacity (in bytes) kept in the formatting pool
+	MaxPooledBuilderSize int
+
+	// OnRecord is called after each successful write by a custom format destination
+	OnRecord func(level slog.Level, bytesWritten int)
+
+	// OnError is called when a custom format destination fails to write a record
+	OnError func(err error, r slog.Record)
+
+	// ErrorHandler is called with each failure to write to a destination, including buffered
+	// flushes and rotations of log files that happen in the background
+	ErrorHandler func(err error)
+
+	// SelfProfiling measures the time spent handling each record, reported by Logger.Stats
+	SelfProfiling bool
+
+	// Record decorators
+	EntryID     bool          // Add a unique log_id attribute to every record
+	IDGenerator func() string // Generator for log_id values, defaults to a random UUID
+
+	// ContextExtractor returns attributes to add to each record from its context, e.g. a trace ID
+	ContextExtractor func(ctx context.Context) []slog.Attr
+
+	ElapsedAttr bool // Add an elapsed_ms attribute with milliseconds since the logger was created
+	PackageAttr bool // With AddSource, add a pkg attribute with the caller's package import path
+
+	// SourceFormat is how {file} shows the source file path, empty means SourceShort
+	SourceFormat SourceFormat
+
+	// CallerSkip attributes records to the caller this many frames above the logging call
+	CallerSkip int
+
+	// StackTraceLevel adds a stack attribute to records at or above this level, nil disables it
+	StackTraceLevel slog.Leveler
+
+	// StackDedupWindow abbreviates stack attributes identical to one emitted within this window (0 disables it)
+	StackDedupWindow time.Duration
+
+	// LevelSampling maps a level to a 1-in-N sampling rate (0, 1 or absent keeps all records)
+	LevelSampling map[slog.Level]int
+
+	// Sampling drops records repeating the same level and message too often (zero disables it)
+	Sampling SamplingConfig
+
+	// DedupWindow collapses identical consecutive records within this window (0 disables it)
+	DedupWindow time.Duration
+
+	// Heartbeat emits a periodic Info record with runtime stats (0 disables it)
+	HeartbeatInterval time.Duration
+	HeartbeatMessage  string // Message for heartbeat records, defaults to DefaultHeartbeatMessage
+
+	// SuppressionReportInterval logs how many records were dropped, per reason, every interval (0 disables it)
+	SuppressionReportInterval time.Duration
+
+	startTime  time.Time         // Set when the logger is created, reference for {elapsed} and elapsed_ms
+	suppressed *suppressionStats // Drop counters for the suppression report, nil when it is disabled
+
+	onErrorActive      callbackGuard // Goroutines running OnError, whose failures are not reported again
+	errorHandlerActive callbackGuard // Same guard for ErrorHandler
+
+	profile   *handleProfile // Handling durations for Logger.Stats, nil when self-profiling is disabled
+	envErr    error          // First invalid environment variable seen by FromEnv, reported by validation
+	optionErr error          // First invalid option value, reported by validation
+	sampler   *repeatSampler // Sampler for WithSampling, nil when it is disabled
+	dedup     *dedupState    // Current run for WithDedup, nil when it is disabled
 }
 
+// SourceFormat selects how the custom format's {file} placeholder shows the source file path
+type SourceFormat string
+
+const (
+	SourceShort   SourceFormat = "short"   // File name only, e.g. handler.go
+	SourceFull    SourceFormat = "full"    // Path as recorded by the compiler, absolute unless built with -trimpath
+	SourcePackage SourceFormat = "package" // Directory and file name, e.g. logger/handler.go
+)
+
 type ConsoleConfig struct {
-	Enabled   bool         // Enable console logging
-	Color     bool         // Enable colorized output
-	Format    OutputFormat // text, json, custom
-	Formatter string       // Custom formatter string, only used if Format is FormatCustom
+	Enabled   bool           // Enable console logging
+	Color     bool           // Enable colorized output
+	ColorAuto bool           // With Color, only colorize when the console is a terminal
+	Format    OutputFormat   // text, json, custom, syslog, logfmt
+	Formatter string         // Custom formatter string, only used if Format is FormatCustom
+	WrapWidth int            // Soft-wrap custom format lines at this many columns, 0 disables wrapping
+	TimeZone  *time.Location // Time zone for {time} on the console, nil uses the global TimeZone
+
+	DualOutput bool // Write each record as a custom-format line followed by its JSON line
+	JSONIndent bool // Pretty-print JSON records with two-space indentation
 }
 
 type FileConfig struct {
-	Enabled       bool
-	Format        OutputFormat
-	Formatter     string // Custom formatter string, only used if Format is FormatCustom
-	Path          string // Path to the log file
-	MaxSizeMB     int    // Maximum size of the log file in megabytes
-	RetentionDays int    // Number of days to retain log files
+	Enabled          bool
+	Format           OutputFormat
+	Formatter        string         // Custom formatter string, only used if Format is FormatCustom
+	Path             string         // Path to the log file
+	TimeZone         *time.Location // Time zone for {time} in the file, nil uses the global TimeZone
+	MaxSizeMB        int            // Maximum size of the log file in megabytes
+	RetentionDays    int            // Number of days to retain log files
+	MaxAge           time.Duration  // Retention as a duration, overrides RetentionDays when positive
+	MaxTotalSizeMB   int            // Cap on the combined size of rotated files in megabytes, 0 disables it
+	RotationInterval time.Duration  // Rotate at every multiple of this interval since midnight, 0 disables
+
+	OnClosedWrite     func(p []byte) // Receives records written after Close instead of failing
+	RotationEvents    bool           // Emit a record to stderr for every rotation
+	AtomicRecords     bool           // Lock the file (flock) around each record for multi-process appends
+	Unbuffered        bool           // Write each record straight to the file, without a bufio.Writer
+	BufferSize        int            // Capacity of the file writer's buffer in bytes, 0 uses DefaultBufferSize
+	SyncWrites        bool           // Flush the buffer after every record; when false, flush every DefaultFlushInterval
+	Fsync             bool           // Commit the file to stable storage (fsync) after every flush and before rotation
+	RotationMarkers   bool           // Mark where a rotated file ends and its successor begins
+	ReliableRetention bool           // Age rotated files by a close time recorded in a .meta sidecar, not their modification time
+	JSONIndent        bool           // Pretty-print JSON records with two-space indentation
+	FileMode          os.FileMode    // Permissions of created log files, before the umask; 0 uses DefaultFileMode
+	DirMode           os.FileMode    // Permissions of created log directories, before the umask; 0 uses DefaultDirMode
+
+	compressor *compressor // Codec for rotated files set by WithCompressor, registered on validation
+
+	RoutingKey     string // Attribute whose value selects a per-value file, empty disables routing
+	RoutingPath    string // Path template for routed files, e.g. "logs/{tenant}.log"
+	RoutingMaxOpen int    // Maximum number of routed files kept open at once
+}
+
+type JournaldConfig struct {
+	Enabled    bool   // Enable logging to systemd-journald (Linux only)
+	SocketPath string // Path to the journald native socket, defaults to DefaultJournaldSocket
+}
+
+type WriterConfig struct {
+	Writer io.Writer    // Destination, closed by Logger.Close if it implements io.Closer
+	Format OutputFormat // text, json, custom, syslog or logfmt; custom uses the file formatter
+}
+
+type SyslogConfig struct {
+	Enabled  bool   // Enable logging to the local syslog daemon (not available on Windows and Plan 9)
+	Priority int    // Facility of every message, as a log/syslog Priority
+	Tag      string // Tag of every message, defaults to the program name
+}
+
+type NetworkConfig struct {
+	Enabled bool   // Ship records as JSON lines to a log collector
+	Network string // "tcp" or "udp", optionally with a 4 or 6 suffix
+	Address string // Collector address, e.g. "logstash:5000"
 }
 
 func DefaultConfig() *Config {
@@ -74,9 +274,17 @@ func DefaultConfig() *Config {
 			Path:          "",
 			MaxSizeMB:     DefaultMaxSizeMB,
 			RetentionDays: DefaultRetentionDays,
+			BufferSize:    DefaultBufferSize,
+			SyncWrites:    true,
+			FileMode:      DefaultFileMode,
+			DirMode:       DefaultDirMode,
 		},
 
 		ReplaceAttr: nil,
+
+		Colors:               ProfileDefault,
+		MaxPooledBuilderSize: DefaultMaxPooledBuilderSize,
+		PathExpansion:        true,
 	}
 }
 
@@ -89,12 +297,31 @@ func WithLevel(level slog.Level) Option {
 	}
 }
 
+// WithLevelVar makes the logger read its level threshold from v on every record, so code
+// holding the same *slog.LevelVar can change the effective level at any time.
+// It takes precedence over WithLevel.
+func WithLevelVar(v *slog.LevelVar) Option {
+	return func(c *Config) {
+		c.LevelVar = v
+	}
+}
+
+// leveler returns the level threshold handlers should consult
+func (c *Config) leveler() slog.Leveler {
+	if c.LevelVar != nil {
+		return c.LevelVar
+	}
+	return c.Level
+}
+
 func WithAddSource(addSource bool) Option {
 	return func(c *Config) {
 		c.AddSource = addSource
 	}
 }
 
+// WithTimeFormat sets the time.Format layout for {time} in custom formats.
+// Layouts may include the zone, as an offset ("-07:00", "-0700", "Z07:00") or a name ("MST").
 func WithTimeFormat(timeFormat string) Option {
 	return func(c *Config) {
 		c.TimeFormat = timeFormat
@@ -107,6 +334,29 @@ func WithTimeZone(timeZone *time.Location) Option {
 	}
 }
 
+// WithConsoleTimeZone formats {time} on the console in tz instead of the global time zone
+func WithConsoleTimeZone(tz *time.Location) Option {
+	return func(c *Config) {
+		c.Console.TimeZone = tz
+	}
+}
+
+// WithFileTimeZone formats {time} in the log file in tz instead of the global time zone,
+// e.g. UTC in files for correlation while the console shows local time
+func WithFileTimeZone(tz *time.Location) Option {
+	return func(c *Config) {
+		c.File.TimeZone = tz
+	}
+}
+
+// WithTimeZoneInTimestamp appends the zone offset (" -07:00") to the time format
+// unless it already contains a zone, so logs aggregated from several zones stay comparable
+func WithTimeZoneInTimestamp(enabled bool) Option {
+	return func(c *Config) {
+		c.TimeZoneInTimestamp = enabled
+	}
+}
+
 // WithReplaceAttr sets a function that can be used to replace attributes in log messages
 func WithReplaceAttr(replaceAttr func(groups []string, a slog.Attr) slog.Attr) Option {
 	return func(c *Config) {
@@ -114,31 +364,429 @@ func WithReplaceAttr(replaceAttr func(groups []string, a slog.Attr) slog.Attr) O
 	}
 }
 
+// WithStrictCustomFormat makes New fail when FormatCustom is selected with an empty formatter
+// or with a formatter lacking {message}. By default an empty formatter silently falls back to
+// DefaultFormatter and a formatter without {message} only logs a warning to stderr.
+func WithStrictCustomFormat(strict bool) Option {
+	return func(c *Config) {
+		c.StrictCustomFormat = strict
+	}
+}
+
+// WithLiteralTemplate renders custom formatters byte for byte as written: empty
+// placeholders become empty strings and the surrounding literal text, spaces included,
+// is kept verbatim. By default spaces around empty placeholders (e.g. {file} without
+// AddSource) are collapsed. Useful when embedding lines into another format.
+func WithLiteralTemplate(literal bool) Option {
+	return func(c *Config) {
+		c.LiteralTemplate = literal
+	}
+}
+
+// WithCanonicalOrder renders every custom format line in a fixed order, time, level,
+// source, message and then the attributes sorted by key (groups included), whatever the
+// configured formatter's placeholder arrangement. Output no longer depends on the order
+// attributes were added in, which keeps golden files in tests stable.
+func WithCanonicalOrder(canonical bool) Option {
+	return func(c *Config) {
+		c.CanonicalOrder = canonical
+	}
+}
+
+// WithMessagelessFormatter accepts custom formatters that lack a {message} placeholder
+// without the validation warning (or, with WithStrictCustomFormat, the error), for
+// placeholder-free or message-free lines that are intended.
+func WithMessagelessFormatter(allow bool) Option {
+	return func(c *Config) {
+		c.AllowMessagelessFormatter = allow
+	}
+}
+
+// WithStringerAsIs controls how the custom format renders fmt.Stringer attribute values.
+// By default String() is used; when enabled the Go value (%#v) is shown instead.
+// Errors are always rendered with Error(), even if they also implement fmt.Stringer.
+func WithStringerAsIs(asIs bool) Option {
+	return func(c *Config) {
+		c.StringerAsIs = asIs
+	}
+}
+
+// WithMaxPooledBuilderSize sets the largest capacity in bytes a formatting buffer may have to be reused.
+// Larger buffers (from very long lines) are dropped instead of returned to the pool.
+// Values <= 0 reset to the default (64KB).
+func WithMaxPooledBuilderSize(n int) Option {
+	return func(c *Config) {
+		c.MaxPooledBuilderSize = n
+	}
+}
+
+// WithOnRecord sets a callback invoked after each record is successfully written by a custom
+// format destination, with the record level and the number of bytes written. It is called once
+// per destination and runs on the logging hot path, so it must be cheap and must not block.
+func WithOnRecord(fn func(level slog.Level, bytesWritten int)) Option {
+	return func(c *Config) {
+		c.OnRecord = fn
+	}
+}
+
+// WithOnError sets a callback invoked when a custom format destination fails to write a record,
+// with the write error and the record, so the application can alert or fall back. slog drops
+// handler errors, so without it failed writes go unnoticed. The callback may log through the same
+// logger: failures of its own records, i.e. on the goroutine running it, are not reported again,
+// so a broken sink cannot recurse. Failures on other goroutines still are, possibly concurrently.
+func WithOnError(fn func(err error, r slog.Record)) Option {
+	return func(c *Config) {
+		c.OnError = fn
+	}
+}
+
+// WithErrorHandler sets a callback invoked with every write failure of a destination, in any
+// format. Beyond the records that WithOnError sees, it receives the errors of log files flushing
+// their buffer or rotating in the background, which no record is attached to. It is best effort
+// and not re-entered: failures of records it logs itself through the same logger are dropped,
+// while those of other goroutines are still reported, possibly concurrently.
+func WithErrorHandler(fn func(err error)) Option {
+	return func(c *Config) {
+		c.ErrorHandler = fn
+	}
+}
+
+// WithSelfProfiling measures how long the logger takes to handle each record, formatting and
+// writing to every destination included, and exposes the totals and a moving average through
+// Logger.Stats. Time spent in the OnRecord and OnError callbacks is left out. Off by default,
+// as reading the clock twice per record has a cost of its own.
+func WithSelfProfiling(enabled bool) Option {
+	return func(c *Config) {
+		c.SelfProfiling = enabled
+	}
+}
+
+// reportWriteError passes a failure to write r to OnError and ErrorHandler, unless it
+// happened while the same goroutine was already running them
+func (c *Config) reportWriteError(err error, r slog.Record) {
+	c.reportError(err)
+	if c.OnError == nil {
+		return
+	}
+	c.onErrorActive.run(func() { c.OnError(err, r) })
+}
+
+// reportError passes a write failure to ErrorHandler unless the calling goroutine is already running it
+func (c *Config) reportError(err error) {
+	if c.ErrorHandler == nil {
+		return
+	}
+	c.errorHandlerActive.run(func() { c.ErrorHandler(err) })
+}
+
+// callbackGuard keeps an error callback from being re-entered by the records it logs itself.
+// It tracks the goroutines running the callback, so failures on other goroutines are still
+// reported meanwhile.
+type callbackGuard struct {
+	mu     sync.Mutex
+	active map[string]bool // IDs of the goroutines inside the callback
+}
+
+// run calls fn unless the calling goroutine is already inside run
+func (g *callbackGuard) run(fn func()) {
+	id := goroutineID()
+	g.mu.Lock()
+	if g.active[id] {
+		g.mu.Unlock()
+		return
+	}
+	if g.active == nil {
+		g.active = make(map[string]bool)
+	}
+	g.active[id] = true
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.active, id)
+		g.mu.Unlock()
+	}()
+	fn()
+}
+
+// hasErrorCallback reports whether write failures are reported at all
+func (c *Config) hasErrorCallback() bool {
+	return c.OnError != nil || c.ErrorHandler != nil
+}
+
+// WithContextExtractor adds the attributes extract returns for a record's context to the
+// record, e.g. a trace_id stored by middleware, so slog.InfoContext(ctx, ...) picks them up
+// automatically. Extracted attributes go through ReplaceAttr like any other, so they can be
+// masked. extract runs for every record, with context.Background() for calls without a context.
+// It can be applied several times, and combined with WithTraceIDKey: all extractors run in order.
+func WithContextExtractor(extract func(ctx context.Context) []slog.Attr) Option {
+	return func(c *Config) {
+		c.addContextExtractor(extract)
+	}
+}
+
+// WithTraceIDKey logs the context value stored under key, e.g. by tracing middleware, as the
+// attribute attrName on every record logged with that context, such as trace_id=abc.
+// The attribute is omitted when the context holds no value (or an empty string) for key.
+// It is a shorthand for WithContextExtractor.
+func WithTraceIDKey(key any, attrName string) Option {
+	return func(c *Config) {
+		c.addContextExtractor(func(ctx context.Context) []slog.Attr {
+			v := ctx.Value(key)
+			if v == nil || v == "" {
+				return nil
+			}
+			return []slog.Attr{slog.Any(attrName, v)}
+		})
+	}
+}
+
+// addContextExtractor chains extract after the extractors already configured
+func (c *Config) addContextExtractor(extract func(ctx context.Context) []slog.Attr) {
+	if extract == nil {
+		return
+	}
+	prev := c.ContextExtractor
+	if prev == nil {
+		c.ContextExtractor = extract
+		return
+	}
+	c.ContextExtractor = func(ctx context.Context) []slog.Attr {
+		return append(prev(ctx), extract(ctx)...)
+	}
+}
+
+// WithEntryID adds a unique log_id attribute to every record.
+// The same ID is shared by all destinations so a line can be correlated across them.
+func WithEntryID(enabled bool) Option {
+	return func(c *Config) {
+		c.EntryID = enabled
+	}
+}
+
+// WithIDGenerator sets the function used to generate log_id values for WithEntryID
+func WithIDGenerator(generator func() string) Option {
+	return func(c *Config) {
+		c.IDGenerator = generator
+	}
+}
+
+// WithPackageAttr adds a pkg attribute with the import path of the caller's package,
+// e.g. "example.com/app/internal/db", to every record when AddSource is enabled, so
+// records can be filtered by package. The custom format can also use the {pkg} placeholder.
+func WithPackageAttr(enabled bool) Option {
+	return func(c *Config) {
+		c.PackageAttr = enabled
+	}
+}
+
+// WithElapsedAttr adds an elapsed_ms attribute to every record with the milliseconds
+// since the logger was created. The custom format can also use the {elapsed} placeholder.
+func WithElapsedAttr(enabled bool) Option {
+	return func(c *Config) {
+		c.ElapsedAttr = enabled
+	}
+}
+
+// WithHeartbeat emits an Info record with msg every interval, carrying the current
+// goroutine count and heap usage, to help detect silent hangs. It is stopped by Close.
+func WithHeartbeat(interval time.Duration, msg string) Option {
+	return func(c *Config) {
+		c.HeartbeatInterval = interval
+		c.HeartbeatMessage = msg
+	}
+}
+
+// WithSuppressionReport logs a summary of the records dropped in the last interval,
+// e.g. by WithLevelSampling, with a counter per reason. The report goes to the internal
+// stderr logger rather than the configured destinations, and intervals without drops are
+// skipped. Counts since the last report are emitted on Close.
+func WithSuppressionReport(interval time.Duration) Option {
+	return func(c *Config) {
+		c.SuppressionReportInterval = interval
+	}
+}
+
+// WithStackDedup abbreviates repeated stack traces: the first record carrying a given
+// "stack" attribute emits it in full along with a short stack_id hash, and identical stacks
+// within window only carry the hash. Keeps error storms readable.
+func WithStackDedup(window time.Duration) Option {
+	return func(c *Config) {
+		c.StackDedupWindow = window
+	}
+}
+
+// WithSourceFormat sets how {file} shows the source file: SourceShort (the default, just the
+// file name), SourceFull (the full path) or SourcePackage (the file's directory and name,
+// which tells apart same-named files of a large repository). The function and line follow as
+// before. The JSON and text formats always report the full path.
+func WithSourceFormat(mode SourceFormat) Option {
+	return func(c *Config) {
+		c.SourceFormat = mode
+	}
+}
+
+// WithCallerSkip reports the source (and the start of WithStackTrace stacks) n frames above
+// the function calling the logger, like zap's AddCallerSkip. Use 1 when all logging goes
+// through a helper such as logError(msg), so records point at the helper's caller rather
+// than the helper. It applies to every format, since the record's PC itself is moved, and has
+// no effect without WithAddSource or WithStackTrace.
+func WithCallerSkip(n int) Option {
+	return func(c *Config) {
+		c.CallerSkip = n
+	}
+}
+
+// WithStackTrace adds the stack of the logging goroutine under StackKey to every record at
+// minLevel or above, e.g. slog.LevelError, one "function\n\tfile:line" entry per frame
+// starting at the caller. The stack is a single string attribute in every format, and only
+// qualifying records pay for capturing it. Records that already carry a stack, including one
+// bound with With, keep theirs; records emitted away from a logging call, such as WithDedup
+// summaries, get none. Combine with WithStackDedup to abbreviate stacks that repeat.
+func WithStackTrace(minLevel slog.Level) Option {
+	return func(c *Config) {
+		c.StackTraceLevel = minLevel
+	}
+}
+
+// WithLevelSampling keeps only 1 in N records for each listed level, e.g.
+// map[slog.Level]int{slog.LevelInfo: 10, slog.LevelDebug: 100}.
+// Rates are matched against the exact record level; 0, 1 or absent levels keep all records.
+func WithLevelSampling(rates map[slog.Level]int) Option {
+	return func(c *Config) {
+		c.LevelSampling = maps.Clone(rates)
+	}
+}
+
+// WithSampling caps the volume of repeated records, e.g. from a hot loop during an incident:
+// within each Tick, the first Thereafter records with a given level and message are kept and
+// the rest are dropped. When the window ends a single "dropped N messages" record at the same
+// level reports them, with the original message under RepeatedMessageKey. Summaries of windows
+// still open are emitted on Close. Records with distinct messages are sampled independently.
+func WithSampling(sc SamplingConfig) Option {
+	return func(c *Config) {
+		c.Sampling = sc
+	}
+}
+
+// WithDedup collapses identical consecutive records like classic syslog: when a record with
+// the same level, message and attributes as the previous one arrives within window of its
+// first occurrence, it is counted instead of written. A "last message repeated N times"
+// record at the same level follows once a different record arrives, the window elapses
+// or the logger is closed.
+func WithDedup(window time.Duration) Option {
+	return func(c *Config) {
+		c.DedupWindow = window
+	}
+}
+
+// WithMessageKey emits the log message under the given key.
+// JSON and text output use it instead of "msg"; in the custom format the {message}
+// placeholder renders as key=message instead of the bare message text.
+// ReplaceAttr still sees the message under slog.MessageKey.
+func WithMessageKey(key string) Option {
+	return func(c *Config) {
+		c.MessageKey = key
+	}
+}
+
+// WithBadKeyName renames the "!BADKEY" attribute slog creates for a value passed without
+// a key (e.g. an odd number of args to Info), so malformed call sites read e.g. extra=value
+func WithBadKeyName(name string) Option {
+	return func(c *Config) {
+		c.BadKeyName = name
+	}
+}
+
+// WithDropBadKeys removes "!BADKEY" attributes instead of logging them. It wins over WithBadKeyName.
+func WithDropBadKeys(drop bool) Option {
+	return func(c *Config) {
+		c.DropBadKeys = drop
+	}
+}
+
 func WithConsole(enabled bool) Option {
 	return func(c *Config) {
 		c.Console.Enabled = enabled
 	}
 }
 
+// WithConsoleFormat sets the console format: FormatText, FormatJSON, FormatCustom, FormatSyslog (RFC5424) or FormatLogfmt
 func WithConsoleFormat(format OutputFormat) Option {
 	return func(c *Config) {
 		c.Console.Format = format
 	}
 }
 
+// WithConsoleColor turns console colors on or off unconditionally, overriding WithColorAuto
 func WithConsoleColor(enabled bool) Option {
 	return func(c *Config) {
 		c.Console.Color = enabled
+		c.Console.ColorAuto = false
+	}
+}
+
+// WithColorAuto colors the console only when stderr is a terminal, so redirecting it to a
+// file or pipe doesn't capture ANSI codes. A later WithConsoleColor overrides it.
+func WithColorAuto() Option {
+	return func(c *Config) {
+		c.Console.Color = true
+		c.Console.ColorAuto = true
+	}
+}
+
+// WithWrapWidth soft-wraps console lines in the custom format at n visible columns,
+// breaking at spaces and indenting continuation lines. ANSI color codes don't count towards
+// the width. 0 disables wrapping; file output is never wrapped.
+func WithWrapWidth(n int) Option {
+	return func(c *Config) {
+		c.Console.WrapWidth = n
+	}
+}
+
+// WithJSONIndent pretty-prints the console's JSON records (FormatJSON and the JSON half of
+// WithDualOutput) with two-space indentation, for local development. Each record still ends
+// with a newline after its closing brace. Files stay compact, see WithFileJSONIndent.
+func WithJSONIndent(enabled bool) Option {
+	return func(c *Config) {
+		c.Console.JSONIndent = enabled
+	}
+}
+
+// WithFileJSONIndent pretty-prints the JSON records of log files and WithWriter destinations.
+// Most aggregation pipelines expect one record per line, so keep it off when files are shipped.
+func WithFileJSONIndent(enabled bool) Option {
+	return func(c *Config) {
+		c.File.JSONIndent = enabled
+	}
+}
+
+// WithDualOutput writes each console record twice: a human line rendered with
+// humanTemplate (the console formatter is kept when empty), immediately followed by
+// the same record as JSON. Intended for local development.
+func WithDualOutput(humanTemplate string) Option {
+	return func(c *Config) {
+		c.Console.DualOutput = true
+		c.Console.Format = FormatCustom
+		if humanTemplate != "" {
+			c.Console.Formatter = humanTemplate
+		}
 	}
 }
 
-// WithConsoleFormatter sets the console formatter for logging, and automatically sets the format to FormatCustom
+// WithConsoleFormatter sets the console formatter for logging, and automatically sets the format to FormatCustom.
+// It overrides any format set earlier; applying a non-custom format afterwards is rejected by validation.
 // The formatter string can contain the following placeholders:
 // - {time}: The timestamp of the log message
 // - {level}: The log level of the message
 // - {message}: The log message
 // - {file}: The source file where the log message was generated
 // - {attrs}: Any additional attributes associated with the log message
+// - {elapsed}: Milliseconds since the logger was created, e.g. "1234ms"
+// - {pkg}: The import path of the caller's package, when AddSource is enabled
+// - {pid}: The process ID
+// - {hostname}: The machine's host name, "unknown" if it cannot be determined
+// - {goroutine}: The ID of the logging goroutine; read from a stack trace per record, which is costly
 // For example: "{time} [{level}] {file} {message} {attrs}"
 func WithConsoleFormatter(formatter string) Option {
 	return func(c *Config) {
@@ -160,6 +808,16 @@ func WithFilePath(path string) Option {
 	}
 }
 
+// WithPathExpansion controls whether file paths expand a leading "~" to the home directory
+// and $VAR or ${VAR} references to environment variables (enabled by default).
+// With expansion, referencing an unset variable is an error rather than an empty string.
+func WithPathExpansion(enabled bool) Option {
+	return func(c *Config) {
+		c.PathExpansion = enabled
+	}
+}
+
+// WithFileFormat sets the file format: FormatText, FormatJSON, FormatCustom, FormatSyslog (RFC5424) or FormatLogfmt
 func WithFileFormat(format OutputFormat) Option {
 	return func(c *Config) {
 		c.File.Format = format
@@ -173,6 +831,58 @@ func WithFileFormatter(formatter string) Option {
 	}
 }
 
+// WithJournald enables logging to systemd-journald using its native protocol.
+// Attributes become uppercased journald fields and levels map to journald priorities.
+// Creating the logger fails on non-Linux platforms or when the journald socket is unavailable.
+func WithJournald() Option {
+	return func(c *Config) {
+		c.Journald.Enabled = true
+	}
+}
+
+// WithFileOutput adds another log file, e.g. errors only in error.log next to everything in
+// app.log. Each output has its own path, format, rotation size, retention and minimum level,
+// and rotates independently; the other file settings (formatter, buffering, compression, ...)
+// are shared with the main file. WithFilePath and the other file options keep configuring the
+// main file. May be applied several times, but every output needs a path of its own.
+func WithFileOutput(out FileOutput) Option {
+	return func(c *Config) {
+		c.FileOutputs = append(c.FileOutputs, out)
+	}
+}
+
+// WithWriter adds w as a destination next to the console, file and the others, e.g. an
+// in-memory ring buffer, a pipe or a test buffer. format selects the handler as for the file;
+// FormatCustom renders with the file formatter and time zone, without color. If w also implements
+// io.Closer it is closed by Logger.Close, so don't pass a writer the application still uses,
+// like os.Stdout, unless that is intended. May be applied several times to add several writers.
+func WithWriter(w io.Writer, format OutputFormat) Option {
+	return func(c *Config) {
+		c.Writers = append(c.Writers, WriterConfig{Writer: w, Format: format})
+	}
+}
+
+// WithNetwork ships records as JSON lines to a log collector such as Logstash or Fluentd.
+// network is "tcp" or "udp"; over UDP each record is sent as one datagram. Creating the
+// logger fails if the collector can't be dialed. When a TCP write fails the record is lost
+// and the connection is dropped, and the next record dials the collector again.
+func WithNetwork(network, address string) Option {
+	return func(c *Config) {
+		c.Network.Enabled = true
+		c.Network.Network = network
+		c.Network.Address = address
+	}
+}
+
+// normalizeMaxSizeMB applies the WithMaxSizeMB rules to a rotation size: 0 disables
+// rotation and negative values are reset to DefaultMaxSizeMB
+func normalizeMaxSizeMB(maxSizeMB int) int {
+	if maxSizeMB < 0 {
+		return DefaultMaxSizeMB
+	}
+	return maxSizeMB
+}
+
 // WithMaxSizeMB sets the maximum size of the log file in megabytes.
 // Set to 0 to disable file rotation. Negative values will be reset to the default.
 func WithMaxSizeMB(maxSizeMB int) Option {
@@ -181,6 +891,25 @@ func WithMaxSizeMB(maxSizeMB int) Option {
 	}
 }
 
+// WithRotationInterval rotates the log file on a schedule in addition to the size limit,
+// e.g. time.Hour starts a fresh file at the top of every hour and 24*time.Hour at midnight.
+// Whichever of size and interval triggers first rotates the file, and the schedule continues
+// from there. Intervals with nothing logged don't produce a rotated file. 0 disables it.
+func WithRotationInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.File.RotationInterval = interval
+	}
+}
+
+// WithMaxTotalSizeMB caps the combined size of rotated log files. After age-based pruning,
+// the oldest rotated files are deleted until the rest fit; the current file is never counted
+// or deleted. It is enforced after every rotation and at the daily cleanup. 0 disables it.
+func WithMaxTotalSizeMB(mb int) Option {
+	return func(c *Config) {
+		c.File.MaxTotalSizeMB = mb
+	}
+}
+
 // WithRetentionDays sets the number of days to retain log files
 func WithRetentionDays(retentionDays int) Option {
 	return func(c *Config) {
@@ -188,6 +917,241 @@ func WithRetentionDays(retentionDays int) Option {
 	}
 }
 
+// WithMaxAge sets how long rotated log files are retained, for retention finer than whole days.
+// When positive it takes precedence over WithRetentionDays. A max age under a day also runs the
+// cleanup every maxAge instead of daily.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(c *Config) {
+		c.File.MaxAge = maxAge
+	}
+}
+
+// WithOnClosedWrite sets a callback that receives records written to the file after the logger
+// was closed (e.g. to route them to stderr). Without it such writes fail, and since slog
+// discards handler errors the records are silently lost.
+func WithOnClosedWrite(fn func(p []byte)) Option {
+	return func(c *Config) {
+		c.File.OnClosedWrite = fn
+	}
+}
+
+// WithColorDecider colors each record by a custom rule instead of strictly by level,
+// e.g. yellow for records with slow=true. The returned ANSI code (such as "\033[93m")
+// colors the level and message; an empty string keeps the level-based colors.
+// It is only consulted when color output is enabled.
+func WithColorDecider(decide func(r slog.Record) string) Option {
+	return func(c *Config) {
+		c.ColorDecider = decide
+	}
+}
+
+// WithColorProfile selects the colors used by the custom format, e.g. ProfileSolarized,
+// ProfileMonochrome (bold and dim instead of color) or ProfileHighContrast.
+// It has no effect when color output is disabled.
+func WithColorProfile(profile ColorProfile) Option {
+	return func(c *Config) {
+		c.Colors = profile
+	}
+}
+
+// WithColorTheme replaces the level colors of the current color profile with the theme's
+// ANSI codes, e.g. for light terminals or color vision deficiencies, keeping its other styles.
+// A later WithColorProfile replaces them again. Start from DefaultColorTheme to change single
+// levels. Every code must be non-empty.
+func WithColorTheme(theme ColorTheme) Option {
+	return func(c *Config) {
+		if err := theme.validate(); err != nil {
+			if c.optionErr == nil {
+				c.optionErr = err
+			}
+			return
+		}
+		c.Colors = *c.colorProfile()
+		c.Colors.Debug = theme.Debug
+		c.Colors.Info = theme.Info
+		c.Colors.Warn = theme.Warn
+		c.Colors.Error = theme.Error
+		c.Colors.Critical = theme.Critical
+	}
+}
+
+// WithErrorHighlightLevel sets the lowest level at which the custom format highlights the
+// "error" attribute (default Warn). Records at Error and above use the profile's ErrorKey and
+// ErrorValue styles, lower ones WarnErrorKey and WarnErrorValue (faint yellow by default).
+func WithErrorHighlightLevel(level slog.Level) Option {
+	return func(c *Config) {
+		c.ErrorHighlightLevel = level
+	}
+}
+
+// errorHighlightLevel returns the configured ErrorHighlightLevel, defaulting to Warn
+func (c *Config) errorHighlightLevel() slog.Level {
+	if c.ErrorHighlightLevel == nil {
+		return slog.LevelWarn
+	}
+	return c.ErrorHighlightLevel.Level()
+}
+
+// WithGroupPrefixOnce makes the custom format emit the group path once in front of the
+// attributes, e.g. "Database.MySQL: host=localhost port=3306", instead of prefixing every
+// key ("Database.MySQL.host=localhost ..."), which remains the default to match slog.
+func WithGroupPrefixOnce(enabled bool) Option {
+	return func(c *Config) {
+		c.GroupPrefixOnce = enabled
+	}
+}
+
+// WithMaxLineBytes caps each fully rendered custom-format line at n bytes, excluding the
+// trailing newline. Longer lines are cut on a rune boundary and end with "…", protecting
+// sinks with a hard line limit (e.g. syslog). 0 disables the limit.
+func WithMaxLineBytes(n int) Option {
+	return func(c *Config) {
+		c.MaxLineBytes = n
+	}
+}
+
+// WithUngroupedAttrs keeps the listed attribute keys at the root in the custom format,
+// without the group prefix added by WithGroup, e.g. a "service" identity field that
+// shouldn't move under request groups.
+func WithUngroupedAttrs(keys ...string) Option {
+	return func(c *Config) {
+		c.UngroupedAttrs = slices.Clone(keys)
+	}
+}
+
+// WithEncoder bypasses the text, JSON and custom template rendering for console and file
+// output and writes the bytes returned by encoder as is. The encoder must include its own
+// record separator (e.g. a trailing newline). ReplaceAttr and the color settings don't apply.
+func WithEncoder(encoder func(r slog.Record, groups []string) ([]byte, error)) Option {
+	return func(c *Config) {
+		c.Encoder = encoder
+	}
+}
+
+// WithRotationMarkers writes a sentinel line at the end of each rotated file and at the top
+// of the new one, naming the file the log continues in or from, so tools can stitch them
+// back together. With FormatJSON the markers are JSON objects with "continued_in" or
+// "continued_from" keys; otherwise they read "--- rotated at <time>, continued in <file> ---".
+func WithRotationMarkers(enabled bool) Option {
+	return func(c *Config) {
+		c.File.RotationMarkers = enabled
+	}
+}
+
+// WithReliableRetention records when each rotated file was closed in a small sidecar
+// file (<rotated name>.meta) and ages files by that time for WithRetentionDays, WithMaxAge
+// and WithMaxTotalSizeMB, so backup or sync tools touching the modification time don't
+// keep old files alive or expire recent ones. Files without a sidecar fall back to their
+// modification time; sidecars are removed with their files.
+func WithReliableRetention(enabled bool) Option {
+	return func(c *Config) {
+		c.File.ReliableRetention = enabled
+	}
+}
+
+// WithCompressor compresses each rotated file with compress, e.g. an lz4, snappy or xz
+// encoder, naming the result after the rotated file plus ext (e.g. ".lz4"); the uncompressed
+// file is then removed. Compression runs after rotation, without blocking writers, and Close
+// waits for it. The codec is registered under name for the whole process, so retention cleanup
+// and RotatedFiles recognize its extension. OpenLogFile only decompresses .gz files.
+func WithCompressor(name string, compress func(dst io.Writer, src io.Reader) error, ext string) Option {
+	return func(c *Config) {
+		c.File.compressor = &compressor{name: name, compress: compress, ext: ext}
+	}
+}
+
+// WithBufferSize sets the capacity in bytes of the buffer in front of the log file
+// (default DefaultBufferSize). Records larger than the buffer are written through directly.
+// 0 uses the default and negative values are rejected; use WithUnbuffered to drop the buffer.
+func WithBufferSize(bytes int) Option {
+	return func(c *Config) {
+		c.File.BufferSize = bytes
+	}
+}
+
+// WithSyncWrites controls whether the file buffer is flushed after every record (the default).
+// With false, the buffer is only flushed when full, every DefaultFlushInterval, on rotation
+// and on Close, which raises throughput under load. A crash may then lose the buffered records.
+func WithSyncWrites(sync bool) Option {
+	return func(c *Config) {
+		c.File.SyncWrites = sync
+	}
+}
+
+// WithFsync makes the file writer fsync the log file whenever it flushes, i.e. after every
+// record with the default sync writes, and before a rotated file is closed. Records then
+// survive a power loss, not only a crash of the process, as audit logs may require. Off by
+// default: an fsync per record costs far more than the write itself (see BenchmarkFsync).
+func WithFsync(enabled bool) Option {
+	return func(c *Config) {
+		c.File.Fsync = enabled
+	}
+}
+
+// WithFileMode sets the permissions of the log files the logger creates, e.g. 0o600 so only
+// the service user can read them (default DefaultFileMode). Rotated files keep the mode of
+// the file they were, and existing files are left as they are. The process umask still applies.
+func WithFileMode(mode os.FileMode) Option {
+	return func(c *Config) {
+		c.File.FileMode = mode
+	}
+}
+
+// WithDirMode sets the permissions of the log directories the logger creates, e.g. 0o700
+// (default DefaultDirMode). Existing directories are left as they are.
+func WithDirMode(mode os.FileMode) Option {
+	return func(c *Config) {
+		c.File.DirMode = mode
+	}
+}
+
+// WithUnbuffered makes the file writer issue each record as a single write(2) on the
+// file, with no bufio.Writer in between. Records already reach the OS on every write,
+// so this mainly removes the buffer's copy and memory for low-volume critical logs.
+func WithUnbuffered(unbuffered bool) Option {
+	return func(c *Config) {
+		c.File.Unbuffered = unbuffered
+	}
+}
+
+// WithAtomicRecords takes an exclusive advisory lock (flock) on the log file around each
+// record, so records from several processes appending to the same file never interleave,
+// whatever their size. Each write then costs two extra system calls and waits for other
+// writers holding the lock. Without flock support (e.g. Windows) records rely on O_APPEND
+// alone, which is only guaranteed atomic for small writes.
+func WithAtomicRecords(enabled bool) Option {
+	return func(c *Config) {
+		c.File.AtomicRecords = enabled
+	}
+}
+
+// WithRoutingKey routes each record to a file chosen by the value of the attrKey attribute.
+// The pathTemplate must contain "{attrKey}", e.g. WithRoutingKey("tenant", "logs/{tenant}.log").
+// Records lacking the attribute go to the regular file path. Routed files share the file
+// format and rotation settings; they are opened lazily and idle ones are closed.
+func WithRoutingKey(attrKey string, pathTemplate string) Option {
+	return func(c *Config) {
+		c.File.RoutingKey = attrKey
+		c.File.RoutingPath = pathTemplate
+	}
+}
+
+// WithRoutingMaxOpen sets the maximum number of routed files kept open at once.
+// The least recently used file is closed when the limit is reached.
+func WithRoutingMaxOpen(n int) Option {
+	return func(c *Config) {
+		c.File.RoutingMaxOpen = n
+	}
+}
+
+// WithRotationEvents emits an Info record to stderr each time the log file rotates,
+// with the old and new file names and the size at rotation
+func WithRotationEvents(enabled bool) Option {
+	return func(c *Config) {
+		c.File.RotationEvents = enabled
+	}
+}
+
 // WithFormat sets the format of the log message for both console and file logging
 func WithFormat(format OutputFormat) Option {
 	return func(c *Config) {
@@ -208,6 +1172,13 @@ func WithFormatter(formatter string) Option {
 }
 
 func validateConfig(cfg *Config) error {
+	if cfg.envErr != nil {
+		return cfg.envErr
+	}
+	if cfg.optionErr != nil {
+		return cfg.optionErr
+	}
+
 	// Validate level
 	if cfg.Level < slog.LevelDebug-4 || cfg.Level > slog.LevelError+4 {
 		return fmt.Errorf("invalid log level: %v (should be within reasonable range)", cfg.Level)
@@ -217,6 +1188,9 @@ func validateConfig(cfg *Config) error {
 	if cfg.TimeFormat == "" {
 		cfg.TimeFormat = DefaultTimeFormat
 	}
+	if cfg.TimeZoneInTimestamp && !layoutHasZone(cfg.TimeFormat) {
+		cfg.TimeFormat += " -07:00"
+	}
 
 	// Validate time zone
 	if cfg.TimeZone == nil {
@@ -230,47 +1204,194 @@ func validateConfig(cfg *Config) error {
 	if cfg.File.Format == "" {
 		cfg.File.Format = DefaultFormat
 	}
+	for i := range cfg.Writers {
+		if cfg.Writers[i].Writer == nil {
+			return fmt.Errorf("writer destination %d is nil", i)
+		}
+		if cfg.Writers[i].Format == "" {
+			cfg.Writers[i].Format = DefaultFormat
+		}
+		if !isValidFormat(cfg.Writers[i].Format) {
+			return fmt.Errorf("unsupported writer format: %s (must be one of: text, json, custom, syslog, logfmt)", cfg.Writers[i].Format)
+		}
+	}
 
 	// Validate format
 	if !isValidFormat(cfg.Console.Format) {
-		return fmt.Errorf("unsupported console format: %s (must be one of: text, json, custom)", cfg.Console.Format)
+		return fmt.Errorf("unsupported console format: %s (must be one of: text, json, custom, syslog, logfmt)", cfg.Console.Format)
 	}
 	if !isValidFormat(cfg.File.Format) {
-		return fmt.Errorf("unsupported file format: %s (must be one of: text, json, custom)", cfg.File.Format)
+		return fmt.Errorf("unsupported file format: %s (must be one of: text, json, custom, syslog, logfmt)", cfg.File.Format)
+	}
+
+	// Reject non-custom formats combined with a custom formatter, which would be silently ignored.
+	// WithConsoleFormatter/WithFileFormatter/WithFormatter switch the format to FormatCustom, so this
+	// only triggers when a non-custom format is applied after a formatter.
+	if cfg.Console.Enabled && hasFormatterConflict(cfg.Console.Format, cfg.Console.Formatter) {
+		return fmt.Errorf("console formatter %q conflicts with console format %s (formatter is only used with format custom)", cfg.Console.Formatter, cfg.Console.Format)
+	}
+	if cfg.File.Enabled && hasFormatterConflict(cfg.File.Format, cfg.File.Formatter) {
+		return fmt.Errorf("file formatter %q conflicts with file format %s (formatter is only used with format custom)", cfg.File.Formatter, cfg.File.Format)
+	}
+
+	if cfg.MaxPooledBuilderSize <= 0 {
+		cfg.MaxPooledBuilderSize = DefaultMaxPooledBuilderSize
+	}
+
+	if cfg.Console.WrapWidth < 0 {
+		return fmt.Errorf("invalid console wrap width: %d (must be >= 0)", cfg.Console.WrapWidth)
+	}
+
+	if cfg.MaxLineBytes < 0 {
+		return fmt.Errorf("invalid max line bytes: %d (must be >= 0)", cfg.MaxLineBytes)
+	}
+
+	if cfg.StackDedupWindow < 0 {
+		return fmt.Errorf("invalid stack dedup window: %v (must be >= 0)", cfg.StackDedupWindow)
+	}
+
+	if cfg.HeartbeatInterval < 0 {
+		return fmt.Errorf("invalid heartbeat interval: %v (must be >= 0)", cfg.HeartbeatInterval)
+	}
+	if cfg.SuppressionReportInterval < 0 {
+		return fmt.Errorf("invalid suppression report interval: %v (must be >= 0)", cfg.SuppressionReportInterval)
+	}
+
+	// Validate sampling rates
+	for level, rate := range cfg.LevelSampling {
+		if rate < 0 {
+			return fmt.Errorf("invalid sampling rate %d for level %v (must be >= 0)", rate, level)
+		}
+	}
+	if cfg.Sampling.Thereafter < 0 {
+		return fmt.Errorf("invalid sampling thereafter: %d (must be >= 0)", cfg.Sampling.Thereafter)
+	}
+	if cfg.Sampling.Thereafter > 0 && cfg.Sampling.Tick <= 0 {
+		return fmt.Errorf("invalid sampling tick: %v (must be > 0)", cfg.Sampling.Tick)
+	}
+	switch cfg.SourceFormat {
+	case "", SourceShort, SourceFull, SourcePackage:
+	default:
+		return fmt.Errorf("unsupported source format: %s (must be one of: short, full, package)", cfg.SourceFormat)
+	}
+
+	if cfg.CallerSkip < 0 {
+		return fmt.Errorf("invalid caller skip: %d (must be >= 0)", cfg.CallerSkip)
+	}
+
+	if cfg.DedupWindow < 0 {
+		return fmt.Errorf("invalid dedup window: %v (must be >= 0)", cfg.DedupWindow)
+	}
+
+	// Validate permissions, shared by the file and the additional file outputs
+	if cfg.File.FileMode == 0 {
+		cfg.File.FileMode = DefaultFileMode
+	}
+	if cfg.File.DirMode == 0 {
+		cfg.File.DirMode = DefaultDirMode
+	}
+	if cfg.File.FileMode&^os.ModePerm != 0 || cfg.File.FileMode&0o200 == 0 {
+		return fmt.Errorf("invalid file mode: %#o (must be permission bits including owner write)", uint32(cfg.File.FileMode))
+	}
+	if cfg.File.DirMode&^os.ModePerm != 0 || cfg.File.DirMode&0o300 != 0o300 {
+		return fmt.Errorf("invalid dir mode: %#o (must be permission bits including owner write and execute)", uint32(cfg.File.DirMode))
 	}
 
 	// Validate file configuration
 	if cfg.File.Enabled {
 		if cfg.File.Path == "" {
-			return fmt.Errorf("file logging enabled but Path is empty")
+			return missingFilePathError(cfg)
 		}
 
-		// Create the log directory if it doesn't exist
-		dir := filepath.Dir(cfg.File.Path)
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			if err := os.MkdirAll(dir, 0755); err != nil {
-				return fmt.Errorf("unable to create log directory %s: %w", dir, err)
+		if cfg.PathExpansion {
+			path, err := expandPath(cfg.File.Path)
+			if err != nil {
+				return fmt.Errorf("invalid file path %q: %w", cfg.File.Path, err)
+			}
+			cfg.File.Path = path
+			if cfg.File.RoutingPath != "" {
+				if cfg.File.RoutingPath, err = expandPath(cfg.File.RoutingPath); err != nil {
+					return fmt.Errorf("invalid routing path: %w", err)
+				}
 			}
-		} else if err != nil {
-			return fmt.Errorf("error checking log directory %s: %w", dir, err)
 		}
 
-		// MaxSizeMB == 0 means disable rotation, negative values are reset to default
-		if cfg.File.MaxSizeMB < 0 {
-			cfg.File.MaxSizeMB = DefaultMaxSizeMB
+		if err := ensureLogDir(cfg.File.Path, cfg.File.DirMode); err != nil {
+			return err
 		}
 
+		cfg.File.MaxSizeMB = normalizeMaxSizeMB(cfg.File.MaxSizeMB)
+
 		if cfg.File.RetentionDays <= 0 {
 			cfg.File.RetentionDays = DefaultRetentionDays
 		}
+		if cfg.File.compressor != nil {
+			if err := registerCompressor(cfg.File.compressor); err != nil {
+				return err
+			}
+		}
+		if cfg.File.BufferSize < 0 {
+			return fmt.Errorf("buffer size must not be negative, got %d", cfg.File.BufferSize)
+		}
+		if cfg.File.MaxTotalSizeMB < 0 {
+			return fmt.Errorf("max total size must not be negative, got %d MB", cfg.File.MaxTotalSizeMB)
+		}
+		if cfg.File.RotationInterval < 0 {
+			return fmt.Errorf("rotation interval must not be negative, got %v", cfg.File.RotationInterval)
+		}
+		if cfg.File.MaxAge < 0 {
+			return fmt.Errorf("max age must not be negative, got %v", cfg.File.MaxAge)
+		}
+
+		if cfg.File.RoutingKey != "" {
+			if !strings.Contains(cfg.File.RoutingPath, "{"+cfg.File.RoutingKey+"}") {
+				return fmt.Errorf("routing path %q must contain {%s}", cfg.File.RoutingPath, cfg.File.RoutingKey)
+			}
+			if cfg.File.RoutingMaxOpen <= 0 {
+				cfg.File.RoutingMaxOpen = DefaultRoutingMaxOpen
+			}
+		}
+	} else if cfg.File.RoutingKey != "" {
+		return fmt.Errorf("routing key %q set but file logging is not enabled", cfg.File.RoutingKey)
+	}
+
+	for i := range cfg.FileOutputs {
+		if err := validateFileOutput(cfg, &cfg.FileOutputs[i]); err != nil {
+			return err
+		}
+	}
+	if err := checkFileOutputPaths(cfg); err != nil {
+		return err
+	}
+	if len(cfg.FileOutputs) > 0 && !cfg.File.Enabled && cfg.File.compressor != nil {
+		if err := registerCompressor(cfg.File.compressor); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Network.Enabled {
+		if !isValidNetwork(cfg.Network.Network) {
+			return fmt.Errorf("unsupported network: %q (must be one of: tcp, udp)", cfg.Network.Network)
+		}
+		if cfg.Network.Address == "" {
+			return fmt.Errorf("network logging is enabled but no address is set")
+		}
 	}
 
 	// Make sure at least one logging destination is enabled
-	if !cfg.Console.Enabled && !cfg.File.Enabled {
-		return fmt.Errorf("neither console nor file logging is enabled")
+	if !cfg.Console.Enabled && !cfg.File.Enabled && !cfg.Journald.Enabled && !cfg.Network.Enabled && !cfg.Syslog.Enabled && len(cfg.Writers) == 0 && len(cfg.FileOutputs) == 0 {
+		return noDestinationError(cfg)
 	}
 
 	// Set default formatter if custom format is selected but no formatter is provided
+	if cfg.StrictCustomFormat {
+		if cfg.Console.Enabled && cfg.Console.Format == FormatCustom && cfg.Console.Formatter == "" {
+			return fmt.Errorf("console format is custom but no formatter is set (strict custom format)")
+		}
+		if cfg.File.Enabled && cfg.File.Format == FormatCustom && cfg.File.Formatter == "" {
+			return fmt.Errorf("file format is custom but no formatter is set (strict custom format)")
+		}
+	}
 	if cfg.Console.Format == FormatCustom && cfg.Console.Formatter == "" {
 		cfg.Console.Formatter = DefaultFormatter
 	}
@@ -278,9 +1399,118 @@ func validateConfig(cfg *Config) error {
 		cfg.File.Formatter = DefaultFormatter
 	}
 
+	// A formatter without {message} renders every record without its message, which is almost always a mistake
+	if !cfg.AllowMessagelessFormatter && cfg.Encoder == nil {
+		if cfg.Console.Enabled && cfg.Console.Format == FormatCustom {
+			if err := checkMessagePlaceholder("console", cfg.Console.Formatter, cfg.StrictCustomFormat); err != nil {
+				return err
+			}
+		}
+		if cfg.File.Enabled && cfg.File.Format == FormatCustom {
+			if err := checkMessagePlaceholder("file", cfg.File.Formatter, cfg.StrictCustomFormat); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
+// missingFilePathError explains a file destination enabled without a path,
+// e.g. by WithFile(true) alone, and names the option that sets it
+func missingFilePathError(cfg *Config) error {
+	if cfg.File.RoutingKey != "" {
+		return fmt.Errorf("file logging is enabled but no path is set: routing by %q still needs WithFilePath(path) for records without the attribute", cfg.File.RoutingKey)
+	}
+	return fmt.Errorf("file logging is enabled but no path is set: call WithFilePath(path) to choose the log file")
+}
+
+// noDestinationError explains why no destination is enabled, guessing from the options
+// that were applied which one the user meant to enable
+func noDestinationError(cfg *Config) error {
+	const prefix = "neither console nor file logging is enabled"
+	// Format settings other than the defaults only matter for an enabled destination
+	formatSet := func(format OutputFormat, formatter string) bool {
+		return format == FormatJSON || format == FormatSyslog || format == FormatLogfmt || (formatter != "" && formatter != DefaultFormatter)
+	}
+	switch {
+	case cfg.File.Path != "":
+		// WithFilePath followed by WithFile(false)
+		return fmt.Errorf("%s: file path %q is set but file logging was disabled, call WithFile(true) or drop WithFile(false)", prefix, cfg.File.Path)
+	case formatSet(cfg.File.Format, cfg.File.Formatter):
+		return fmt.Errorf("%s: file format options are set but no file path, call WithFilePath(path) to enable file logging", prefix)
+	case formatSet(cfg.Console.Format, cfg.Console.Formatter):
+		return fmt.Errorf("%s: console format options are set but the console is disabled, call WithConsole(true)", prefix)
+	}
+	return fmt.Errorf("%s: call WithFilePath(path) to log to a file or WithConsole(true) to log to the console", prefix)
+}
+
+// checkMessagePlaceholder warns, or fails in strict mode, when formatter lacks {message}
+func checkMessagePlaceholder(destination, formatter string, strict bool) error {
+	if strings.Contains(formatter, PlaceholderMessage) {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%s formatter %q has no %s placeholder (strict custom format)", destination, formatter, PlaceholderMessage)
+	}
+	internalLogger.Warn("Formatter has no {message} placeholder, records will not include their message",
+		slog.String("destination", destination),
+		slog.String("formatter", formatter),
+	)
+	return nil
+}
+
+// hasFormatterConflict reports whether a non-default formatter is set alongside a non-custom format
+func hasFormatterConflict(format OutputFormat, formatter string) bool {
+	return format != FormatCustom && formatter != "" && formatter != DefaultFormatter
+}
+
 func isValidFormat(format OutputFormat) bool {
-	return format == FormatText || format == FormatJSON || format == FormatCustom
+	return format == FormatText || format == FormatJSON || format == FormatCustom || format == FormatSyslog ||
+		format == FormatLogfmt
+}
+
+// ensureLogDir creates the directory of the log file at path with mode if it doesn't exist
+func ensureLogDir(path string, mode os.FileMode) error {
+	dir := filepath.Dir(path)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, mode); err != nil {
+			return fmt.Errorf("unable to create log directory %s: %w", dir, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("error checking log directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+// expandPath expands a leading "~" to the user's home directory and $VAR or ${VAR}
+// references to environment variables. Unset variables are reported as errors so a
+// missing variable can't silently turn "$LOG_DIR/app.log" into "/app.log".
+func expandPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(os.PathSeparator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("unable to expand ~: %w", err)
+		}
+		path = home + path[1:]
+	}
+
+	var missing []string
+	path = os.Expand(path, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return path, nil
+}
+
+// layoutHasZone reports whether a time layout already renders the zone,
+// as a numeric offset (-07, -0700, -07:00, Z07...) or an abbreviation (MST)
+func layoutHasZone(layout string) bool {
+	return strings.Contains(layout, "-07") || strings.Contains(layout, "Z07") || strings.Contains(layout, "MST")
 }
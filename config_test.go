@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"bytes"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -143,6 +145,11 @@ func TestOptions(t *testing.T) {
 		t.Errorf("Expected RetentionDays to be %d, got %d", retentionDays, cfg.File.RetentionDays)
 	}
 
+	WithMaxAge(6 * time.Hour)(cfg)
+	if cfg.File.MaxAge != 6*time.Hour {
+		t.Errorf("Expected MaxAge to be 6h, got %v", cfg.File.MaxAge)
+	}
+
 	// Test compatibility methods
 	WithFormat(FormatText)(cfg)
 	if cfg.Console.Format != FormatText {
@@ -298,6 +305,32 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "file config with negative RotationInterval",
+			config: &Config{
+				Level: slog.LevelInfo,
+				File: FileConfig{
+					Enabled:          true,
+					Format:           FormatText,
+					Path:             filepath.Join(os.TempDir(), "test_negative_interval.log"),
+					RotationInterval: -time.Hour,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "file config with negative MaxAge",
+			config: &Config{
+				Level: slog.LevelInfo,
+				File: FileConfig{
+					Enabled: true,
+					Format:  FormatText,
+					Path:    filepath.Join(os.TempDir(), "test_negative_age.log"),
+					MaxAge:  -time.Hour,
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -357,6 +390,86 @@ func TestConfigValidationBehavior(t *testing.T) {
 	})
 }
 
+// TestFileModeValidation tests the defaults and rejected values of WithFileMode and WithDirMode
+func TestFileModeValidation(t *testing.T) {
+	cfg := &Config{Level: slog.LevelInfo, Console: ConsoleConfig{Enabled: true}}
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("validateConfig() failed: %v", err)
+	}
+	if cfg.File.FileMode != DefaultFileMode || cfg.File.DirMode != DefaultDirMode {
+		t.Errorf("Expected zero modes to use the defaults, got %v and %v", cfg.File.FileMode, cfg.File.DirMode)
+	}
+
+	for name, opt := range map[string]Option{
+		"file mode with type bits":      WithFileMode(os.ModeDir | 0o644),
+		"file mode without owner write": WithFileMode(0o444),
+		"dir mode without owner exec":   WithDirMode(0o600),
+		"dir mode with setuid":          WithDirMode(os.ModeSetuid | 0o755),
+	} {
+		if _, err := New(opt); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+// TestDestinationErrorMessages tests that a missing destination names the option to fix it
+func TestDestinationErrorMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{
+			name: "Nothing enabled",
+			opts: []Option{WithConsole(false)},
+			want: []string{"neither console nor file logging is enabled", "WithFilePath(path)", "WithConsole(true)"},
+		},
+		{
+			name: "File path set then disabled",
+			opts: []Option{WithConsole(false), WithFilePath("app.log"), WithFile(false)},
+			want: []string{`file path "app.log" is set`, "WithFile(true)"},
+		},
+		{
+			name: "File format without path",
+			opts: []Option{WithConsole(false), WithFileFormat(FormatJSON)},
+			want: []string{"file format options are set", "WithFilePath(path)"},
+		},
+		{
+			name: "Console formatter with console disabled",
+			opts: []Option{WithConsoleFormatter("{level} {message}"), WithConsole(false)},
+			want: []string{"console format options are set", "WithConsole(true)"},
+		},
+		{
+			name: "File enabled without path",
+			opts: []Option{WithFile(true)},
+			want: []string{"file logging is enabled but no path is set", "WithFilePath(path)"},
+		},
+		{
+			name: "Routing without fallback path",
+			opts: []Option{WithFile(true), WithRoutingKey("tenant", "logs/{tenant}.log")},
+			want: []string{`routing by "tenant"`, "WithFilePath(path)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+			err := validateConfig(cfg)
+			if err == nil {
+				t.Fatal("Expected a validation error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got %q", want, err)
+				}
+			}
+		})
+	}
+}
+
 // TestWithTimeZone tests the WithTimeZone option
 func TestWithTimeZone(t *testing.T) {
 	t.Run("Set custom timezone", func(t *testing.T) {
@@ -453,3 +566,248 @@ func TestWithReplaceAttr(t *testing.T) {
 		}
 	})
 }
+
+// TestFormatFormatterConflict tests validation of format/formatter combinations
+func TestFormatFormatterConflict(t *testing.T) {
+	applyAndValidate := func(opts ...Option) (*Config, error) {
+		cfg := DefaultConfig()
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		return cfg, validateConfig(cfg)
+	}
+
+	t.Run("Formatter after format overrides to custom", func(t *testing.T) {
+		cfg, err := applyAndValidate(
+			WithConsoleFormat(FormatJSON),
+			WithConsoleFormatter("{time} {message}"),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Console.Format != FormatCustom {
+			t.Errorf("Expected format to be overridden to %s, got %s", FormatCustom, cfg.Console.Format)
+		}
+	})
+
+	t.Run("Console format after formatter conflicts", func(t *testing.T) {
+		_, err := applyAndValidate(
+			WithConsoleFormatter("{time} {message}"),
+			WithConsoleFormat(FormatJSON),
+		)
+		if err == nil {
+			t.Fatal("Expected conflict error, got nil")
+		}
+		if !strings.Contains(err.Error(), "console formatter") {
+			t.Errorf("Expected console conflict error, got %v", err)
+		}
+	})
+
+	t.Run("File format after formatter conflicts", func(t *testing.T) {
+		_, err := applyAndValidate(
+			WithConsole(false),
+			WithFilePath(filepath.Join(t.TempDir(), "app.log")),
+			WithFileFormatter("{level} {message}"),
+			WithFileFormat(FormatText),
+		)
+		if err == nil {
+			t.Fatal("Expected conflict error, got nil")
+		}
+		if !strings.Contains(err.Error(), "file formatter") {
+			t.Errorf("Expected file conflict error, got %v", err)
+		}
+	})
+
+	t.Run("Default formatter with non-custom format is allowed", func(t *testing.T) {
+		if _, err := applyAndValidate(WithFormat(FormatJSON)); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Disabled destination is not checked", func(t *testing.T) {
+		if _, err := applyAndValidate(
+			WithFileFormatter("{level} {message}"),
+			WithFileFormat(FormatJSON),
+		); err != nil {
+			t.Errorf("Expected no error for disabled file destination, got %v", err)
+		}
+	})
+}
+
+// TestStrictCustomFormat tests strict and lenient handling of a missing custom formatter
+func TestStrictCustomFormat(t *testing.T) {
+	t.Run("Lenient mode defaults the formatter", func(t *testing.T) {
+		cfg := DefaultConfig()
+		WithConsoleFormatter("")(cfg)
+
+		if err := validateConfig(cfg); err != nil {
+			t.Fatalf("Expected no error in lenient mode, got %v", err)
+		}
+		if cfg.Console.Formatter != DefaultFormatter {
+			t.Errorf("Expected formatter to default to %q, got %q", DefaultFormatter, cfg.Console.Formatter)
+		}
+	})
+
+	t.Run("Strict mode rejects missing console formatter", func(t *testing.T) {
+		cfg := DefaultConfig()
+		WithStrictCustomFormat(true)(cfg)
+		WithConsoleFormatter("")(cfg)
+
+		err := validateConfig(cfg)
+		if err == nil || !strings.Contains(err.Error(), "console format is custom") {
+			t.Errorf("Expected strict console error, got %v", err)
+		}
+	})
+
+	t.Run("Strict mode rejects missing file formatter", func(t *testing.T) {
+		cfg := DefaultConfig()
+		WithStrictCustomFormat(true)(cfg)
+		WithConsole(false)(cfg)
+		WithFilePath(filepath.Join(t.TempDir(), "app.log"))(cfg)
+		WithFileFormatter("")(cfg)
+
+		err := validateConfig(cfg)
+		if err == nil || !strings.Contains(err.Error(), "file format is custom") {
+			t.Errorf("Expected strict file error, got %v", err)
+		}
+	})
+
+	t.Run("Strict mode accepts explicit formatter", func(t *testing.T) {
+		cfg := DefaultConfig()
+		WithStrictCustomFormat(true)(cfg)
+		WithConsoleFormatter("{level} {message}")(cfg)
+
+		if err := validateConfig(cfg); err != nil {
+			t.Errorf("Expected no error with explicit formatter, got %v", err)
+		}
+	})
+}
+
+// TestMessagelessFormatter tests the warning, strict error and override for formatters without {message}
+func TestMessagelessFormatter(t *testing.T) {
+	captureInternal := func(t *testing.T) *bytes.Buffer {
+		var buf bytes.Buffer
+		original := internalLogger
+		internalLogger = slog.New(slog.NewTextHandler(&buf, nil))
+		t.Cleanup(func() { internalLogger = original })
+		return &buf
+	}
+
+	t.Run("Lenient mode warns", func(t *testing.T) {
+		buf := captureInternal(t)
+		cfg := DefaultConfig()
+		WithConsoleFormatter("static text")(cfg)
+
+		if err := validateConfig(cfg); err != nil {
+			t.Fatalf("Expected no error in lenient mode, got %v", err)
+		}
+		if !strings.Contains(buf.String(), "no {message} placeholder") || !strings.Contains(buf.String(), "destination=console") {
+			t.Errorf("Expected warning about missing {message}, got %q", buf.String())
+		}
+	})
+
+	t.Run("Strict mode rejects", func(t *testing.T) {
+		captureInternal(t)
+		cfg := DefaultConfig()
+		WithStrictCustomFormat(true)(cfg)
+		WithConsole(false)(cfg)
+		WithFilePath(filepath.Join(t.TempDir(), "app.log"))(cfg)
+		WithFileFormatter("{time} {level}")(cfg)
+
+		err := validateConfig(cfg)
+		if err == nil || !strings.Contains(err.Error(), "file formatter") || !strings.Contains(err.Error(), PlaceholderMessage) {
+			t.Errorf("Expected strict error for missing {message}, got %v", err)
+		}
+	})
+
+	t.Run("Override silences the check", func(t *testing.T) {
+		buf := captureInternal(t)
+		cfg := DefaultConfig()
+		WithStrictCustomFormat(true)(cfg)
+		WithMessagelessFormatter(true)(cfg)
+		WithConsoleFormatter("static text")(cfg)
+
+		if err := validateConfig(cfg); err != nil {
+			t.Errorf("Expected no error with override, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no warning with override, got %q", buf.String())
+		}
+	})
+
+	t.Run("Formatter with message passes", func(t *testing.T) {
+		buf := captureInternal(t)
+		cfg := DefaultConfig()
+		WithConsoleFormatter("{level} {message}")(cfg)
+
+		if err := validateConfig(cfg); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected no warning, got %q", buf.String())
+		}
+	})
+}
+
+// TestPathExpansion tests ~ and $VAR expansion of file paths
+func TestPathExpansion(t *testing.T) {
+	t.Run("Tilde expands to home", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		t.Setenv("USERPROFILE", home)
+
+		cfg := DefaultConfig()
+		WithFilePath("~/logs/app.log")(cfg)
+		if err := validateConfig(cfg); err != nil {
+			t.Fatalf("validateConfig() failed: %v", err)
+		}
+		if want := filepath.Join(home, "logs", "app.log"); cfg.File.Path != want {
+			t.Errorf("Expected %q, got %q", want, cfg.File.Path)
+		}
+		if _, err := os.Stat(filepath.Join(home, "logs")); err != nil {
+			t.Errorf("Expected log directory under home: %v", err)
+		}
+	})
+
+	t.Run("Env vars expand", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("LOGGER_TEST_DIR", dir)
+
+		t.Setenv("LOGGER_TEST_NAME", "app")
+
+		cfg := DefaultConfig()
+		WithFilePath("$LOGGER_TEST_DIR/${LOGGER_TEST_NAME}.log")(cfg)
+		if err := validateConfig(cfg); err != nil {
+			t.Fatalf("validateConfig() failed: %v", err)
+		}
+		if want := dir + "/app.log"; cfg.File.Path != want {
+			t.Errorf("Expected %q, got %q", want, cfg.File.Path)
+		}
+	})
+
+	t.Run("Unset variable is an error", func(t *testing.T) {
+		os.Unsetenv("LOGGER_TEST_UNSET")
+		cfg := DefaultConfig()
+		WithFilePath("$LOGGER_TEST_UNSET/app.log")(cfg)
+		err := validateConfig(cfg)
+		if err == nil || !strings.Contains(err.Error(), "LOGGER_TEST_UNSET") {
+			t.Errorf("Expected error naming the unset variable, got %v", err)
+		}
+	})
+
+	t.Run("Disabled keeps the literal path", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("LOGGER_TEST_DIR", "/should/not/be/used")
+
+		cfg := DefaultConfig()
+		WithPathExpansion(false)(cfg)
+		literal := filepath.Join(dir, "$LOGGER_TEST_DIR", "app.log")
+		WithFilePath(literal)(cfg)
+		if err := validateConfig(cfg); err != nil {
+			t.Fatalf("validateConfig() failed: %v", err)
+		}
+		if cfg.File.Path != literal {
+			t.Errorf("Expected literal path %q, got %q", literal, cfg.File.Path)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// contextHandler is a slog.Handler that adds attributes extracted from the context to each record
+type contextHandler struct {
+	handler slog.Handler
+	extract func(context.Context) []slog.Attr
+}
+
+// newContextHandler wraps a handler so each record carries the attributes extract returns for its context
+func newContextHandler(handler slog.Handler, extract func(context.Context) []slog.Attr) slog.Handler {
+	return &contextHandler{handler: handler, extract: extract}
+}
+
+// Enabled implements slog.Handler
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	// Records logged without a context (e.g. slog.Logger.Info) carry context.Background()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if attrs := h.extract(ctx); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &contextHandler{handler: h.handler.WithAttrs(attrs), extract: h.extract}
+}
+
+// WithGroup implements slog.Handler
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &contextHandler{handler: h.handler.WithGroup(name), extract: h.extract}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type traceIDKey struct{}
+
+// traceIDFromContext extracts the trace ID stored under traceIDKey, if any
+func traceIDFromContext(ctx context.Context) []slog.Attr {
+	if id, ok := ctx.Value(traceIDKey{}).(string); ok {
+		return []slog.Attr{slog.String("trace_id", id)}
+	}
+	return nil
+}
+
+func TestWithContextExtractor(t *testing.T) {
+	for _, format := range []OutputFormat{FormatCustom, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "ctx.log")
+			log, err := New(
+				WithConsole(false),
+				WithFilePath(logPath),
+				WithFileFormat(format),
+				WithContextExtractor(traceIDFromContext),
+			)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+
+			ctx := context.WithValue(context.Background(), traceIDKey{}, "abc123")
+			log.InfoContext(ctx, "with trace")
+			log.Info("without trace")
+			if err := log.Close(); err != nil {
+				t.Fatalf("Close() failed: %v", err)
+			}
+
+			content, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("Failed to read log file: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("Expected 2 lines, got %q", content)
+			}
+			want := "trace_id=abc123"
+			if format == FormatJSON {
+				want = `"trace_id":"abc123"`
+			}
+			if !strings.Contains(lines[0], want) {
+				t.Errorf("Expected %s in %q", want, lines[0])
+			}
+			if strings.Contains(lines[1], "trace_id") {
+				t.Errorf("Expected no trace_id without a context value, got %q", lines[1])
+			}
+		})
+	}
+}
+
+func TestWithContextExtractor_ReplaceAttrMasks(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "ctx.log")
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(logPath),
+		WithContextExtractor(traceIDFromContext),
+		WithReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == "trace_id" {
+				return slog.String(a.Key, "***")
+			}
+			return a
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	log.InfoContext(context.WithValue(context.Background(), traceIDKey{}, "secret"), "masked")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if output := string(content); strings.Contains(output, "secret") || !strings.Contains(output, "trace_id=***") {
+		t.Errorf("Expected the extracted attribute to be masked, got %q", output)
+	}
+}
+
+func TestWithTraceIDKey(t *testing.T) {
+	type requestIDKey struct{}
+	render := func(t *testing.T, ctx context.Context) string {
+		t.Helper()
+		handler := NewCaptureHandler(nil)
+		cfg := DefaultConfig()
+		WithTraceIDKey(traceIDKey{}, "trace_id")(cfg)
+		WithContextExtractor(func(ctx context.Context) []slog.Attr {
+			if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+				return []slog.Attr{slog.String("request_id", id)}
+			}
+			return nil
+		})(cfg)
+		slog.New(wrapHandler(handler, cfg)).InfoContext(ctx, "handled")
+
+		var parts []string
+		for _, a := range handler.Records()[0].Attrs {
+			parts = append(parts, a.String())
+		}
+		return strings.Join(parts, " ")
+	}
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "abc")
+	if got := render(t, ctx); got != "trace_id=abc" {
+		t.Errorf("Expected trace_id=abc, got %q", got)
+	}
+
+	// Both extractors contribute, in the order they were applied
+	ctx = context.WithValue(ctx, requestIDKey{}, "r-1")
+	if got := render(t, ctx); got != "trace_id=abc request_id=r-1" {
+		t.Errorf("Expected both attributes, got %q", got)
+	}
+
+	// Absent (or empty) values are omitted rather than logged empty
+	if got := render(t, context.Background()); got != "" {
+		t.Errorf("Expected no attributes without a trace ID, got %q", got)
+	}
+	if got := render(t, context.WithValue(context.Background(), traceIDKey{}, "")); got != "" {
+		t.Errorf("Expected no attribute for an empty trace ID, got %q", got)
+	}
+}
@@ -1,13 +1,16 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,23 +19,29 @@ import (
 
 const (
 	// Placeholders
-	PlaceholderTime    = "{time}"
-	PlaceholderLevel   = "{level}"
-	PlaceholderMessage = "{message}"
-	PlaceholderFile    = "{file}"
-	PlaceholderAttrs   = "{attrs}"
+	PlaceholderTime      = "{time}"
+	PlaceholderLevel     = "{level}"
+	PlaceholderMessage   = "{message}"
+	PlaceholderFile      = "{file}"
+	PlaceholderHostname  = "{hostname}"
+	PlaceholderAttrs     = "{attrs}"
+	PlaceholderElapsed   = "{elapsed}"
+	PlaceholderPkg       = "{pkg}"
+	PlaceholderPid       = "{pid}"
+	PlaceholderGoroutine = "{goroutine}"
 
 	// ANSI escape codes
-	ansiReset          = "\033[0m"
-	ansiFaint          = "\033[2m"
-	ansiResetFaint     = "\033[22m"
-	ansiBrightCyan     = "\033[96m"
-	ansiBrightRed      = "\033[91m"
-	ansiBrightRedFaint = "\033[91;2m"
-	ansiBrightGreen    = "\033[92m"
-	ansiBrightYellow   = "\033[93m"
-	ansiBrightBlue     = "\033[94m"
-	ansiBrightMagenta  = "\033[95m"
+	ansiReset             = "\033[0m"
+	ansiFaint             = "\033[2m"
+	ansiResetFaint        = "\033[22m"
+	ansiBrightCyan        = "\033[96m"
+	ansiBrightRed         = "\033[91m"
+	ansiBrightRedFaint    = "\033[91;2m"
+	ansiBrightGreen       = "\033[92m"
+	ansiBrightYellow      = "\033[93m"
+	ansiBrightYellowFaint = "\033[93;2m"
+	ansiBrightBlue        = "\033[94m"
+	ansiBrightMagenta     = "\033[95m"
 )
 
 // TokenType represents the type of a template token
@@ -45,6 +54,13 @@ const (
 	TokenTypeMessage
 	TokenTypeFile
 	TokenTypeAttrs
+	TokenTypeElapsed
+	TokenTypePkg
+	TokenTypePid
+	TokenTypeHostname
+	TokenTypeGoroutine
+
+	tokenTypeCount // Number of token types, must stay last
 )
 
 // Token represents a parsed template component
@@ -53,6 +69,50 @@ type Token struct {
 	Text string // For static text tokens
 }
 
+// String returns the placeholder a token type stands for, or "text" for static text
+func (t TokenType) String() string {
+	switch t {
+	case TokenTypeText:
+		return "text"
+	case TokenTypeTime:
+		return PlaceholderTime
+	case TokenTypeLevel:
+		return PlaceholderLevel
+	case TokenTypeMessage:
+		return PlaceholderMessage
+	case TokenTypeFile:
+		return PlaceholderFile
+	case TokenTypeAttrs:
+		return PlaceholderAttrs
+	case TokenTypeElapsed:
+		return PlaceholderElapsed
+	case TokenTypePkg:
+		return PlaceholderPkg
+	case TokenTypePid:
+		return PlaceholderPid
+	case TokenTypeHostname:
+		return PlaceholderHostname
+	case TokenTypeGoroutine:
+		return PlaceholderGoroutine
+	}
+	return fmt.Sprintf("TokenType(%d)", int(t))
+}
+
+// String describes the token, quoting static text so surrounding spaces are visible
+func (t Token) String() string {
+	if t.Type == TokenTypeText {
+		return strconv.Quote(t.Text)
+	}
+	return t.Type.String()
+}
+
+// DescribeTemplate returns the tokens a custom format template is parsed into, for
+// debugging where placeholders and literal text begin and end. An empty template
+// describes DefaultFormatter, as it does when formatting.
+func DescribeTemplate(template string) []Token {
+	return slices.Clone(parseTemplate(template).tokens)
+}
+
 // ParsedTemplate holds the pre-parsed template tokens
 type ParsedTemplate struct {
 	tokens []Token
@@ -67,6 +127,17 @@ type handlerConfig struct {
 	attrs          []slog.Attr
 	opts           slog.HandlerOptions
 	parsedTemplate *ParsedTemplate // Pre-parsed template for efficient formatting
+	startTime      time.Time       // Reference time for {elapsed}
+	pid            string          // Process ID for {pid}, formatted once at creation
+	hostname       string          // Host name for {hostname}, resolved once at creation
+}
+
+// timeZone returns the zone {time} is formatted in: the destination's own, else the global one
+func (cfg *handlerConfig) timeZone() *time.Location {
+	if tz := cfg.outputCfg.GetTimeZone(); tz != nil {
+		return tz
+	}
+	return cfg.globalCfg.TimeZone
 }
 
 type customHandler struct {
@@ -86,6 +157,8 @@ type outputConfig interface {
 	GetFormat() OutputFormat
 	GetColor() bool
 	GetFormatter() string
+	GetWrapWidth() int
+	GetTimeZone() *time.Location // nil uses the global time zone
 }
 
 // ConsoleConfig implements outputConfig interface
@@ -101,6 +174,14 @@ func (c *ConsoleConfig) GetFormatter() string {
 	return c.Formatter
 }
 
+func (c *ConsoleConfig) GetWrapWidth() int {
+	return c.WrapWidth
+}
+
+func (c *ConsoleConfig) GetTimeZone() *time.Location {
+	return c.TimeZone
+}
+
 // FileConfig implements outputConfig interface
 func (c *FileConfig) GetFormat() OutputFormat {
 	return c.Format
@@ -115,6 +196,15 @@ func (c *FileConfig) GetFormatter() string {
 	return c.Formatter
 }
 
+func (c *FileConfig) GetWrapWidth() int {
+	// File output is never wrapped
+	return 0
+}
+
+func (c *FileConfig) GetTimeZone() *time.Location {
+	return c.TimeZone
+}
+
 // parseTemplate parses a format template into tokens for efficient rendering
 func parseTemplate(template string) *ParsedTemplate {
 	if template == "" {
@@ -140,6 +230,11 @@ func parseTemplate(template string) *ParsedTemplate {
 			{PlaceholderMessage, TokenTypeMessage},
 			{PlaceholderFile, TokenTypeFile},
 			{PlaceholderAttrs, TokenTypeAttrs},
+			{PlaceholderElapsed, TokenTypeElapsed},
+			{PlaceholderPkg, TokenTypePkg},
+			{PlaceholderPid, TokenTypePid},
+			{PlaceholderHostname, TokenTypeHostname},
+			{PlaceholderGoroutine, TokenTypeGoroutine},
 		}
 
 		for _, p := range placeholders {
@@ -180,6 +275,9 @@ func newCustomHandler(w io.Writer, globalCfg *Config, outputCfg outputConfig, op
 	if formatter == "" {
 		formatter = DefaultFormatter
 	}
+	if globalCfg.CanonicalOrder {
+		formatter = CanonicalFormatter
+	}
 
 	// Parse template at startup time for efficient formatting
 	parsedTemplate := parseTemplate(formatter)
@@ -192,13 +290,19 @@ func newCustomHandler(w io.Writer, globalCfg *Config, outputCfg outputConfig, op
 		groups:         make([]string, 0),
 		attrs:          make([]slog.Attr, 0),
 		parsedTemplate: parsedTemplate,
+		startTime:      globalCfg.startTime,
+		pid:            strconv.Itoa(os.Getpid()),
+		hostname:       hostname(),
+	}
+	if cfg.startTime.IsZero() {
+		cfg.startTime = time.Now()
 	}
 
 	if opts != nil {
 		cfg.opts = *opts
 	} else {
 		cfg.opts = slog.HandlerOptions{
-			Level:       globalCfg.Level,
+			Level:       globalCfg.leveler(),
 			AddSource:   globalCfg.AddSource,
 			ReplaceAttr: globalCfg.ReplaceAttr,
 		}
@@ -240,22 +344,35 @@ func (h *customHandler) Handle(ctx context.Context, r slog.Record) error {
 
 	// Lock-free log formatting (CPU-intensive operation)
 	builder := h.pool.Get().(*strings.Builder)
-	defer func() {
-		builder.Reset()
-		h.pool.Put(builder)
-	}()
+	defer h.putBuilder(builder, cfg)
 
 	h.formatLogLine(builder, r, cfg)
 	logData := []byte(builder.String())
 
 	// Only lock during write (I/O operation)
 	h.writeMu.Lock()
-	_, err := h.out.Write(logData)
+	n, err := h.out.Write(logData)
 	h.writeMu.Unlock()
 
+	if err != nil && cfg.globalCfg.hasErrorCallback() {
+		runCallback(ctx, func() { cfg.globalCfg.reportWriteError(err, r) })
+	} else if err == nil && cfg.globalCfg.OnRecord != nil {
+		runCallback(ctx, func() { cfg.globalCfg.OnRecord(r.Level, n) })
+	}
+
 	return err
 }
 
+// putBuilder returns a builder to the pool, discarding oversized ones so a single
+// huge record doesn't permanently inflate pooled memory
+func (h *customHandler) putBuilder(builder *strings.Builder, cfg *handlerConfig) {
+	if limit := cfg.globalCfg.MaxPooledBuilderSize; limit > 0 && builder.Cap() > limit {
+		return
+	}
+	builder.Reset()
+	h.pool.Put(builder)
+}
+
 func (h *customHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	if len(attrs) == 0 {
 		return h
@@ -271,6 +388,9 @@ func (h *customHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		attrs:          append(slices.Clone(oldCfg.attrs), attrs...),
 		opts:           oldCfg.opts,
 		parsedTemplate: oldCfg.parsedTemplate, // Share the parsed template
+		startTime:      oldCfg.startTime,
+		pid:            oldCfg.pid,
+		hostname:       oldCfg.hostname,
 	}
 
 	newHandler := &customHandler{
@@ -297,6 +417,9 @@ func (h *customHandler) WithGroup(name string) slog.Handler {
 		attrs:          slices.Clone(oldCfg.attrs),
 		opts:           oldCfg.opts,
 		parsedTemplate: oldCfg.parsedTemplate, // Share the parsed template
+		startTime:      oldCfg.startTime,
+		pid:            oldCfg.pid,
+		hostname:       oldCfg.hostname,
 	}
 
 	newHandler := &customHandler{
@@ -311,23 +434,30 @@ func (h *customHandler) WithGroup(name string) slog.Handler {
 func (h *customHandler) formatLogLine(builder *strings.Builder, r slog.Record, cfg *handlerConfig) {
 	// Process built-in attributes through ReplaceAttr like standard slog handlers
 	rep := cfg.opts.ReplaceAttr
+	colors := cfg.globalCfg.colorProfile()
 
 	// Pre-compute all the parts that might be needed
-	var timeStr, levelStr, msgStr, fileStr, attrsStr string
+	var timeStr, levelStr, msgStr, fileStr, attrsStr, elapsedStr, pkgStr string
+
+	// A color decider may override the level-based color of the level and message
+	var lineColor string
+	if decide := cfg.globalCfg.ColorDecider; decide != nil && cfg.outputCfg.GetColor() {
+		lineColor = decide(r)
+	}
 
-	// Handle time (built-in attribute)
-	if !r.Time.IsZero() {
-		timeAttr := slog.Time(slog.TimeKey, r.Time.In(cfg.globalCfg.TimeZone))
+	// Handle time (built-in attribute), skipped entirely when the template doesn't show it
+	if !r.Time.IsZero() && cfg.parsedTemplate.has(TokenTypeTime) {
+		timeAttr := slog.Time(slog.TimeKey, r.Time.In(cfg.timeZone()))
 		if rep != nil {
 			timeAttr = rep(nil, timeAttr) // Built-ins are not in any group
 		}
 		if !timeAttr.Equal(slog.Attr{}) { // Check if not removed by ReplaceAttr
 			timeValue := timeAttr.Value.Any()
 			if t, ok := timeValue.(time.Time); ok {
-				timeStr = h.colorize(t.Format(cfg.globalCfg.TimeFormat), ansiFaint, cfg)
+				timeStr = h.colorize(t.Format(cfg.globalCfg.TimeFormat), colors.Muted, cfg)
 			} else {
 				// ReplaceAttr changed the type, use the new value
-				timeStr = h.colorize(fmt.Sprintf("%v", timeValue), ansiFaint, cfg)
+				timeStr = h.colorize(fmt.Sprintf("%v", timeValue), colors.Muted, cfg)
 			}
 		}
 	}
@@ -340,10 +470,10 @@ func (h *customHandler) formatLogLine(builder *strings.Builder, r slog.Record, c
 	if !levelAttr.Equal(slog.Attr{}) { // Check if not removed by ReplaceAttr
 		levelValue := levelAttr.Value.Any()
 		if level, ok := levelValue.(slog.Level); ok {
-			levelStr = h.colorizeLevel(level, cfg)
+			levelStr = h.colorizeLevel(level, lineColor, cfg)
 		} else {
 			// ReplaceAttr changed the type, use the new value
-			levelStr = h.colorize(fmt.Sprintf("%v", levelValue), ansiBrightGreen, cfg)
+			levelStr = h.colorize(fmt.Sprintf("%v", levelValue), colors.Info, cfg)
 		}
 	}
 
@@ -353,14 +483,23 @@ func (h *customHandler) formatLogLine(builder *strings.Builder, r slog.Record, c
 		msgAttr = rep(nil, msgAttr) // Built-ins are not in any group
 	}
 	if !msgAttr.Equal(slog.Attr{}) { // Check if not removed by ReplaceAttr
-		msgStr = h.colorizeMessage(msgAttr.Value.String(), r.Level, cfg)
+		msgStr = h.colorizeMessage(msgAttr.Value.String(), r.Level, lineColor, cfg)
+		// With a message key, {message} renders keyed (key=message) instead of positionally
+		if key := cfg.globalCfg.MessageKey; key != "" {
+			msgStr = h.colorize(key, colors.Muted, cfg) + h.colorize("=", colors.Muted, cfg) + msgStr
+		}
 	}
 
 	// Handle source/file (built-in attribute)
-	if cfg.opts.AddSource {
+	// An explicit *slog.Source attribute supplied by the caller takes precedence over r.PC,
+	// so wrappers can report the real call site
+	explicitSrc := explicitSource(r)
+	if cfg.opts.AddSource || explicitSrc != nil {
 		// Create source attribute like standard slog handlers
 		var source *slog.Source
-		if r.PC != 0 {
+		if explicitSrc != nil {
+			source = explicitSrc
+		} else if r.PC != 0 {
 			fs := runtime.CallersFrames([]uintptr{r.PC})
 			f, _ := fs.Next()
 			source = &slog.Source{
@@ -382,11 +521,14 @@ func (h *customHandler) formatLogLine(builder *strings.Builder, r slog.Record, c
 			if src, ok := sourceValue.(*slog.Source); ok {
 				if src.File != "" {
 					// Standard format: filename:function:line
-					fileStr = h.colorize(fmt.Sprintf("%s:%s:%d", filepath.Base(src.File), filepath.Base(src.Function), src.Line), ansiFaint, cfg)
+					fileStr = h.colorize(fmt.Sprintf("%s:%s:%d", sourceFile(src.File, cfg.globalCfg.SourceFormat), filepath.Base(src.Function), src.Line), colors.Muted, cfg)
+				}
+				if src.Function != "" && cfg.parsedTemplate.has(TokenTypePkg) {
+					pkgStr = h.colorize(callerPackage(src.Function), colors.Muted, cfg)
 				}
 			} else {
 				// ReplaceAttr changed the type, use the new value
-				fileStr = h.colorize(fmt.Sprintf("%v", sourceValue), ansiFaint, cfg)
+				fileStr = h.colorize(fmt.Sprintf("%v", sourceValue), colors.Muted, cfg)
 			}
 		}
 	}
@@ -394,117 +536,258 @@ func (h *customHandler) formatLogLine(builder *strings.Builder, r slog.Record, c
 	// Handle user attributes
 	if cfg.attrsIndex >= 0 {
 		attrBuilder := h.pool.Get().(*strings.Builder)
-		defer func() {
-			attrBuilder.Reset()
-			h.pool.Put(attrBuilder)
-		}()
+		defer h.putBuilder(attrBuilder, cfg)
 
 		isFirst := true
+		if cfg.globalCfg.GroupPrefixOnce && len(cfg.groups) > 0 && r.NumAttrs() > 0 {
+			// e.g. "Database.MySQL: host=localhost port=3306"
+			attrBuilder.WriteString(h.colorize(strings.Join(cfg.groups, ".")+":", colors.Muted, cfg))
+			isFirst = false
+		}
+		var sorted []slog.Attr // Collected instead of rendered with canonical order
 		r.Attrs(func(a slog.Attr) bool {
+			// The explicit source is rendered via {file}, not as a regular attribute
+			if explicitSrc != nil && isSourceAttr(a) {
+				return true
+			}
 			// Apply ReplaceAttr if configured
 			if rep != nil {
 				a = rep(cfg.groups, a) // User attributes use current groups
 			}
+			if cfg.globalCfg.CanonicalOrder {
+				sorted = append(sorted, a)
+				return true
+			}
 			h.appendColorizedAttr(attrBuilder, a, r.Level, isFirst, cfg)
 			isFirst = false
 			return true
 		})
+		for _, a := range sortAttrs(sorted) {
+			h.appendColorizedAttr(attrBuilder, a, r.Level, isFirst, cfg)
+			isFirst = false
+		}
 		attrsStr = attrBuilder.String()
 	}
 
+	// Handle elapsed time since logger creation
+	if cfg.parsedTemplate.has(TokenTypeElapsed) {
+		elapsedStr = h.colorize(formatElapsed(time.Since(cfg.startTime)), colors.Muted, cfg)
+	}
+
 	// Use parsed template for efficient formatting
-	h.renderTemplate(builder, cfg.parsedTemplate, timeStr, levelStr, msgStr, fileStr, attrsStr)
+	var values templateValues
+	values[TokenTypeTime] = timeStr
+	values[TokenTypeLevel] = levelStr
+	values[TokenTypeMessage] = msgStr
+	values[TokenTypeFile] = fileStr
+	values[TokenTypeAttrs] = attrsStr
+	values[TokenTypeElapsed] = elapsedStr
+	values[TokenTypePkg] = pkgStr
+	if cfg.parsedTemplate.has(TokenTypePid) {
+		values[TokenTypePid] = h.colorize(cfg.pid, colors.Muted, cfg)
+	}
+	if cfg.parsedTemplate.has(TokenTypeHostname) {
+		values[TokenTypeHostname] = h.colorize(cfg.hostname, colors.Muted, cfg)
+	}
+	// Reading the goroutine ID means formatting a stack header, so only do it when shown
+	if cfg.parsedTemplate.has(TokenTypeGoroutine) {
+		values[TokenTypeGoroutine] = h.colorize(goroutineID(), colors.Muted, cfg)
+	}
+	h.renderTemplate(builder, cfg.parsedTemplate, &values, cfg.globalCfg.LiteralTemplate)
+	if width := cfg.outputCfg.GetWrapWidth(); width > 0 {
+		wrapped := wrapLine(builder.String(), width)
+		builder.Reset()
+		builder.WriteString(wrapped)
+	}
+	if limit := cfg.globalCfg.MaxLineBytes; limit > 0 && builder.Len() > limit {
+		truncated := truncateLine(builder.String(), limit)
+		builder.Reset()
+		builder.WriteString(truncated)
+	}
 	builder.WriteString("\n")
 }
 
-// renderTemplate efficiently renders the parsed template by iterating through tokens
-func (h *customHandler) renderTemplate(builder *strings.Builder, template *ParsedTemplate, timeStr, levelStr, msgStr, fileStr, attrsStr string) {
+// sortAttrs sorts attributes by key, recursing into groups. The sort is stable so
+// duplicate keys keep the order they were added in.
+func sortAttrs(attrs []slog.Attr) []slog.Attr {
+	slices.SortStableFunc(attrs, func(a, b slog.Attr) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	for i, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			attrs[i].Value = slog.GroupValue(sortAttrs(slices.Clone(a.Value.Group()))...)
+		}
+	}
+	return attrs
+}
+
+// explicitSource returns the first caller-supplied source attribute of the record, if any
+func explicitSource(r slog.Record) *slog.Source {
+	var src *slog.Source
+	r.Attrs(func(a slog.Attr) bool {
+		if isSourceAttr(a) {
+			src = a.Value.Any().(*slog.Source)
+			return false
+		}
+		return true
+	})
+	return src
+}
+
+// sourceFile shortens a source file path for {file} according to mode
+func sourceFile(file string, mode SourceFormat) string {
+	switch mode {
+	case SourceFull:
+		return file
+	case SourcePackage:
+		if dir := filepath.Base(filepath.Dir(file)); dir != "." && dir != string(filepath.Separator) {
+			return dir + "/" + filepath.Base(file)
+		}
+	}
+	return filepath.Base(file)
+}
+
+// isSourceAttr reports whether the attribute uses the reserved source key with a *slog.Source value
+func isSourceAttr(a slog.Attr) bool {
+	if a.Key != slog.SourceKey || a.Value.Kind() != slog.KindAny {
+		return false
+	}
+	src, ok := a.Value.Any().(*slog.Source)
+	return ok && src != nil
+}
+
+// templateValues holds the rendered value of each placeholder, indexed by token type
+type templateValues [tokenTypeCount]string
+
+// renderTemplate efficiently renders the parsed template by iterating through tokens.
+// Unless literal, spaces around empty placeholders are collapsed.
+func (h *customHandler) renderTemplate(builder *strings.Builder, template *ParsedTemplate, values *templateValues, literal bool) {
 	tokens := template.tokens
+	start := builder.Len()
 	for i, token := range tokens {
-		switch token.Type {
-		case TokenTypeText:
-			// Handle text tokens, but be smart about spaces around empty placeholders
-			text := token.Text
-
-			// If this is a space before an empty placeholder, and we're followed by another space, skip one space
-			if text == " " && i+2 < len(tokens) {
-				nextToken := tokens[i+1]
-				nextNextToken := tokens[i+2]
-
-				// Check if next token is empty and followed by space
-				isEmpty := false
-				switch nextToken.Type {
-				case TokenTypeTime:
-					isEmpty = timeStr == ""
-				case TokenTypeLevel:
-					isEmpty = levelStr == ""
-				case TokenTypeMessage:
-					isEmpty = msgStr == ""
-				case TokenTypeFile:
-					isEmpty = fileStr == ""
-				case TokenTypeAttrs:
-					isEmpty = attrsStr == ""
-				}
+		if token.Type != TokenTypeText {
+			builder.WriteString(values[token.Type])
+			continue
+		}
+		if literal {
+			builder.WriteString(token.Text)
+			continue
+		}
 
-				// If next placeholder is empty and followed by space, skip this space
-				if isEmpty && nextNextToken.Type == TokenTypeText && strings.HasPrefix(nextNextToken.Text, " ") {
-					continue
-				}
-			}
+		// Handle text tokens, but be smart about spaces around empty placeholders
+		text := token.Text
 
-			builder.WriteString(text)
-		case TokenTypeTime:
-			if timeStr != "" {
-				builder.WriteString(timeStr)
-			}
-		case TokenTypeLevel:
-			if levelStr != "" {
-				builder.WriteString(levelStr)
-			}
-		case TokenTypeMessage:
-			if msgStr != "" {
-				builder.WriteString(msgStr)
-			}
-		case TokenTypeFile:
-			if fileStr != "" {
-				builder.WriteString(fileStr)
-			}
-		case TokenTypeAttrs:
-			if attrsStr != "" {
-				builder.WriteString(attrsStr)
+		// Placeholders may come in any order, e.g. "{attrs} {level} {message}": when only empty
+		// placeholders precede or follow this text, its spaces separate nothing, so drop them
+		if i > 0 && builder.Len() == start {
+			text = strings.TrimLeft(text, " ")
+		}
+		if onlyEmptyPlaceholders(tokens[i+1:], values) {
+			text = strings.TrimRight(text, " ")
+		}
+
+		// If this is a space before an empty placeholder, and we're followed by another space, skip one space
+		if text == " " && i+2 < len(tokens) {
+			nextToken := tokens[i+1]
+			nextNextToken := tokens[i+2]
+
+			// Check if next token is an empty placeholder followed by space
+			isEmpty := nextToken.Type != TokenTypeText && values[nextToken.Type] == ""
+			if isEmpty && nextNextToken.Type == TokenTypeText && strings.HasPrefix(nextNextToken.Text, " ") {
+				continue
 			}
 		}
+
+		builder.WriteString(text)
+	}
+}
+
+// onlyEmptyPlaceholders reports whether tokens is non-empty and consists solely of placeholders rendering as ""
+func onlyEmptyPlaceholders(tokens []Token, values *templateValues) bool {
+	for _, token := range tokens {
+		if token.Type == TokenTypeText || values[token.Type] != "" {
+			return false
+		}
+	}
+	return len(tokens) > 0
+}
+
+// has reports whether the template contains a placeholder of the given type
+func (t *ParsedTemplate) has(tokenType TokenType) bool {
+	for _, token := range t.tokens {
+		if token.Type == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine 123 [running]:" header of its stack trace, or "" if it cannot be read
+func goroutineID() string {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header, ok := bytes.CutPrefix(header, []byte("goroutine "))
+	if !ok {
+		return ""
+	}
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		return string(header[:i])
 	}
+	return ""
+}
+
+// hostname returns the machine's host name, or "unknown" if it cannot be determined
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// formatElapsed renders a duration as whole milliseconds, e.g. "1234ms"
+func formatElapsed(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
 }
 
 func (h *customHandler) colorize(s, color string, cfg *handlerConfig) string {
-	if !cfg.outputCfg.GetColor() {
+	if !cfg.outputCfg.GetColor() || color == "" {
 		return s
 	}
 	return color + s + ansiReset
 }
 
-func (h *customHandler) colorizeLevel(level slog.Level, cfg *handlerConfig) string {
+// colorizeLevel colors the level by severity, or with lineColor when it is set
+func (h *customHandler) colorizeLevel(level slog.Level, lineColor string, cfg *handlerConfig) string {
+	if lineColor != "" {
+		return h.colorize(level.String(), lineColor, cfg)
+	}
+	colors := cfg.globalCfg.colorProfile()
 	var color string
 	switch {
 	case level <= slog.LevelDebug:
-		color = ansiBrightCyan
+		color = colors.Debug
 	case level <= slog.LevelInfo:
-		color = ansiBrightGreen
+		color = colors.Info
 	case level <= slog.LevelWarn:
-		color = ansiBrightYellow
+		color = colors.Warn
 	case level <= slog.LevelError:
-		color = ansiBrightRed
+		color = colors.Error
 	default:
-		color = ansiBrightMagenta
+		color = colors.Critical
 	}
 
 	return h.colorize(level.String(), color, cfg)
 }
 
-func (h *customHandler) colorizeMessage(msg string, level slog.Level, cfg *handlerConfig) string {
+// colorizeMessage colors error messages, or any message with lineColor when it is set
+func (h *customHandler) colorizeMessage(msg string, level slog.Level, lineColor string, cfg *handlerConfig) string {
+	if lineColor != "" {
+		return h.colorize(msg, lineColor, cfg)
+	}
 	if level >= slog.LevelError {
-		return h.colorize(msg, ansiBrightRed, cfg)
+		return h.colorize(msg, cfg.globalCfg.colorProfile().ErrorMessage, cfg)
 	}
 	return msg
 }
@@ -518,19 +801,56 @@ func (h *customHandler) appendColorizedAttr(builder *strings.Builder, a slog.Att
 		builder.WriteByte(' ')
 	}
 
-	// Build the key with group prefixes (slog standard behavior)
+	// Build the key with group prefixes (slog standard behavior),
+	// unless the group path is emitted once in front of all attributes
 	key := a.Key
-	if len(cfg.groups) > 0 {
+	if len(cfg.groups) > 0 && !cfg.globalCfg.GroupPrefixOnce && !slices.Contains(cfg.globalCfg.UngroupedAttrs, a.Key) {
 		key = strings.Join(cfg.groups, ".") + "." + a.Key
 	}
 
-	if level >= slog.LevelError && a.Key == "error" {
-		builder.WriteString(h.colorize(key, ansiBrightRedFaint, cfg))
-		builder.WriteString(h.colorize("=", ansiBrightRedFaint, cfg))
-		builder.WriteString(h.colorize(fmt.Sprintf("%v", a.Value.Any()), ansiBrightRed, cfg))
+	colors := cfg.globalCfg.colorProfile()
+	if a.Key == "error" && level >= cfg.globalCfg.errorHighlightLevel() {
+		keyColor, valueColor := colors.ErrorKey, colors.ErrorValue
+		if level < slog.LevelError {
+			keyColor, valueColor = colors.WarnErrorKey, colors.WarnErrorValue
+		}
+		builder.WriteString(h.colorize(key, keyColor, cfg))
+		builder.WriteString(h.colorize("=", keyColor, cfg))
+		builder.WriteString(h.colorize(formatAttrValue(a.Value, cfg.globalCfg.StringerAsIs), valueColor, cfg))
 	} else {
-		builder.WriteString(h.colorize(key, ansiFaint, cfg))
-		builder.WriteString(h.colorize("=", ansiFaint, cfg))
-		fmt.Fprintf(builder, "%v", a.Value.Any())
+		builder.WriteString(h.colorize(key, colors.Muted, cfg))
+		builder.WriteString(h.colorize("=", colors.Muted, cfg))
+		builder.WriteString(formatAttrValue(a.Value, cfg.globalCfg.StringerAsIs))
+	}
+}
+
+// formatAttrValue renders an attribute value for the custom format.
+// Errors are rendered with Error() and fmt.Stringers with String() (an error that is also a
+// Stringer uses Error()). With stringerAsIs, Stringers are shown as their Go value instead.
+func formatAttrValue(v slog.Value, stringerAsIs bool) string {
+	v = v.Resolve()
+	if v.Kind() != slog.KindAny {
+		return v.String()
+	}
+	switch x := v.Any().(type) {
+	case error:
+		return safeString(x.Error)
+	case fmt.Stringer:
+		if stringerAsIs {
+			return fmt.Sprintf("%#v", x)
+		}
+		return safeString(x.String)
+	default:
+		return fmt.Sprintf("%v", x)
 	}
 }
+
+// safeString calls fn, recovering from panics such as nil pointer receivers like fmt does
+func safeString(fn func() string) (s string) {
+	defer func() {
+		if p := recover(); p != nil {
+			s = fmt.Sprintf("<PANIC=%v>", p)
+		}
+	}()
+	return fn()
+}
@@ -3,8 +3,18 @@ package logger
 import (
 	"bytes"
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -14,6 +24,8 @@ type mockOutputConfig struct {
 	format    OutputFormat
 	color     bool
 	formatter string
+	wrapWidth int
+	timeZone  *time.Location
 }
 
 func (m *mockOutputConfig) GetFormat() OutputFormat {
@@ -28,6 +40,14 @@ func (m *mockOutputConfig) GetFormatter() string {
 	return m.formatter
 }
 
+func (m *mockOutputConfig) GetTimeZone() *time.Location {
+	return m.timeZone
+}
+
+func (m *mockOutputConfig) GetWrapWidth() int {
+	return m.wrapWidth
+}
+
 func TestCustomHandler(t *testing.T) {
 	t.Run("BasicFormatting", func(t *testing.T) {
 		var buf bytes.Buffer
@@ -1230,3 +1250,826 @@ func TestOutputConfigInterfaces(t *testing.T) {
 		}
 	})
 }
+
+// TestCustomHandler_ExplicitSource tests that a caller-supplied source attribute overrides r.PC
+func TestCustomHandler_ExplicitSource(t *testing.T) {
+	newHandler := func(buf *bytes.Buffer, addSource bool) slog.Handler {
+		cfg := DefaultConfig()
+		outputCfg := &mockOutputConfig{
+			format:    FormatCustom,
+			color:     false,
+			formatter: "{level} {message} {file} {attrs}",
+		}
+		handler, err := newCustomHandler(buf, cfg, outputCfg, &slog.HandlerOptions{
+			Level:     slog.LevelInfo,
+			AddSource: addSource,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		return handler
+	}
+
+	src := &slog.Source{
+		Function: "github.com/example/app.handleRequest",
+		File:     "/src/app/server.go",
+		Line:     42,
+	}
+
+	t.Run("ExplicitSourceOverridesPC", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newHandler(&buf, true))
+
+		logger.Info("wrapped call", slog.Any(slog.SourceKey, src), "key", "value")
+
+		output := buf.String()
+		if !strings.Contains(output, "server.go:app.handleRequest:42") {
+			t.Errorf("Expected explicit source in output, got: %q", output)
+		}
+		if strings.Contains(output, "custom_handler_test.go") {
+			t.Errorf("Expected PC-derived source to be ignored, got: %q", output)
+		}
+		if strings.Contains(output, "source=") {
+			t.Errorf("Explicit source should not be repeated as an attribute, got: %q", output)
+		}
+		if !strings.Contains(output, "key=value") {
+			t.Errorf("Expected other attributes to be kept, got: %q", output)
+		}
+	})
+
+	t.Run("ExplicitSourceWithoutAddSource", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newHandler(&buf, false))
+
+		logger.Info("wrapped call", slog.Any(slog.SourceKey, src))
+
+		output := buf.String()
+		if !strings.Contains(output, "server.go:app.handleRequest:42") {
+			t.Errorf("Expected explicit source in output, got: %q", output)
+		}
+	})
+
+	t.Run("NonSourceValueIsRegularAttr", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newHandler(&buf, false))
+
+		logger.Info("plain", slog.String(slog.SourceKey, "not-a-source"))
+
+		output := buf.String()
+		if !strings.Contains(output, "source=not-a-source") {
+			t.Errorf("Expected non-*slog.Source value to render as attribute, got: %q", output)
+		}
+	})
+}
+
+// TestCustomHandler_MaxPooledBuilderSize tests that oversized builders are not returned to the pool
+func TestCustomHandler_MaxPooledBuilderSize(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	WithMaxPooledBuilderSize(4 * 1024)(cfg)
+
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{level} {message} {attrs}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	huge := strings.Repeat("x", 2*1024*1024)
+	logger.Info("huge", "payload", huge)
+	if !strings.Contains(buf.String(), huge) {
+		t.Fatal("Expected huge attribute to be written")
+	}
+
+	pool := handler.(*customHandler).pool
+	for i := 0; i < 10; i++ {
+		b := pool.Get().(*strings.Builder)
+		if b.Cap() > 4*1024 {
+			t.Errorf("Expected pooled builder capacity <= 4096, got %d", b.Cap())
+		}
+	}
+
+	// Small records still reuse pooled builders
+	buf.Reset()
+	logger.Info("small", "key", "value")
+	if !strings.Contains(buf.String(), "key=value") {
+		t.Errorf("Expected small record to be written, got %q", buf.String())
+	}
+}
+
+// TestCustomHandler_OnRecord tests the per-record metrics callback
+func TestCustomHandler_OnRecord(t *testing.T) {
+	var buf bytes.Buffer
+	var levels []slog.Level
+	var total int
+
+	cfg := DefaultConfig()
+	WithOnRecord(func(level slog.Level, bytesWritten int) {
+		levels = append(levels, level)
+		total += bytesWritten
+	})(cfg)
+
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{level} {message}",
+	}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	logger.Debug("one")
+	logger.Info("two")
+	logger.Error("three")
+
+	expected := []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelError}
+	if len(levels) != len(expected) {
+		t.Fatalf("Expected %d callbacks, got %d", len(expected), len(levels))
+	}
+	for i, level := range expected {
+		if levels[i] != level {
+			t.Errorf("Callback %d: expected level %v, got %v", i, level, levels[i])
+		}
+	}
+	if total != buf.Len() {
+		t.Errorf("Expected %d bytes reported, got %d", buf.Len(), total)
+	}
+}
+
+// TestCustomHandler_OnRecordSkipsFailedWrites tests that the callback only fires on success
+func TestCustomHandler_OnRecordSkipsFailedWrites(t *testing.T) {
+	called := false
+	cfg := DefaultConfig()
+	WithOnRecord(func(slog.Level, int) { called = true })(cfg)
+
+	handler, err := newCustomHandler(&failingWriter{}, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	if err := handler.Handle(context.Background(), slog.Record{Level: slog.LevelInfo, Message: "lost"}); err == nil {
+		t.Fatal("Expected write error")
+	}
+	if called {
+		t.Error("Expected callback not to fire on failed write")
+	}
+}
+
+// failingWriter is an io.Writer that always fails
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// TestCustomHandler_OnError tests that write failures reach the callback with the record
+func TestCustomHandler_OnError(t *testing.T) {
+	var errs []error
+	var messages []string
+	cfg := DefaultConfig()
+	WithOnError(func(err error, r slog.Record) {
+		errs = append(errs, err)
+		messages = append(messages, r.Message)
+	})(cfg)
+
+	handler, err := newCustomHandler(&failingWriter{}, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	writeErr := handler.Handle(context.Background(), slog.Record{Level: slog.LevelError, Message: "lost"})
+	if writeErr == nil {
+		t.Fatal("Expected write error")
+	}
+	if len(errs) != 1 || errs[0] != writeErr {
+		t.Fatalf("Expected the callback to receive the write error %v, got %v", writeErr, errs)
+	}
+	if messages[0] != "lost" {
+		t.Errorf("Expected the failed record, got message %q", messages[0])
+	}
+}
+
+// TestCustomHandler_OnErrorRecursionGuard tests that a callback logging through the failing sink doesn't recurse
+func TestCustomHandler_OnErrorRecursionGuard(t *testing.T) {
+	calls := 0
+	var logger *slog.Logger
+	cfg := DefaultConfig()
+	WithOnError(func(err error, r slog.Record) {
+		calls++
+		logger.Error("write failed", "err", err) // Fails too, on the same sink
+	})(cfg)
+
+	handler, err := newCustomHandler(&failingWriter{}, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger = slog.New(handler)
+
+	logger.Info("first")
+	logger.Info("second")
+	if calls != 2 {
+		t.Errorf("Expected one callback per original failure, got %d", calls)
+	}
+}
+
+// TestCustomHandler_ErrorHandler tests that write failures reach WithErrorHandler, alongside OnError
+func TestCustomHandler_ErrorHandler(t *testing.T) {
+	var errs []error
+	var records int
+	cfg := DefaultConfig()
+	WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})(cfg)
+	WithOnError(func(error, slog.Record) { records++ })(cfg)
+
+	handler, err := newCustomHandler(&failingWriter{}, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	logger.Info("lost")
+	if len(errs) != 1 || errs[0].Error() != "write failed" {
+		t.Fatalf("Expected the error handler to receive the write error, got %v", errs)
+	}
+	if records != 1 {
+		t.Errorf("Expected OnError to still be called, got %d calls", records)
+	}
+
+	// The error handler is not re-entered by records it logs through the failing sink
+	errs = nil
+	cfg.ErrorHandler = func(err error) {
+		errs = append(errs, err)
+		logger.Error("write failed", "err", err)
+	}
+	logger.Info("first")
+	logger.Info("second")
+	if len(errs) != 2 {
+		t.Errorf("Expected one call per original failure, got %d", len(errs))
+	}
+}
+
+// TestCustomHandler_ErrorHandlerOtherGoroutines tests that the recursion guard only holds back
+// the goroutine running the callback, not failures elsewhere meanwhile
+func TestCustomHandler_ErrorHandlerOtherGoroutines(t *testing.T) {
+	entered := make(chan string, 2)
+	release := make(chan struct{})
+	cfg := DefaultConfig()
+	WithErrorHandler(func(err error) {
+		entered <- goroutineID()
+		<-release
+	})(cfg)
+
+	handler, err := newCustomHandler(&failingWriter{}, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("lost")
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-entered:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected both goroutines' failures to be reported, got %d", i)
+		}
+	}
+	close(release)
+	wg.Wait()
+}
+
+// stringerValue implements fmt.Stringer
+type stringerValue struct{ ID int }
+
+func (s stringerValue) String() string { return fmt.Sprintf("stringer-%d", s.ID) }
+
+// stringerError implements both error and fmt.Stringer
+type stringerError struct{}
+
+func (stringerError) Error() string  { return "error text" }
+func (stringerError) String() string { return "stringer text" }
+
+// TestCustomHandler_StringerAndErrorValues tests how Stringer and error attribute values are rendered
+func TestCustomHandler_StringerAndErrorValues(t *testing.T) {
+	render := func(asIs bool, args ...any) string {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithStringerAsIs(asIs)(cfg)
+		handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+			format:    FormatCustom,
+			formatter: "{attrs}",
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		slog.New(handler).Info("msg", args...)
+		return strings.TrimSpace(buf.String())
+	}
+
+	t.Run("Stringer expanded by default", func(t *testing.T) {
+		if got := render(false, "v", stringerValue{ID: 7}); got != "v=stringer-7" {
+			t.Errorf("Expected String() output, got %q", got)
+		}
+	})
+
+	t.Run("Stringer as Go value", func(t *testing.T) {
+		got := render(true, "v", stringerValue{ID: 7})
+		if !strings.Contains(got, "stringerValue{ID:7}") {
+			t.Errorf("Expected Go value, got %q", got)
+		}
+	})
+
+	t.Run("Error uses Error()", func(t *testing.T) {
+		if got := render(false, "err", errors.New("boom")); got != "err=boom" {
+			t.Errorf("Expected Error() output, got %q", got)
+		}
+	})
+
+	t.Run("Error that is also a Stringer uses Error()", func(t *testing.T) {
+		for _, asIs := range []bool{false, true} {
+			if got := render(asIs, "err", stringerError{}); got != "err=error text" {
+				t.Errorf("asIs=%v: expected Error() output, got %q", asIs, got)
+			}
+		}
+	})
+
+	t.Run("Nil pointer Stringer does not panic", func(t *testing.T) {
+		var p *bytes.Buffer
+		got := render(false, "p", p)
+		if !strings.HasPrefix(got, "p=") {
+			t.Errorf("Expected rendered attribute, got %q", got)
+		}
+	})
+
+	t.Run("Plain values unchanged", func(t *testing.T) {
+		if got := render(false, "n", 42, "s", "text"); got != "n=42 s=text" {
+			t.Errorf("Unexpected output %q", got)
+		}
+	})
+}
+
+func TestCustomHandler_TimeZoneInTimestamp(t *testing.T) {
+	zone := time.FixedZone("IST", 5*3600+1800)
+	recordTime := time.Date(2025, 1, 2, 10, 4, 5, 0, time.UTC)
+
+	render := func(opts ...Option) string {
+		cfg := DefaultConfig()
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		if err := validateConfig(cfg); err != nil {
+			t.Fatalf("validateConfig() failed: %v", err)
+		}
+		var buf bytes.Buffer
+		handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{format: FormatCustom, formatter: "{time} {message}"}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		if err := handler.Handle(context.Background(), slog.NewRecord(recordTime, slog.LevelInfo, "hello", 0)); err != nil {
+			t.Fatalf("Handler.Handle failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	t.Run("Offset appended in the configured zone", func(t *testing.T) {
+		got := render(WithTimeZone(zone), WithTimeZoneInTimestamp(true))
+		if want := "2025/01/02 15:34:05 +05:30 hello\n"; got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("Layout with a zone is left alone", func(t *testing.T) {
+		got := render(WithTimeZone(zone), WithTimeFormat("15:04:05 MST"), WithTimeZoneInTimestamp(true))
+		if want := "15:34:05 IST hello\n"; got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		got := render(WithTimeZone(zone))
+		if want := "2025/01/02 15:34:05 hello\n"; got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	})
+}
+
+func TestDescribeTemplate(t *testing.T) {
+	got := DescribeTemplate("[{time}] {level}: {message}{attrs}")
+	want := []Token{
+		{Type: TokenTypeText, Text: "["},
+		{Type: TokenTypeTime},
+		{Type: TokenTypeText, Text: "] "},
+		{Type: TokenTypeLevel},
+		{Type: TokenTypeText, Text: ": "},
+		{Type: TokenTypeMessage},
+		{Type: TokenTypeAttrs},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("DescribeTemplate() = %v, want %v", got, want)
+	}
+
+	if s := fmt.Sprint(got); s != `["[" {time} "] " {level} ": " {message} {attrs}]` {
+		t.Errorf("Unexpected description %s", s)
+	}
+	if s := fmt.Sprint(DescribeTemplate("{level} {unknown}")); s != `[{level} " {unknown}"]` {
+		t.Errorf("Expected unknown placeholders to stay literal text, got %s", s)
+	}
+	if got := DescribeTemplate(""); !slices.Equal(got, parseTemplate(DefaultFormatter).tokens) {
+		t.Errorf("Expected empty template to describe DefaultFormatter, got %v", got)
+	}
+}
+
+func TestCustomHandler_PerDestinationTimeZone(t *testing.T) {
+	cfg := DefaultConfig()
+	WithTimeFormat("15:04 -07:00")(cfg)
+	WithTimeZone(time.FixedZone("GLOBAL", 2*3600))(cfg)
+	WithConsoleTimeZone(time.FixedZone("LOCAL", -4*3600))(cfg)
+	WithFileTimeZone(time.UTC)(cfg)
+	WithConsoleFormatter("{time} {message}")(cfg)
+	WithFileFormatter("{time} {message}")(cfg)
+	WithConsoleColor(false)(cfg)
+
+	var console, file, global bytes.Buffer
+	consoleHandler, err := newCustomHandler(&console, cfg, &cfg.Console, nil)
+	if err != nil {
+		t.Fatalf("Failed to create console handler: %v", err)
+	}
+	fileHandler, err := newCustomHandler(&file, cfg, &cfg.File, nil)
+	if err != nil {
+		t.Fatalf("Failed to create file handler: %v", err)
+	}
+	globalHandler, err := newCustomHandler(&global, cfg, &mockOutputConfig{format: FormatCustom, formatter: "{time} {message}"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	record := slog.NewRecord(time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC), slog.LevelInfo, "tick", 0)
+	if err := newMultiHandler(consoleHandler, fileHandler, globalHandler).Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+
+	if got := console.String(); got != "08:30 -04:00 tick\n" {
+		t.Errorf("Unexpected console output %q", got)
+	}
+	if got := file.String(); got != "12:30 +00:00 tick\n" {
+		t.Errorf("Unexpected file output %q", got)
+	}
+	if got := global.String(); got != "14:30 +02:00 tick\n" {
+		t.Errorf("Expected the global zone without an override, got %q", got)
+	}
+}
+
+func TestCustomHandler_ColorDecider(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	WithColorDecider(func(r slog.Record) string {
+		slow := false
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == "slow" && a.Value.Kind() == slog.KindBool {
+				slow = a.Value.Bool()
+				return false
+			}
+			return true
+		})
+		if slow {
+			return ansiBrightYellow
+		}
+		return ""
+	})(cfg)
+
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		color:     true,
+		formatter: "{level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+	logger.Info("query", "slow", true)
+	logger.Info("query", "slow", false)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	wantSlow := ansiBrightYellow + "INFO" + ansiReset + " " + ansiBrightYellow + "query" + ansiReset
+	if lines[0] != wantSlow {
+		t.Errorf("Expected slow record colored by the decider, got %q", lines[0])
+	}
+	wantDefault := ProfileDefault.Info + "INFO" + ansiReset + " query"
+	if lines[1] != wantDefault {
+		t.Errorf("Expected level-based color when the decider returns empty, got %q", lines[1])
+	}
+
+	// The decider is not consulted without color
+	buf.Reset()
+	handler, _ = newCustomHandler(&buf, cfg, &mockOutputConfig{format: FormatCustom, formatter: "{level} {message}"}, nil)
+	slog.New(handler).Info("query", "slow", true)
+	if got := strings.TrimSpace(buf.String()); got != "INFO query" {
+		t.Errorf("Expected uncolored output, got %q", got)
+	}
+}
+
+func TestCustomHandler_PidPlaceholder(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{pid} {level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	slog.New(handler).With("k", "v").Info("hello")
+
+	want := fmt.Sprintf("%d INFO hello", os.Getpid())
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// Colored like {file}
+	buf.Reset()
+	handler, _ = newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		color:     true,
+		formatter: "{pid}",
+	}, nil)
+	slog.New(handler).Info("hello")
+	if got := strings.TrimSpace(buf.String()); got != ProfileDefault.Muted+strconv.Itoa(os.Getpid())+ansiReset {
+		t.Errorf("Expected a muted pid, got %q", got)
+	}
+}
+
+func TestCustomHandler_HostnamePlaceholder(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("os.Hostname failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	handler, err := newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{hostname} {level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	slog.New(handler).WithGroup("g").Info("hello")
+
+	if got, want := strings.TrimSpace(buf.String()), host+" INFO hello"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	buf.Reset()
+	handler, _ = newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		color:     true,
+		formatter: "{hostname}",
+	}, nil)
+	slog.New(handler).Info("hello")
+	if got := strings.TrimSpace(buf.String()); got != ProfileDefault.Muted+host+ansiReset {
+		t.Errorf("Expected a muted hostname, got %q", got)
+	}
+}
+
+func TestCustomHandler_AttrsBeforeMessage(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{attrs} {level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	logger.Info("with attrs", "user", "alice", "count", 3)
+	logger.Info("without attrs")
+	logger.With("service", "api").Warn("preset attrs")
+
+	want := []string{
+		"user=alice count=3 INFO with attrs",
+		"INFO without attrs",
+		"service=api WARN preset attrs",
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if !slices.Equal(lines, want) {
+		t.Errorf("Expected lines %q, got %q", want, lines)
+	}
+
+	// Trailing empty placeholders leave no trailing space either
+	buf.Reset()
+	handler, _ = newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{level} {message} {file} {attrs}",
+	}, nil)
+	slog.New(handler).Info("plain")
+	if got := buf.String(); got != "INFO plain\n" {
+		t.Errorf("Expected %q, got %q", "INFO plain\n", got)
+	}
+}
+
+func TestCustomHandler_LiteralTemplate(t *testing.T) {
+	const formatter = "  {attrs} [{file}]  {level}{message} {pkg}  "
+	render := func(literal bool) string {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithLiteralTemplate(literal)(cfg)
+		handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+			format:    FormatCustom,
+			formatter: formatter,
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		slog.New(handler).Info("msg")
+		return buf.String()
+	}
+
+	// No attrs, no source and no {pkg} without AddSource: every placeholder but level and message is empty
+	if got, want := render(true), "   []  INFOmsg   \n"; got != want {
+		t.Errorf("Expected byte-exact literal rendering %q, got %q", want, got)
+	}
+	// The default rendering drops the space before the empty {pkg}
+	if got, want := render(false), "   []  INFOmsg  \n"; got != want {
+		t.Errorf("Expected the default rendering %q, got %q", want, got)
+	}
+}
+
+func TestCustomHandler_GoroutinePlaceholder(t *testing.T) {
+	var buf safeBuffer
+	handler, err := newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "[{goroutine}] {level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	// Two goroutines log, each line carries its own numeric ID
+	logger.Info("main")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("other")
+	}()
+	<-done
+
+	pattern := regexp.MustCompile(`^\[(\d+)\] INFO (main|other)$`)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	var ids []string
+	for _, line := range lines {
+		m := pattern.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("Line %q does not match the goroutine format", line)
+		}
+		ids = append(ids, m[1])
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("Expected different goroutine IDs, got %s twice", ids[0])
+	}
+	if ids[0] != goroutineID() {
+		t.Errorf("Expected the logging goroutine's ID %s, got %s", goroutineID(), ids[0])
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// TestCustomHandler_CanonicalOrder tests that canonical order output is independent of the
+// formatter layout and attribute order, against testdata/canonical_order.golden
+func TestCustomHandler_CanonicalOrder(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	source := &slog.Source{Function: "example.com/app.handle", File: "/src/app/main.go", Line: 42}
+
+	render := func(formatter string, records func(*slog.Logger)) string {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithTimeZone(time.UTC)(cfg)
+		WithCanonicalOrder(true)(cfg)
+		handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{format: FormatCustom, formatter: formatter}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		records(slog.New(&fixedTimeHandler{Handler: handler, at: at}))
+		return buf.String()
+	}
+
+	got := render("{attrs} | {message} | {level} | {time}", func(l *slog.Logger) {
+		l.With("zone", "eu").Info("request done", "status", 200, "method", "GET", "req", slog.GroupValue(
+			slog.String("path", "/login"), slog.Int("bytes", 512),
+		))
+		l.Warn("slow", slog.Any(slog.SourceKey, source), "took", "1.5s", "attempt", 2)
+		l.Error("no attrs")
+	})
+	// The same records with attributes in another order and a different layout
+	reordered := render("{time} {message} {attrs} {level}", func(l *slog.Logger) {
+		l.With("zone", "eu").Info("request done", "req", slog.GroupValue(
+			slog.Int("bytes", 512), slog.String("path", "/login"),
+		), "method", "GET", "status", 200)
+		l.Warn("slow", "attempt", 2, "took", "1.5s", slog.Any(slog.SourceKey, source))
+		l.Error("no attrs")
+	})
+	if got != reordered {
+		t.Errorf("Expected identical output regardless of layout and attribute order:\n%s\nvs\n%s", got, reordered)
+	}
+
+	golden := filepath.Join("testdata", "canonical_order.golden")
+	if *updateGolden {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("Output differs from %s:\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+// fixedTimeHandler stamps every record with the same time, for reproducible output
+type fixedTimeHandler struct {
+	slog.Handler
+	at time.Time
+}
+
+func (h *fixedTimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.Time = h.at
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *fixedTimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &fixedTimeHandler{Handler: h.Handler.WithAttrs(attrs), at: h.at}
+}
+
+// TestCustomHandler_SourceFormat tests the path shape of {file} in each source format
+func TestCustomHandler_SourceFormat(t *testing.T) {
+	_, thisFile, _, _ := runtime.Caller(0)
+	tests := []struct {
+		mode SourceFormat
+		want string
+	}{
+		{"", "custom_handler_test.go:"},
+		{SourceShort, "custom_handler_test.go:"},
+		{SourceFull, thisFile + ":"},
+		{SourcePackage, filepath.Base(filepath.Dir(thisFile)) + "/custom_handler_test.go:"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			var buf bytes.Buffer
+			log, err := New(
+				WithConsole(false),
+				WithWriter(&buf, FormatCustom),
+				WithFileFormatter("{file}"),
+				WithAddSource(true),
+				WithSourceFormat(tt.mode),
+			)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			defer log.Close()
+
+			log.Info("where")
+			out := strings.TrimSpace(buf.String())
+			if !strings.HasPrefix(out, tt.want) || !strings.Contains(out, "TestCustomHandler_SourceFormat") {
+				t.Errorf("Expected {file} to start with %q, got %q", tt.want, out)
+			}
+		})
+	}
+
+	if got := sourceFile("main.go", SourcePackage); got != "main.go" {
+		t.Errorf("Expected a bare file name to stay as is, got %q", got)
+	}
+	if _, err := New(WithSourceFormat("relative")); err == nil {
+		t.Error("Expected an error for an unknown source format")
+	}
+}
@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SuppressedDuplicate is the suppression report reason for records collapsed by WithDedup
+const SuppressedDuplicate = "duplicate"
+
+// dedupState tracks the current run of identical consecutive records, shared by all
+// handlers derived via WithAttrs/WithGroup so runs are detected across them
+type dedupState struct {
+	mu         sync.Mutex
+	window     time.Duration
+	now        func() time.Time // clock for windows, replaceable in tests
+	suppressed *suppressionStats
+
+	key     uint64       // Fingerprint of the run's record
+	start   time.Time    // When the run's first record was emitted
+	count   int          // Repeats suppressed so far
+	level   slog.Level   // Level and handler of the run, for its summary
+	handler slog.Handler //
+	timer   *time.Timer  // Flushes the summary when the window elapses without a new record
+}
+
+// newDedupState creates the state for WithDedup; Close flushes a pending summary
+func newDedupState(window time.Duration, suppressed *suppressionStats) *dedupState {
+	return &dedupState{window: window, now: time.Now, suppressed: suppressed}
+}
+
+// dedupSummary is the "last message repeated N times" record of an ended run. It is built
+// under the state's lock and emitted after releasing it, so a handler that logs through the
+// same logger (e.g. an error callback) doesn't deadlock.
+type dedupSummary struct {
+	handler slog.Handler
+	record  slog.Record
+}
+
+// emit writes the summary, if there is one
+func (d *dedupSummary) emit() {
+	if d != nil {
+		_ = d.handler.Handle(context.Background(), d.record)
+	}
+}
+
+// admit reports whether a record with this fingerprint is emitted. A different record, or
+// the same one after the window, ends the current run; its summary is returned for the
+// caller to emit before the record.
+func (s *dedupState) admit(key uint64, level slog.Level, handler slog.Handler) (bool, *dedupSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.handler != nil && key == s.key && now.Sub(s.start) < s.window {
+		s.count++
+		if s.count == 1 {
+			s.timer = time.AfterFunc(s.window-now.Sub(s.start), s.expire)
+		}
+		s.suppressed.add(SuppressedDuplicate)
+		return false, nil
+	}
+	summary := s.flushLocked()
+	s.key, s.start, s.level, s.handler = key, now, level, handler
+	return true, summary
+}
+
+// expire flushes the run once its window elapsed
+func (s *dedupState) expire() {
+	s.mu.Lock()
+	var summary *dedupSummary
+	if s.count > 0 && s.now().Sub(s.start) >= s.window {
+		summary = s.flushLocked()
+		s.handler = nil // The next record starts a fresh run
+	}
+	s.mu.Unlock()
+	summary.emit()
+}
+
+// flushLocked ends the current run and returns its summary, nil if nothing was suppressed
+func (s *dedupState) flushLocked() *dedupSummary {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.count == 0 {
+		return nil
+	}
+	n := s.count
+	s.count = 0
+	return &dedupSummary{
+		handler: s.handler,
+		record:  slog.NewRecord(s.now(), s.level, fmt.Sprintf("last message repeated %d times", n), 0),
+	}
+}
+
+// Close flushes the summary of a run still in progress
+func (s *dedupState) Close() error {
+	s.mu.Lock()
+	summary := s.flushLocked()
+	s.handler = nil
+	s.mu.Unlock()
+	summary.emit()
+	return nil
+}
+
+var _ io.Closer = (*dedupState)(nil)
+
+// dedupHandler is a slog.Handler that collapses identical consecutive records
+type dedupHandler struct {
+	handler slog.Handler
+	scope   string // Fingerprint of the attributes and groups added via WithAttrs/WithGroup
+	state   *dedupState
+}
+
+// newDedupHandler wraps a handler so records identical to the previous one within the
+// state's window are counted instead of written
+func newDedupHandler(handler slog.Handler, state *dedupState) slog.Handler {
+	return &dedupHandler{handler: handler, state: state}
+}
+
+// fingerprint hashes what makes two records identical: level, message and attributes
+func (h *dedupHandler) fingerprint(r slog.Record) uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(h.scope))
+	f.Write([]byte(strconv.Itoa(int(r.Level))))
+	f.Write([]byte{0})
+	f.Write([]byte(r.Message))
+	r.Attrs(func(a slog.Attr) bool {
+		f.Write([]byte{0})
+		f.Write([]byte(a.String()))
+		return true
+	})
+	return f.Sum64()
+}
+
+// Enabled implements slog.Handler
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	admitted, summary := h.state.admit(h.fingerprint(r), r.Level, h.handler)
+	summary.emit()
+	if !admitted {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	scope := h.scope
+	for _, a := range attrs {
+		scope += "\x00" + a.String()
+	}
+	return &dedupHandler{handler: h.handler.WithAttrs(attrs), scope: scope, state: h.state}
+}
+
+// WithGroup implements slog.Handler
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &dedupHandler{handler: h.handler.WithGroup(name), scope: h.scope + "\x00[" + name, state: h.state}
+}
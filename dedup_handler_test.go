@@ -0,0 +1,201 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithDedup(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(
+		WithConsole(false),
+		WithWriter(&buf, FormatJSON),
+		WithDedup(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	for i := 0; i < 5; i++ {
+		log.Warn("connection refused", "host", "db1")
+	}
+	log.Info("connection restored")
+
+	entries := decodeJSONLines(t, &buf)
+	if len(entries) != 3 {
+		t.Fatalf("Expected the first record, a summary and the new record, got %d: %v", len(entries), entries)
+	}
+	if entries[0]["msg"] != "connection refused" || entries[0]["host"] != "db1" {
+		t.Errorf("Unexpected first record: %v", entries[0])
+	}
+	if entries[1]["msg"] != "last message repeated 4 times" || entries[1]["level"] != "WARN" {
+		t.Errorf("Unexpected summary: %v", entries[1])
+	}
+	if entries[2]["msg"] != "connection restored" {
+		t.Errorf("Unexpected last record: %v", entries[2])
+	}
+}
+
+func TestDedupHandler_DistinctRecords(t *testing.T) {
+	capture := NewCaptureHandler(slog.LevelDebug)
+	state := newDedupState(time.Hour, nil)
+	logger := slog.New(newDedupHandler(capture, state))
+
+	logger.Info("request", "path", "/a")
+	logger.Info("request", "path", "/b") // Different attributes
+	logger.Warn("request", "path", "/b") // Different level
+	logger.With("user", "alice").Warn("request", "path", "/b")
+	logger.WithGroup("g").Warn("request", "path", "/b")
+
+	if n := len(capture.Records()); n != 5 {
+		t.Errorf("Expected all 5 distinct records, got %d", n)
+	}
+}
+
+func TestDedupHandler_WindowElapsed(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	advance := func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+
+	capture := NewCaptureHandler(slog.LevelDebug)
+	state := newDedupState(time.Minute, nil)
+	state.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	logger := slog.New(newDedupHandler(capture, state))
+
+	logger.Error("disk full")
+	logger.Error("disk full")
+	logger.Error("disk full")
+	advance(time.Minute)
+	logger.Error("disk full") // A new run after the window
+
+	records := capture.Records()
+	if len(records) != 3 {
+		t.Fatalf("Expected record, summary and record, got %d", len(records))
+	}
+	if records[1].Message != "last message repeated 2 times" || records[1].Level != slog.LevelError {
+		t.Errorf("Unexpected summary: %s %s", records[1].Level, records[1].Message)
+	}
+
+	// The summary of a run still open is flushed on Close
+	logger.Error("disk full")
+	if err := state.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	records = capture.Records()
+	if len(records) != 4 || records[3].Message != "last message repeated 1 times" {
+		t.Errorf("Expected a summary on Close, got %d records", len(records))
+	}
+}
+
+func TestDedupHandler_TimerFlush(t *testing.T) {
+	capture := NewCaptureHandler(slog.LevelDebug)
+	state := newDedupState(20*time.Millisecond, nil)
+	logger := slog.New(newDedupHandler(capture, state))
+	defer state.Close()
+
+	logger.Info("tick")
+	logger.Info("tick")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(capture.Records()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	records := capture.Records()
+	if len(records) != 2 || records[1].Message != "last message repeated 1 times" {
+		t.Fatalf("Expected the summary once the window elapsed, got %d records", len(records))
+	}
+
+	logger.Info("tick") // Starts a fresh run rather than being suppressed
+	if n := len(capture.Records()); n != 3 {
+		t.Errorf("Expected the record after the flush to be written, got %d records", n)
+	}
+}
+
+func TestDedupHandler_Concurrent(t *testing.T) {
+	capture := NewCaptureHandler(slog.LevelDebug)
+	state := newDedupState(time.Hour, nil)
+	logger := slog.New(newDedupHandler(capture, state))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Info("same")
+			}
+		}()
+	}
+	wg.Wait()
+	state.Close()
+
+	records := capture.Records()
+	if len(records) != 2 || records[1].Message != "last message repeated 799 times" {
+		t.Errorf("Expected one record and one summary, got %d records", len(records))
+	}
+}
+
+// reentrantHandler logs through logger while handling a summary, as an error callback
+// reporting a failed summary write through the same logger would
+type reentrantHandler struct {
+	*CaptureHandler
+	logger *slog.Logger
+}
+
+func (h *reentrantHandler) Handle(ctx context.Context, r slog.Record) error {
+	if strings.HasPrefix(r.Message, "last message repeated") {
+		h.logger.Error("summary write failed")
+	}
+	return h.CaptureHandler.Handle(ctx, r)
+}
+
+func TestDedupHandler_ReentrantSummary(t *testing.T) {
+	h := &reentrantHandler{CaptureHandler: NewCaptureHandler(slog.LevelDebug)}
+	state := newDedupState(time.Hour, nil)
+	logger := slog.New(newDedupHandler(h, state))
+	h.logger = logger
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Info("same")
+		logger.Info("same")
+		logger.Info("different") // Ends the run, the summary logs through logger
+		state.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Logging from a handler while emitting a summary deadlocked")
+	}
+
+	var messages []string
+	for _, r := range h.Records() {
+		messages = append(messages, r.Message)
+	}
+	want := []string{"same", "summary write failed", "last message repeated 1 times", "different"}
+	if !slices.Equal(messages, want) {
+		t.Errorf("Expected %v, got %v", want, messages)
+	}
+}
+
+func TestWithDedup_Validation(t *testing.T) {
+	if _, err := New(WithDedup(-time.Second)); err == nil {
+		t.Error("Expected an error for a negative dedup window")
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// dualHandler is a slog.Handler that writes each record twice to the same destination:
+// a human-readable line immediately followed by a structured one
+type dualHandler struct {
+	mu         *sync.Mutex // Shared by derived handlers so the two lines of a record stay adjacent
+	human      slog.Handler
+	structured slog.Handler
+}
+
+// newDualHandler pairs a human and a structured handler writing to the same writer
+func newDualHandler(human, structured slog.Handler) slog.Handler {
+	return &dualHandler{mu: &sync.Mutex{}, human: human, structured: structured}
+}
+
+// Enabled implements slog.Handler
+func (h *dualHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.human.Enabled(ctx, level) || h.structured.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *dualHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Both lines render clones of the same record snapshot
+	var errs []error
+	if h.human.Enabled(ctx, r.Level) {
+		if err := h.human.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if h.structured.Enabled(ctx, r.Level) {
+		if err := h.structured.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler
+func (h *dualHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &dualHandler{mu: h.mu, human: h.human.WithAttrs(attrs), structured: h.structured.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h *dualHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &dualHandler{mu: h.mu, human: h.human.WithGroup(name), structured: h.structured.WithGroup(name)}
+}
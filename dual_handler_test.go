@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDualHandler_HumanThenJSON(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	WithDualOutput("{level} {message} {attrs}")(cfg)
+	opts := newHandlerOptions(cfg)
+
+	human, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: cfg.Console.Formatter,
+	}, opts)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(newDualHandler(human, slog.NewJSONHandler(&buf, opts))).With("svc", "api")
+
+	logger.Info("first", "n", 1)
+	logger.Info("second", "n", 2)
+	logger.Debug("filtered")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 2 lines per Info call, got %d: %q", len(lines), buf.String())
+	}
+	for i, want := range []struct {
+		msg string
+		n   float64
+	}{{"first", 1}, {"second", 2}} {
+		humanLine, jsonLine := lines[2*i], lines[2*i+1]
+		if !strings.HasPrefix(humanLine, "INFO "+want.msg) {
+			t.Errorf("Expected human line for %q, got %q", want.msg, humanLine)
+		}
+
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(jsonLine), &entry); err != nil {
+			t.Fatalf("Expected JSON line after human line, got %q: %v", jsonLine, err)
+		}
+		if entry["msg"] != want.msg || entry["n"] != want.n || entry["svc"] != "api" {
+			t.Errorf("JSON line does not match the human line's record: %v", entry)
+		}
+	}
+}
+
+func TestWithDualOutput_ConsoleHandler(t *testing.T) {
+	cfg := DefaultConfig()
+	WithDualOutput("")(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("validateConfig() failed: %v", err)
+	}
+	if cfg.Console.Formatter != DefaultFormatter {
+		t.Errorf("Expected empty template to keep the default formatter, got %q", cfg.Console.Formatter)
+	}
+
+	handler, err := newConsoleHandler(cfg)
+	if err != nil {
+		t.Fatalf("newConsoleHandler() failed: %v", err)
+	}
+	if _, ok := handler.(*dualHandler); !ok {
+		t.Errorf("Expected a dualHandler, got %T", handler)
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ElapsedKey is the attribute key for milliseconds since the logger was created
+const ElapsedKey = "elapsed_ms"
+
+// elapsedHandler is a slog.Handler that adds the time elapsed since a start time to each record
+type elapsedHandler struct {
+	handler   slog.Handler
+	startTime time.Time
+}
+
+// newElapsedHandler wraps a handler so each record carries an elapsed_ms attribute
+func newElapsedHandler(handler slog.Handler, startTime time.Time) slog.Handler {
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+	return &elapsedHandler{handler: handler, startTime: startTime}
+}
+
+// Enabled implements slog.Handler
+func (h *elapsedHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *elapsedHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(slog.Int64(ElapsedKey, time.Since(h.startTime).Milliseconds()))
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *elapsedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &elapsedHandler{handler: h.handler.WithAttrs(attrs), startTime: h.startTime}
+}
+
+// WithGroup implements slog.Handler
+func (h *elapsedHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &elapsedHandler{handler: h.handler.WithGroup(name), startTime: h.startTime}
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestElapsedPlaceholder(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.startTime = time.Now()
+
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{elapsed} {level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	logger.Info("first")
+	time.Sleep(20 * time.Millisecond)
+	logger.Info("second")
+
+	pattern := regexp.MustCompile(`^(\d+)ms INFO (first|second)$`)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	var values []int
+	for _, line := range lines {
+		m := pattern.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("Line %q does not match elapsed format", line)
+		}
+		v, _ := strconv.Atoi(m[1])
+		values = append(values, v)
+	}
+	if values[1] <= values[0] {
+		t.Errorf("Expected elapsed to increase, got %d then %d", values[0], values[1])
+	}
+	if values[1]-values[0] < 20 {
+		t.Errorf("Expected at least 20ms between records, got %d", values[1]-values[0])
+	}
+}
+
+func TestElapsedHandler(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Now().Add(-1500 * time.Millisecond)
+	logger := slog.New(newElapsedHandler(slog.NewJSONHandler(&buf, nil), start))
+
+	logger.Info("message")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	elapsed, ok := entry[ElapsedKey].(float64)
+	if !ok {
+		t.Fatalf("Expected %s attribute, got %v", ElapsedKey, entry)
+	}
+	if elapsed < 1500 || elapsed > 5000 {
+		t.Errorf("Expected elapsed_ms around 1500, got %v", elapsed)
+	}
+}
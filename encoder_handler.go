@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Encoder turns a record into the exact bytes written to a destination.
+// Attributes in r are complete and already nested in their groups, including those bound
+// with With; groups lists the groups currently open, for encoders that want them.
+type Encoder func(r slog.Record, groups []string) ([]byte, error)
+
+// encoderHandler is a slog.Handler that delegates all serialization to an Encoder
+type encoderHandler struct {
+	mu      *sync.Mutex // Shared by derived handlers so writes don't interleave
+	out     io.Writer
+	encoder Encoder
+	level   slog.Leveler
+	onWrite func(level slog.Level, bytesWritten int)
+	onError func(err error, r slog.Record)
+	groups  []string
+	attrs   []slog.Attr // Bound attributes, already nested in the groups open when they were added
+}
+
+// newEncoderHandler creates a handler writing the encoder's bytes directly to w
+func newEncoderHandler(w io.Writer, cfg *Config) slog.Handler {
+	return &encoderHandler{
+		mu:      &sync.Mutex{},
+		out:     w,
+		encoder: cfg.Encoder,
+		level:   cfg.leveler(),
+		onWrite: cfg.OnRecord,
+		onError: cfg.reportWriteError,
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *encoderHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.level != nil {
+		minLevel = h.level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler
+func (h *encoderHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	record.AddAttrs(h.attrs...)
+
+	var own []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		own = append(own, a)
+		return true
+	})
+	record.AddAttrs(nestInGroups(h.groups, own)...)
+
+	data, err := h.encoder(record, slices.Clone(h.groups))
+	if err != nil {
+		return fmt.Errorf("encoder error: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	h.mu.Lock()
+	n, err := h.out.Write(data)
+	h.mu.Unlock()
+
+	if err != nil {
+		runCallback(ctx, func() { h.onError(err, r) })
+	} else if h.onWrite != nil {
+		runCallback(ctx, func() { h.onWrite(r.Level, n) })
+	}
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *encoderHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.attrs = append(slices.Clip(h.attrs), nestInGroups(h.groups, attrs)...)
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *encoderHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(slices.Clip(h.groups), name)
+	return &clone
+}
+
+// nestInGroups wraps attrs in the given groups, outermost first
+func nestInGroups(groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+	return attrs
+}
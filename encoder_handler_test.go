@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pipeEncoder renders "LEVEL|message|key=value,..." with dotted group keys
+func pipeEncoder(r slog.Record, groups []string) ([]byte, error) {
+	var fields []string
+	var walk func(prefix string, a slog.Attr)
+	walk = func(prefix string, a slog.Attr) {
+		if a.Value.Kind() == slog.KindGroup {
+			for _, ga := range a.Value.Group() {
+				walk(prefix+a.Key+".", ga)
+			}
+			return
+		}
+		fields = append(fields, prefix+a.Key+"="+a.Value.String())
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		walk("", a)
+		return true
+	})
+	return []byte(fmt.Sprintf("%s|%s|%s|%s\n", r.Level, r.Message, strings.Join(fields, ","), strings.Join(groups, "/"))), nil
+}
+
+func TestEncoderHandler_ExactBytes(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.Encoder = pipeEncoder
+	logger := slog.New(newEncoderHandler(&buf, cfg))
+
+	logger.Info("plain", "a", 1)
+	logger.With("svc", "api").WithGroup("req").With("id", 7).Warn("grouped", "path", "/x")
+	logger.Debug("filtered")
+
+	want := "INFO|plain|a=1|\n" +
+		"WARN|grouped|svc=api,req.id=7,req.path=/x|req\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Unexpected encoder output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestEncoderHandler_Error(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	encodeErr := errors.New("boom")
+	cfg.Encoder = func(slog.Record, []string) ([]byte, error) { return nil, encodeErr }
+	handler := newEncoderHandler(&buf, cfg)
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	if err := handler.Handle(context.Background(), record); !errors.Is(err, encodeErr) {
+		t.Errorf("Expected encoder error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written on error, got %q", buf.String())
+	}
+}
+
+func TestWithEncoder_File(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "encoded.log")
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(logPath),
+		WithEncoder(func(r slog.Record, _ []string) ([]byte, error) {
+			return []byte{0x01, byte(len(r.Message)), 'm', 's', 'g', 0x00}, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("msg")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if want := []byte{0x01, 0x03, 'm', 's', 'g', 0x00}; !bytes.Equal(content, want) {
+		t.Errorf("Expected %v, got %v", want, content)
+	}
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+)
+
+// EntryIDKey is the attribute key used for per-record unique IDs
+const EntryIDKey = "log_id"
+
+// entryIDHandler is a slog.Handler that adds a unique ID attribute to each record
+type entryIDHandler struct {
+	handler   slog.Handler
+	generator func() string
+}
+
+// newEntryIDHandler wraps a handler so each record carries a log_id attribute
+func newEntryIDHandler(handler slog.Handler, generator func() string) slog.Handler {
+	if generator == nil {
+		generator = newUUID
+	}
+	return &entryIDHandler{handler: handler, generator: generator}
+}
+
+// Enabled implements slog.Handler
+func (h *entryIDHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *entryIDHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(slog.String(EntryIDKey, h.generator()))
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *entryIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &entryIDHandler{handler: h.handler.WithAttrs(attrs), generator: h.generator}
+}
+
+// WithGroup implements slog.Handler
+func (h *entryIDHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &entryIDHandler{handler: h.handler.WithGroup(name), generator: h.generator}
+}
+
+// newUUID returns a random (version 4) UUID string
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000-0000-0000-0000-000000000000"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestEntryIDHandler(t *testing.T) {
+	t.Run("Unique IDs across records", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newEntryIDHandler(slog.NewJSONHandler(&buf, nil), nil))
+
+		const count = 50
+		for i := 0; i < count; i++ {
+			logger.Info("message", "i", i)
+		}
+
+		uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		seen := make(map[string]bool)
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry map[string]any
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Failed to parse JSON line %q: %v", line, err)
+			}
+			id, ok := entry[EntryIDKey].(string)
+			if !ok {
+				t.Fatalf("Expected %s attribute in %q", EntryIDKey, line)
+			}
+			if !uuidPattern.MatchString(id) {
+				t.Errorf("Expected UUID v4 format, got %q", id)
+			}
+			if seen[id] {
+				t.Errorf("Duplicate entry ID %q", id)
+			}
+			seen[id] = true
+		}
+		if len(seen) != count {
+			t.Errorf("Expected %d unique IDs, got %d", count, len(seen))
+		}
+	})
+
+	t.Run("Custom generator", func(t *testing.T) {
+		var buf bytes.Buffer
+		n := 0
+		generator := func() string {
+			n++
+			return fmt.Sprintf("id-%d", n)
+		}
+		logger := slog.New(newEntryIDHandler(slog.NewTextHandler(&buf, nil), generator))
+
+		logger.Info("first")
+		logger.With("key", "value").Info("second")
+
+		output := buf.String()
+		if !strings.Contains(output, "log_id=id-1") || !strings.Contains(output, "log_id=id-2") {
+			t.Errorf("Expected generated IDs in output, got %q", output)
+		}
+	})
+
+	t.Run("Shared ID across destinations", func(t *testing.T) {
+		var buf1, buf2 bytes.Buffer
+		cfg := DefaultConfig()
+		WithEntryID(true)(cfg)
+		WithIDGenerator(func() string { return "shared" })(cfg)
+
+		handler := wrapHandler(newMultiHandler(
+			slog.NewTextHandler(&buf1, nil),
+			slog.NewTextHandler(&buf2, nil),
+		), cfg)
+		slog.New(handler).Info("message")
+
+		for _, out := range []string{buf1.String(), buf2.String()} {
+			if !strings.Contains(out, "log_id=shared") {
+				t.Errorf("Expected shared log_id in every destination, got %q", out)
+			}
+		}
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler := wrapHandler(slog.NewTextHandler(&buf, nil), DefaultConfig())
+		slog.New(handler).Info("message")
+
+		if strings.Contains(buf.String(), EntryIDKey) {
+			t.Errorf("Expected no log_id by default, got %q", buf.String())
+		}
+	})
+}
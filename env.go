@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by FromEnv
+const (
+	EnvColor      = "LOG_COLOR"       // Console color, a boolean
+	EnvSource     = "LOG_SOURCE"      // AddSource, a boolean
+	EnvTimeFormat = "LOG_TIME_FORMAT" // time.Format layout for {time}
+	EnvTimeZone   = "LOG_TIMEZONE"    // IANA time zone name, e.g. "UTC" or "Europe/Berlin"
+)
+
+// parseEnvBool parses a boolean environment variable, accepting 1/0, true/false and yes/no
+// in any case. name is only used in the error.
+func parseEnvBool(name, value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes":
+		return true, nil
+	case "0", "false", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid value %q for %s: must be one of 1, 0, true, false, yes, no", value, name)
+}
+
+// FromEnv applies settings from the environment, so deployments can change them without code:
+// LOG_COLOR (console color), LOG_SOURCE (AddSource), LOG_TIME_FORMAT and LOG_TIMEZONE.
+// Unset or empty variables leave the configuration alone. Options apply in order, so place
+// FromEnv last for the environment to override values set in code. An invalid value makes
+// New fail with an error naming the variable.
+func FromEnv() Option {
+	return func(c *Config) {
+		if err := applyEnv(c); err != nil && c.envErr == nil {
+			c.envErr = err
+		}
+	}
+}
+
+func applyEnv(c *Config) error {
+	if v := os.Getenv(EnvColor); v != "" {
+		color, err := parseEnvBool(EnvColor, v)
+		if err != nil {
+			return err
+		}
+		c.Console.Color = color
+		c.Console.ColorAuto = false
+	}
+	if v := os.Getenv(EnvSource); v != "" {
+		source, err := parseEnvBool(EnvSource, v)
+		if err != nil {
+			return err
+		}
+		c.AddSource = source
+	}
+	if v := os.Getenv(EnvTimeFormat); v != "" {
+		c.TimeFormat = v
+	}
+	if v := os.Getenv(EnvTimeZone); v != "" {
+		loc, err := time.LoadLocation(v)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: %w", v, EnvTimeZone, err)
+		}
+		c.TimeZone = loc
+	}
+	return nil
+}
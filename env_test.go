@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEnvBool(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{"1", true, false},
+		{"0", false, false},
+		{"true", true, false},
+		{"false", false, false},
+		{"TRUE", true, false},
+		{"False", false, false},
+		{"yes", true, false},
+		{"no", false, false},
+		{" Yes ", true, false},
+		{"NO", false, false},
+		{"on", false, true},
+		{"2", false, true},
+		{"y", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseEnvBool("LOG_TEST", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEnvBool(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "LOG_TEST") {
+				t.Errorf("Expected the error to name the variable, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parseEnvBool(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Run("color", func(t *testing.T) {
+		t.Setenv(EnvColor, "false")
+		cfg := DefaultConfig()
+		WithConsoleColor(true)(cfg)
+		FromEnv()(cfg)
+		if cfg.Console.Color {
+			t.Error("Expected LOG_COLOR=false to disable console color")
+		}
+	})
+
+	t.Run("source", func(t *testing.T) {
+		t.Setenv(EnvSource, "yes")
+		cfg := DefaultConfig()
+		FromEnv()(cfg)
+		if !cfg.AddSource {
+			t.Error("Expected LOG_SOURCE=yes to enable AddSource")
+		}
+	})
+
+	t.Run("time format", func(t *testing.T) {
+		t.Setenv(EnvTimeFormat, time.RFC3339)
+		cfg := DefaultConfig()
+		FromEnv()(cfg)
+		if cfg.TimeFormat != time.RFC3339 {
+			t.Errorf("Expected time format %q, got %q", time.RFC3339, cfg.TimeFormat)
+		}
+	})
+
+	t.Run("time zone", func(t *testing.T) {
+		t.Setenv(EnvTimeZone, "UTC")
+		cfg := DefaultConfig()
+		FromEnv()(cfg)
+		if cfg.TimeZone != time.UTC {
+			t.Errorf("Expected UTC, got %v", cfg.TimeZone)
+		}
+	})
+
+	t.Run("unset leaves config alone", func(t *testing.T) {
+		for _, name := range []string{EnvColor, EnvSource, EnvTimeFormat, EnvTimeZone} {
+			t.Setenv(name, "")
+		}
+		cfg := DefaultConfig()
+		WithConsoleColor(true)(cfg)
+		WithAddSource(true)(cfg)
+		FromEnv()(cfg)
+		if !cfg.Console.Color || !cfg.AddSource || cfg.TimeFormat != DefaultTimeFormat || cfg.TimeZone != time.Local {
+			t.Errorf("Expected defaults to be kept, got color=%v source=%v format=%q zone=%v",
+				cfg.Console.Color, cfg.AddSource, cfg.TimeFormat, cfg.TimeZone)
+		}
+	})
+}
+
+func TestFromEnv_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{EnvColor, "maybe"},
+		{EnvSource, "enabled"},
+		{EnvTimeZone, "Mars/Olympus_Mons"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			_, err := New(WithConsole(true), FromEnv())
+			if err == nil {
+				t.Fatalf("Expected New to fail for %s=%q", tt.name, tt.value)
+			}
+			if !strings.Contains(err.Error(), tt.name) {
+				t.Errorf("Expected the error to name %s, got %v", tt.name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Event accumulates typed attributes for a single record, e.g.
+// l.Event("user login").Str("user", "john").Int("attempt", 2).Info().
+// Attributes are stored as slog.Attr, avoiding the key/value pitfalls of the ...any API.
+// An Event is emitted once by one of its level methods and must not be reused.
+type Event struct {
+	logger *slog.Logger
+	ctx    context.Context
+	msg    string
+	attrs  []slog.Attr
+}
+
+// Event starts a fluent record with message msg
+func (l *Logger) Event(msg string) *Event {
+	return &Event{
+		logger: l.Logger,
+		ctx:    context.Background(),
+		msg:    msg,
+		attrs:  make([]slog.Attr, 0, 8),
+	}
+}
+
+// Ctx sets the context passed to the handler
+func (e *Event) Ctx(ctx context.Context) *Event {
+	e.ctx = ctx
+	return e
+}
+
+// Str adds a string attribute
+func (e *Event) Str(key, value string) *Event {
+	e.attrs = append(e.attrs, slog.String(key, value))
+	return e
+}
+
+// Int adds an int attribute
+func (e *Event) Int(key string, value int) *Event {
+	e.attrs = append(e.attrs, slog.Int(key, value))
+	return e
+}
+
+// Int64 adds an int64 attribute
+func (e *Event) Int64(key string, value int64) *Event {
+	e.attrs = append(e.attrs, slog.Int64(key, value))
+	return e
+}
+
+// Uint64 adds a uint64 attribute
+func (e *Event) Uint64(key string, value uint64) *Event {
+	e.attrs = append(e.attrs, slog.Uint64(key, value))
+	return e
+}
+
+// Float64 adds a float64 attribute
+func (e *Event) Float64(key string, value float64) *Event {
+	e.attrs = append(e.attrs, slog.Float64(key, value))
+	return e
+}
+
+// Bool adds a bool attribute
+func (e *Event) Bool(key string, value bool) *Event {
+	e.attrs = append(e.attrs, slog.Bool(key, value))
+	return e
+}
+
+// Dur adds a time.Duration attribute
+func (e *Event) Dur(key string, value time.Duration) *Event {
+	e.attrs = append(e.attrs, slog.Duration(key, value))
+	return e
+}
+
+// Time adds a time.Time attribute
+func (e *Event) Time(key string, value time.Time) *Event {
+	e.attrs = append(e.attrs, slog.Time(key, value))
+	return e
+}
+
+// Err adds err under the "error" key; a nil error adds nothing
+func (e *Event) Err(err error) *Event {
+	if err != nil {
+		e.attrs = append(e.attrs, slog.Any("error", err))
+	}
+	return e
+}
+
+// Any adds an attribute of any type
+func (e *Event) Any(key string, value any) *Event {
+	e.attrs = append(e.attrs, slog.Any(key, value))
+	return e
+}
+
+// Attrs adds prebuilt attributes
+func (e *Event) Attrs(attrs ...slog.Attr) *Event {
+	e.attrs = append(e.attrs, attrs...)
+	return e
+}
+
+// Debug emits the event at LevelDebug
+func (e *Event) Debug() { e.emit(slog.LevelDebug) }
+
+// Info emits the event at LevelInfo
+func (e *Event) Info() { e.emit(slog.LevelInfo) }
+
+// Warn emits the event at LevelWarn
+func (e *Event) Warn() { e.emit(slog.LevelWarn) }
+
+// Error emits the event at LevelError
+func (e *Event) Error() { e.emit(slog.LevelError) }
+
+// Log emits the event at level
+func (e *Event) Log(level slog.Level) { e.emit(level) }
+
+// emit logs the event for the level methods, with their caller as the source
+func (e *Event) emit(level slog.Level) {
+	logAttrsAt(e.ctx, e.logger, 2, level, e.msg, e.attrs) // skip [emit, level method]
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newEventTestLogger(buf *bytes.Buffer) *Logger {
+	handler := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true})
+	return &Logger{Logger: slog.New(handler)}
+}
+
+func TestEvent_TypedFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newEventTestLogger(&buf)
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.Event("user login").
+		Str("user", "john").
+		Int("attempt", 2).
+		Int64("id", 1<<40).
+		Uint64("quota", 7).
+		Float64("score", 0.5).
+		Bool("admin", true).
+		Dur("took", 1500*time.Millisecond).
+		Time("at", ts).
+		Err(errors.New("denied")).
+		Err(nil).
+		Any("tags", []string{"a", "b"}).
+		Attrs(slog.String("extra", "x")).
+		Info()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"msg":     "user login",
+		"level":   "INFO",
+		"user":    "john",
+		"attempt": float64(2),
+		"id":      float64(1 << 40),
+		"quota":   float64(7),
+		"score":   0.5,
+		"admin":   true,
+		"took":    float64(1500 * time.Millisecond),
+		"at":      ts.Format(time.RFC3339),
+		"error":   "denied",
+		"extra":   "x",
+	}
+	for key, want := range expected {
+		if got := entry[key]; got != want {
+			t.Errorf("%s = %v (%T), want %v (%T)", key, got, got, want, want)
+		}
+	}
+	if tags, ok := entry["tags"].([]any); !ok || len(tags) != 2 {
+		t.Errorf("Expected tags array, got %v", entry["tags"])
+	}
+
+	// The source is the caller of Info, not event.go
+	source, _ := entry[slog.SourceKey].(map[string]any)
+	if file, _ := source["file"].(string); filepath.Base(file) != "event_test.go" {
+		t.Errorf("Expected source in event_test.go, got %v", source)
+	}
+}
+
+func TestEvent_Levels(t *testing.T) {
+	tests := []struct {
+		name  string
+		emit  func(e *Event)
+		level string
+	}{
+		{"Debug", (*Event).Debug, "DEBUG"},
+		{"Info", (*Event).Info, "INFO"},
+		{"Warn", (*Event).Warn, "WARN"},
+		{"Error", (*Event).Error, "ERROR"},
+		{"Log", func(e *Event) { e.Log(slog.LevelError + 4) }, "ERROR+4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newEventTestLogger(&buf)
+			tt.emit(logger.Event("msg").Str("k", "v"))
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+			}
+			if entry["level"] != tt.level || entry["k"] != "v" {
+				t.Errorf("Unexpected record: %v", entry)
+			}
+		})
+	}
+}
+
+func TestEvent_DisabledLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &Logger{Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))}
+
+	logger.Event("quiet").Str("k", "v").Info()
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output below the level, got %q", buf.String())
+	}
+}
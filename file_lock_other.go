@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "os"
+
+// fileLockSupported reports whether lockFile provides inter-process exclusion on this platform
+const fileLockSupported = false
+
+// lockFile is a no-op where flock is unavailable; records then rely on O_APPEND alone
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op where flock is unavailable
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileLockSupported reports whether lockFile provides inter-process exclusion on this platform
+const fileLockSupported = true
+
+// lockFile takes an exclusive advisory lock (flock) on f, blocking until it is available
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile releases the advisory lock taken by lockFile
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+)
+
+// FileOutput is an additional log file registered with WithFileOutput. It rotates on its own
+// and shares the remaining file settings (formatter, buffering, compression, ...) of FileConfig.
+type FileOutput struct {
+	Path          string       // Path to the log file
+	Format        OutputFormat // text, json, custom, syslog or logfmt, defaults to DefaultFormat
+	MaxSizeMB     int          // Rotation size in megabytes, 0 disables rotation, negative uses DefaultMaxSizeMB
+	RetentionDays int          // Days to keep rotated files, 0 or less uses DefaultRetentionDays
+	MinLevel      slog.Level   // Records below this level are not written to the file
+}
+
+// validateFileOutput applies defaults to out and checks it, creating its directory
+func validateFileOutput(cfg *Config, out *FileOutput) error {
+	if out.Path == "" {
+		return fmt.Errorf("file output has no path")
+	}
+	if cfg.PathExpansion {
+		path, err := expandPath(out.Path)
+		if err != nil {
+			return fmt.Errorf("invalid file output path %q: %w", out.Path, err)
+		}
+		out.Path = path
+	}
+	if out.Format == "" {
+		out.Format = DefaultFormat
+	}
+	if !isValidFormat(out.Format) {
+		return fmt.Errorf("unsupported format for file output %s: %s (must be one of: text, json, custom, syslog, logfmt)", out.Path, out.Format)
+	}
+	out.MaxSizeMB = normalizeMaxSizeMB(out.MaxSizeMB)
+	if out.RetentionDays <= 0 {
+		out.RetentionDays = DefaultRetentionDays
+	}
+	return ensureLogDir(out.Path, cfg.File.DirMode)
+}
+
+// checkFileOutputPaths rejects file outputs writing to the main file or to the file of another
+// output: two writers rotating the same file would each miscount its size
+func checkFileOutputPaths(cfg *Config) error {
+	seen := make(map[string]bool, len(cfg.FileOutputs)+1)
+	if cfg.File.Enabled && cfg.File.Path != "" {
+		seen[filepath.Clean(cfg.File.Path)] = true
+	}
+	for _, out := range cfg.FileOutputs {
+		path := filepath.Clean(out.Path)
+		if seen[path] {
+			return fmt.Errorf("file output %s writes to a file already in use (each path may only be used once)", out.Path)
+		}
+		seen[path] = true
+	}
+	return nil
+}
+
+// newFileOutputHandler creates the handler of an additional file output: the file settings
+// of cfg with the output's path, format, rotation and retention, filtered by its minimum level
+func newFileOutputHandler(cfg *Config, out FileOutput) (slog.Handler, io.Closer, error) {
+	fc := cfg.File
+	fc.Path = out.Path
+	fc.Format = out.Format
+	fc.MaxSizeMB = out.MaxSizeMB
+	fc.RetentionDays = out.RetentionDays
+	if fc.Format == FormatCustom && fc.Formatter == "" {
+		fc.Formatter = DefaultFormatter
+	}
+	handler, closer, err := newFileHandlerFrom(cfg, &fc, out.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newMinLevelHandler(newForceLevelHandler(handler), out.MinLevel), closer, nil
+}
+
+// minLevelHandler is a slog.Handler that drops records below a fixed level, even those
+// allowed by a lower global level or ContextWithForceLevel
+type minLevelHandler struct {
+	handler slog.Handler
+	min     slog.Level
+}
+
+// newMinLevelHandler wraps a handler so it only accepts records at min or above
+func newMinLevelHandler(handler slog.Handler, min slog.Level) slog.Handler {
+	return &minLevelHandler{handler: handler, min: min}
+}
+
+// Enabled implements slog.Handler
+func (h *minLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.min && h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *minLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.min {
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *minLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &minLevelHandler{handler: h.handler.WithAttrs(attrs), min: h.min}
+}
+
+// WithGroup implements slog.Handler
+func (h *minLevelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &minLevelHandler{handler: h.handler.WithGroup(name), min: h.min}
+}
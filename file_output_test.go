@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithFileOutput(t *testing.T) {
+	dir := t.TempDir()
+	appLog := filepath.Join(dir, "app.log")
+	errorLog := filepath.Join(dir, "error.log")
+	warnLog := filepath.Join(dir, "warn.json")
+
+	log, err := New(
+		WithConsole(false),
+		WithLevel(slog.LevelInfo),
+		WithFilePath(appLog),
+		WithFileOutput(FileOutput{Path: errorLog, Format: FormatCustom, MinLevel: slog.LevelError}),
+		WithFileOutput(FileOutput{Path: warnLog, Format: FormatJSON, MinLevel: slog.LevelWarn}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Debug("debug record")
+	log.Info("info record")
+	log.Warn("warn record")
+	log.Error("error record", "code", 500)
+	// A forced level lowers the global threshold but not an output's minimum
+	log.DebugContext(ContextWithForceLevel(context.Background(), slog.LevelDebug), "forced record")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	app := readFileString(t, appLog)
+	for _, msg := range []string{"info record", "warn record", "error record", "forced record"} {
+		if !strings.Contains(app, msg) {
+			t.Errorf("Expected app.log to contain %q, got %q", msg, app)
+		}
+	}
+	if strings.Contains(app, "debug record") {
+		t.Errorf("Expected app.log to honor the global level, got %q", app)
+	}
+
+	errs := readFileString(t, errorLog)
+	if !strings.Contains(errs, "ERROR error record code=500") {
+		t.Errorf("Expected error.log to contain the error record, got %q", errs)
+	}
+	if n := strings.Count(errs, "\n"); n != 1 {
+		t.Errorf("Expected only the error record in error.log, got %d lines: %q", n, errs)
+	}
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(readFileString(t, warnLog)), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Expected JSON lines in warn.json, got %q: %v", line, err)
+		}
+		messages = append(messages, entry["msg"].(string))
+	}
+	if strings.Join(messages, ",") != "warn record,error record" {
+		t.Errorf("Expected warn and error records in warn.json, got %v", messages)
+	}
+}
+
+func TestWithFileOutput_Validation(t *testing.T) {
+	cfg := DefaultConfig()
+	WithFileOutput(FileOutput{})(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected error for a file output without a path")
+	}
+
+	cfg = DefaultConfig()
+	WithFileOutput(FileOutput{Path: filepath.Join(t.TempDir(), "x.log"), Format: "xml"})(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected error for an unsupported file output format")
+	}
+
+	// Defaults are applied and a file output alone is a destination
+	cfg = DefaultConfig()
+	WithConsole(false)(cfg)
+	WithFileOutput(FileOutput{Path: filepath.Join(t.TempDir(), "logs", "x.log")})(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("Expected a file-output-only config to be valid, got %v", err)
+	}
+	out := cfg.FileOutputs[0]
+	if out.Format != DefaultFormat || out.RetentionDays != DefaultRetentionDays {
+		t.Errorf("Expected defaults to be applied, got %+v", out)
+	}
+	// MaxSizeMB follows FileConfig: 0 keeps rotation disabled
+	if out.MaxSizeMB != 0 {
+		t.Errorf("Expected MaxSizeMB 0 to stay 0, got %d", out.MaxSizeMB)
+	}
+}
+
+func TestFileOutput_DuplicatePaths(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "app.log")
+
+	cfg := DefaultConfig()
+	WithFilePath(main)(cfg)
+	WithFileOutput(FileOutput{Path: dir + "/./app.log"})(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected an error for a file output writing to the main file")
+	}
+
+	cfg = DefaultConfig()
+	WithFilePath(main)(cfg)
+	WithFileOutput(FileOutput{Path: filepath.Join(dir, "error.log")})(cfg)
+	WithFileOutput(FileOutput{Path: filepath.Join(dir, "sub", "..", "error.log")})(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected an error for two file outputs writing to the same file")
+	}
+
+	// The main file's path is free when file logging is off
+	cfg = DefaultConfig()
+	WithFilePath(main)(cfg)
+	WithFile(false)(cfg)
+	WithFileOutput(FileOutput{Path: main})(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("Expected no conflict without the main file, got %v", err)
+	}
+}
+
+func TestFileOutput_DisableRotation(t *testing.T) {
+	dir := t.TempDir()
+	log, err := New(
+		WithConsole(false),
+		WithFileOutput(FileOutput{Path: filepath.Join(dir, "default.log"), MaxSizeMB: -1}),
+		WithFileOutput(FileOutput{Path: filepath.Join(dir, "append.log")}),
+		WithFileOutput(FileOutput{Path: filepath.Join(dir, "sized.log"), MaxSizeMB: 50}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	closers := log.closer.(*multiCloser).closers
+	if len(closers) != 3 {
+		t.Fatalf("Expected 3 file writers, got %d", len(closers))
+	}
+	for i, want := range []int{DefaultMaxSizeMB, 0, 50} {
+		if got := closers[i].(*rotatingWriter).config.maxSizeMB; got != want {
+			t.Errorf("Output %d: expected a rotation size of %d MB, got %d", i, want, got)
+		}
+	}
+}
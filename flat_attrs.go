@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// flatAttrs flattens attributes into pairs with dotted keys, e.g. req.user.id=7, for the
+// handlers writing one line of pairs per record (syslog, the syslog destination and logfmt).
+// It holds the groups and the pairs pre-encoded by WithAttrs; the handler's encode function
+// writes each pair, including its leading separator.
+type flatAttrs struct {
+	replace func(groups []string, a slog.Attr) slog.Attr // The handler's ReplaceAttr, may be nil
+	encode  func(buf *bytes.Buffer, key string, v slog.Value)
+	groups  []string
+	preset  []byte // Pre-encoded pairs from WithAttrs
+}
+
+// newFlatAttrs creates a flattener applying replace to every attribute and writing pairs with encode
+func newFlatAttrs(replace func(groups []string, a slog.Attr) slog.Attr, encode func(buf *bytes.Buffer, key string, v slog.Value)) flatAttrs {
+	return flatAttrs{replace: replace, encode: encode}
+}
+
+// appendAttr encodes an attribute nested in groups, flattening groups with dots.
+// ReplaceAttr applies to every non-group attribute, and empty attributes are left out.
+func (f *flatAttrs) appendAttr(buf *bytes.Buffer, groups []string, a slog.Attr) {
+	if f.replace != nil && a.Value.Kind() != slog.KindGroup {
+		a = f.replace(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		subGroups := groups
+		if a.Key != "" {
+			subGroups = append(slices.Clone(groups), a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			f.appendAttr(buf, subGroups, ga)
+		}
+		return
+	}
+	f.encode(buf, strings.Join(append(slices.Clone(groups), a.Key), "."), a.Value)
+}
+
+// appendRecord encodes the pre-encoded pairs and the record's own attributes in the open
+// groups. Explicit source attributes are left out, as handlers write them as their source.
+func (f *flatAttrs) appendRecord(buf *bytes.Buffer, r slog.Record) {
+	buf.Write(f.preset)
+	r.Attrs(func(a slog.Attr) bool {
+		if !isSourceAttr(a) {
+			f.appendAttr(buf, f.groups, a)
+		}
+		return true
+	})
+}
+
+// withAttrs returns a copy with attrs pre-encoded in the open groups
+func (f flatAttrs) withAttrs(attrs []slog.Attr) flatAttrs {
+	buf := bytes.NewBuffer(slices.Clone(f.preset))
+	for _, a := range attrs {
+		f.appendAttr(buf, f.groups, a)
+	}
+	f.groups = slices.Clone(f.groups)
+	f.preset = buf.Bytes()
+	return f
+}
+
+// withGroup returns a copy with the group name opened
+func (f flatAttrs) withGroup(name string) flatAttrs {
+	f.groups = append(slices.Clone(f.groups), name)
+	return f
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestFlatAttrs(t *testing.T) {
+	encode := func(buf *bytes.Buffer, key string, v slog.Value) {
+		buf.WriteString(" " + key + "=" + v.String())
+	}
+	replace := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "secret" {
+			return slog.Attr{}
+		}
+		return a
+	}
+
+	f := newFlatAttrs(replace, encode).withAttrs([]slog.Attr{slog.String("app", "api")})
+	g := f.withGroup("req").withAttrs([]slog.Attr{
+		slog.Group("user", slog.Int("id", 7), slog.String("secret", "x")),
+		slog.Group("", slog.String("inline", "y")),
+	})
+
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "msg", 0)
+	r.AddAttrs(slog.String("path", "/"), slog.Group("empty"), slog.Any(slog.SourceKey, &slog.Source{File: "main.go", Line: 1}))
+
+	var buf bytes.Buffer
+	g.appendRecord(&buf, r)
+	if got, want := buf.String(), " app=api req.user.id=7 req.inline=y req.path=/"; got != want {
+		t.Errorf("appendRecord = %q, want %q", got, want)
+	}
+
+	// Deriving g must not change f
+	buf.Reset()
+	f.appendRecord(&buf, r)
+	if got, want := buf.String(), " app=api path=/"; got != want {
+		t.Errorf("parent appendRecord = %q, want %q", got, want)
+	}
+}
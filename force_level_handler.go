@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// forceLevelKey is the context key for a per-request level override
+type forceLevelKey struct{}
+
+// ContextWithForceLevel returns a context that lowers the effective level threshold to level
+// for records logged with it (e.g. via InfoContext/DebugContext). This enables verbose logging
+// for a single request or tenant without lowering the global level. A forced level never raises
+// the threshold: records enabled by the configured level are always emitted.
+func ContextWithForceLevel(ctx context.Context, level slog.Level) context.Context {
+	return context.WithValue(ctx, forceLevelKey{}, level)
+}
+
+// forceLevelFromContext returns the forced level carried by ctx, if any
+func forceLevelFromContext(ctx context.Context) (slog.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(forceLevelKey{}).(slog.Level)
+	return level, ok
+}
+
+// forceLevelHandler is a slog.Handler that honors a level forced through the context
+type forceLevelHandler struct {
+	handler slog.Handler
+}
+
+// newForceLevelHandler wraps a destination handler so it accepts records allowed by ContextWithForceLevel
+func newForceLevelHandler(handler slog.Handler) slog.Handler {
+	return &forceLevelHandler{handler: handler}
+}
+
+// Enabled implements slog.Handler
+func (h *forceLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.handler.Enabled(ctx, level) {
+		return true
+	}
+	forced, ok := forceLevelFromContext(ctx)
+	return ok && level >= forced
+}
+
+// Handle implements slog.Handler
+func (h *forceLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *forceLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &forceLevelHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h *forceLevelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &forceLevelHandler{handler: h.handler.WithGroup(name)}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForceLevelHandler(t *testing.T) {
+	t.Run("Forced level lowers threshold", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newForceLevelHandler(slog.NewTextHandler(&buf, nil)))
+
+		logger.DebugContext(context.Background(), "suppressed")
+		if strings.Contains(buf.String(), "suppressed") {
+			t.Errorf("Expected debug record to be suppressed without forced level, got %q", buf.String())
+		}
+
+		ctx := ContextWithForceLevel(context.Background(), slog.LevelDebug)
+		logger.DebugContext(ctx, "forced")
+		if !strings.Contains(buf.String(), "forced") {
+			t.Errorf("Expected debug record to pass with forced level, got %q", buf.String())
+		}
+	})
+
+	t.Run("Forced level never raises threshold", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(newForceLevelHandler(slog.NewTextHandler(&buf, nil)))
+
+		ctx := ContextWithForceLevel(context.Background(), slog.LevelError)
+		logger.InfoContext(ctx, "still emitted")
+		if !strings.Contains(buf.String(), "still emitted") {
+			t.Errorf("Expected info record to be emitted, got %q", buf.String())
+		}
+	})
+
+	t.Run("Works through multiHandler", func(t *testing.T) {
+		var buf1, buf2 bytes.Buffer
+		logger := slog.New(newMultiHandler(
+			newForceLevelHandler(slog.NewTextHandler(&buf1, nil)),
+			newForceLevelHandler(slog.NewJSONHandler(&buf2, nil)),
+		))
+
+		ctx := ContextWithForceLevel(context.Background(), slog.LevelDebug)
+		logger.With("tenant", "acme").DebugContext(ctx, "forced")
+
+		for _, out := range []string{buf1.String(), buf2.String()} {
+			if !strings.Contains(out, "forced") || !strings.Contains(out, "acme") {
+				t.Errorf("Expected forced record in every destination, got %q", out)
+			}
+		}
+	})
+
+	t.Run("Logger integration", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "force.log")
+		logger, err := New(WithConsole(false), WithFilePath(logPath))
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+
+		logger.Debug("plain debug")
+		logger.DebugContext(ContextWithForceLevel(context.Background(), slog.LevelDebug), "forced debug")
+		logger.Close()
+
+		content, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("Failed to read log file: %v", err)
+		}
+		if strings.Contains(string(content), "plain debug") {
+			t.Errorf("Expected plain debug record to be suppressed, got %q", content)
+		}
+		if !strings.Contains(string(content), "forced debug") {
+			t.Errorf("Expected forced debug record to be written, got %q", content)
+		}
+	})
+}
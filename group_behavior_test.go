@@ -262,3 +262,62 @@ func TestGroupBackwardCompatibility(t *testing.T) {
 		t.Errorf("Output doesn't contain grouped attribute: %q", output)
 	}
 }
+
+func TestGroupPrefixOnce(t *testing.T) {
+	var buf bytes.Buffer
+
+	cfg := DefaultConfig()
+	cfg.GroupPrefixOnce = true
+	outputCfg := &mockOutputConfig{
+		format:    FormatCustom,
+		color:     false,
+		formatter: "{message} {attrs}",
+	}
+
+	handler, err := newCustomHandler(&buf, cfg, outputCfg, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler).WithGroup("Database").WithGroup("MySQL")
+
+	logger.Info("connect", "host", "localhost", "port", 3306)
+	if got, want := buf.String(), "connect Database.MySQL: host=localhost port=3306\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// No attributes, no dangling group path
+	buf.Reset()
+	logger.Info("ping")
+	if got := buf.String(); strings.Contains(got, "Database") {
+		t.Errorf("Expected no group path without attributes, got %q", got)
+	}
+
+	// Default keeps the per-key prefix
+	buf.Reset()
+	cfg.GroupPrefixOnce = false
+	logger.Info("connect", "host", "localhost", "port", 3306)
+	if got, want := buf.String(), "connect Database.MySQL.host=localhost Database.MySQL.port=3306\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestUngroupedAttrs(t *testing.T) {
+	var buf bytes.Buffer
+
+	cfg := DefaultConfig()
+	WithUngroupedAttrs("service")(cfg)
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		color:     false,
+		formatter: "{message} {attrs}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	logger := slog.New(handler).With("service", "billing").WithGroup("request")
+
+	logger.Info("handled", "id", 42)
+	if got, want := buf.String(), "handled request.id=42 service=billing\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
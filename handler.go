@@ -1,17 +1,24 @@
 package logger
 
 import (
+	"cmp"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // handlerResult holds a handler and its associated closer
 type handlerResult struct {
-	handler slog.Handler
-	closer  io.Closer
+	handler  slog.Handler
+	closer   io.Closer
+	filePath string         // Expanded log file path, empty when file logging is disabled
+	levelVar *slog.LevelVar // Live level threshold shared by all destinations
+
+	fileWriter *rotatingWriter // Writer of the (non-routed) log file, nil without file logging
+	profile    *handleProfile  // Self-profiling measurements, nil when disabled
 }
 
 // newHandler creates a handler with resource management
@@ -24,9 +31,16 @@ func newHandler(opts ...Option) (*handlerResult, error) {
 	if err := validateConfig(cfg); err != nil {
 		return nil, err
 	}
+	cfg.startTime = time.Now()
+	// Every logger gets a live level, so it can be changed later (e.g. via SetNamedLevel)
+	if cfg.LevelVar == nil {
+		cfg.LevelVar = new(slog.LevelVar)
+		cfg.LevelVar.Set(cfg.Level)
+	}
 
 	var handlers []slog.Handler
 	var closers []io.Closer
+	var fileWriter *rotatingWriter
 
 	// Console handler
 	if cfg.Console.Enabled {
@@ -34,95 +48,420 @@ func newHandler(opts ...Option) (*handlerResult, error) {
 		if err != nil {
 			return nil, fmt.Errorf("console handler error: %w", err)
 		}
-		handlers = append(handlers, handler)
+		handlers = append(handlers, newForceLevelHandler(handler))
 	}
 
 	// File handler
 	if cfg.File.Enabled && cfg.File.Path != "" {
-		handler, closer, err := newFileHandler(cfg)
+		newFile := newFileHandler
+		if cfg.File.RoutingKey != "" {
+			newFile = newRoutingHandler
+		}
+		handler, closer, err := newFile(cfg)
 		if err != nil {
 			return nil, fmt.Errorf("file handler error: %w", err)
 		}
-		handlers = append(handlers, handler)
+		fileWriter, _ = closer.(*rotatingWriter)
+		handlers = append(handlers, newForceLevelHandler(handler))
 		if closer != nil {
 			closers = append(closers, closer)
 		}
 	}
 
+	// Journald handler
+	if cfg.Journald.Enabled {
+		handler, closer, err := newJournaldHandler(cfg)
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("journald handler error: %w", err)
+		}
+		handlers = append(handlers, newForceLevelHandler(handler))
+		closers = append(closers, closer)
+	}
+
+	// Syslog handler
+	if cfg.Syslog.Enabled {
+		handler, closer, err := newSyslogDestination(cfg)
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("syslog handler error: %w", err)
+		}
+		handlers = append(handlers, newForceLevelHandler(handler))
+		closers = append(closers, closer)
+	}
+
+	// Additional file handlers
+	for _, out := range cfg.FileOutputs {
+		handler, closer, err := newFileOutputHandler(cfg, out)
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("file output %s error: %w", out.Path, err)
+		}
+		handlers = append(handlers, handler)
+		closers = append(closers, closer)
+	}
+
+	// Writer handlers
+	for _, wc := range cfg.Writers {
+		handler, err := newWriterHandler(cfg, wc)
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("writer handler error: %w", err)
+		}
+		handlers = append(handlers, newForceLevelHandler(handler))
+		if closer, ok := wc.Writer.(io.Closer); ok {
+			closers = append(closers, closer)
+		}
+	}
+
+	// Network handler
+	if cfg.Network.Enabled {
+		handler, closer, err := newNetworkHandler(cfg)
+		if err != nil {
+			for _, c := range closers {
+				c.Close()
+			}
+			return nil, fmt.Errorf("network handler error: %w", err)
+		}
+		handlers = append(handlers, newForceLevelHandler(handler))
+		closers = append(closers, closer)
+	}
+
 	// Default to console if no handlers
 	if len(handlers) == 0 {
 		return &handlerResult{
 			handler: slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-				Level:     cfg.Level,
+				Level:     cfg.leveler(),
 				AddSource: cfg.AddSource,
 			}),
+			levelVar: cfg.LevelVar,
 		}, nil
 	}
 
+	// Single handler or multiple handlers
+	var handler slog.Handler
+	if len(handlers) == 1 {
+		handler = handlers[0]
+	} else {
+		handler = newMultiHandler(handlers...)
+	}
+	if cfg.SuppressionReportInterval > 0 {
+		cfg.suppressed = &suppressionStats{}
+	}
+	if cfg.SelfProfiling {
+		cfg.profile = &handleProfile{}
+	}
+	if cfg.Sampling.Thereafter > 0 {
+		cfg.sampler = newRepeatSampler(cfg.Sampling, cfg.suppressed)
+	}
+	if cfg.DedupWindow > 0 {
+		cfg.dedup = newDedupState(cfg.DedupWindow, cfg.suppressed)
+	}
+	handler = wrapHandler(handler, cfg)
+
+	// Heartbeat is stopped first on Close, before the destinations it writes to
+	if cfg.HeartbeatInterval > 0 {
+		closers = append([]io.Closer{startHeartbeat(handler, cfg.HeartbeatInterval, cfg.HeartbeatMessage)}, closers...)
+	}
+	// The sampler emits its last summaries on Close, while the destinations are still open
+	if cfg.sampler != nil {
+		closers = append([]io.Closer{cfg.sampler}, closers...)
+	}
+	if cfg.dedup != nil {
+		closers = append([]io.Closer{cfg.dedup}, closers...)
+	}
+	if cfg.suppressed != nil {
+		closers = append([]io.Closer{startSuppressionReport(cfg.suppressed, cfg.SuppressionReportInterval)}, closers...)
+	}
+
 	var combinedCloser io.Closer
 	if len(closers) > 0 {
 		combinedCloser = &multiCloser{closers: closers}
 	}
 
-	// Single handler
-	if len(handlers) == 1 {
-		return &handlerResult{
-			handler: handlers[0],
-			closer:  combinedCloser,
-		}, nil
+	result := &handlerResult{
+		handler:    handler,
+		closer:     combinedCloser,
+		levelVar:   cfg.LevelVar,
+		fileWriter: fileWriter,
+		profile:    cfg.profile,
 	}
+	if cfg.File.Enabled {
+		result.filePath = cfg.File.Path
+	}
+	return result, nil
+}
 
-	// Multiple handlers
-	return &handlerResult{
-		handler: newMultiHandler(handlers...),
-		closer:  combinedCloser,
-	}, nil
+// wrapHandler applies the record-level decorators shared by all destinations.
+// Decorators applied later run first, so records dropped by sampling never reach the inner ones.
+func wrapHandler(handler slog.Handler, cfg *Config) slog.Handler {
+	if cfg.StackDedupWindow > 0 {
+		handler = newStackDedupHandler(handler, cfg.StackDedupWindow)
+	}
+	// Outside stack dedup, so the captured stacks are abbreviated when they repeat
+	if cfg.StackTraceLevel != nil {
+		handler = newStackTraceHandler(handler, cfg.StackTraceLevel)
+	}
+	if cfg.ElapsedAttr {
+		handler = newElapsedHandler(handler, cfg.startTime)
+	}
+	if cfg.PackageAttr && cfg.AddSource {
+		handler = newPackageHandler(handler)
+	}
+	if cfg.EntryID {
+		handler = newEntryIDHandler(handler, cfg.IDGenerator)
+	}
+	if cfg.ContextExtractor != nil {
+		handler = newContextHandler(handler, cfg.ContextExtractor)
+	}
+	// Outside the decorators adding per-record attributes such as log_id, which never repeat
+	if cfg.dedup != nil {
+		handler = newDedupHandler(handler, cfg.dedup)
+	}
+	// Outside the decorators reading the source or the stack, so they see the moved PC.
+	// Walking the stack is wasted when neither is recorded.
+	if cfg.CallerSkip > 0 && (cfg.AddSource || cfg.StackTraceLevel != nil) {
+		handler = newCallerSkipHandler(handler, cfg.CallerSkip)
+	}
+	// Outside the other decorators so they are measured too, but records dropped by sampling aren't
+	if cfg.profile != nil {
+		handler = newProfilingHandler(handler, cfg.profile)
+	}
+	if cfg.sampler != nil {
+		handler = cfg.sampler.wrap(handler)
+	}
+	if len(cfg.LevelSampling) > 0 {
+		handler = newSamplingHandler(handler, cfg.LevelSampling, cfg.suppressed)
+	}
+	return handler
 }
 
-func newConsoleHandler(cfg *Config) (slog.Handler, error) {
-	opts := &slog.HandlerOptions{
-		Level:       cfg.Level,
+// newHandlerOptions builds the slog.HandlerOptions shared by all destinations
+func newHandlerOptions(cfg *Config) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level:       cfg.leveler(),
 		AddSource:   cfg.AddSource,
-		ReplaceAttr: cfg.ReplaceAttr,
+		ReplaceAttr: buildReplaceAttr(cfg),
+	}
+}
+
+// badKey is the key slog gives a value passed without one, e.g. an odd number of args to Info
+const badKey = "!BADKEY"
+
+// buildReplaceAttr composes the user's ReplaceAttr with the built-in key renames.
+// The user's function runs first and always sees the standard slog keys.
+func buildReplaceAttr(cfg *Config) func(groups []string, a slog.Attr) slog.Attr {
+	messageKey := cfg.MessageKey
+	if messageKey == slog.MessageKey {
+		messageKey = ""
+	}
+	badKeyName, dropBadKeys := cfg.BadKeyName, cfg.DropBadKeys
+	if messageKey == "" && badKeyName == "" && !dropBadKeys {
+		return cfg.ReplaceAttr
+	}
+	user := cfg.ReplaceAttr
+	return func(groups []string, a slog.Attr) slog.Attr {
+		isMessage := len(groups) == 0 && a.Key == slog.MessageKey
+		if user != nil {
+			a = user(groups, a)
+		}
+		if isMessage && messageKey != "" && a.Key == slog.MessageKey {
+			a.Key = messageKey
+		}
+		if a.Key == badKey {
+			if dropBadKeys {
+				return slog.Attr{}
+			}
+			if badKeyName != "" {
+				a.Key = badKeyName
+			}
+		}
+		return a
+	}
+}
+
+// reportingWriter passes the write errors of w to the ErrorHandler, for the handlers that
+// don't report failures themselves (slog's JSON and text handlers, syslog and logfmt)
+type reportingWriter struct {
+	w      io.Writer
+	report func(err error)
+}
+
+// Write implements io.Writer
+func (w reportingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.report(err)
+	}
+	return n, err
+}
+
+// reportWrites returns w wrapped in a reportingWriter when an ErrorHandler is set, w itself otherwise
+func reportWrites(w io.Writer, cfg *Config) io.Writer {
+	if cfg.ErrorHandler == nil {
+		return w
+	}
+	return reportingWriter{w: w, report: cfg.reportError}
+}
+
+func newConsoleHandler(cfg *Config) (slog.Handler, error) {
+	return newConsoleHandlerTo(os.Stderr, cfg)
+}
+
+// newConsoleHandlerTo creates the console handler writing to w
+func newConsoleHandlerTo(w io.Writer, cfg *Config) (slog.Handler, error) {
+	if cfg.Encoder != nil {
+		return newEncoderHandler(w, cfg), nil
+	}
+	color := cfg.Console.Color && (!cfg.Console.ColorAuto || isTerminalWriter(w))
+	out := consoleOutput{ConsoleConfig: &cfg.Console, color: color}
+
+	opts := newHandlerOptions(cfg)
+	// The custom handler reports its own failures, with the record
+	reported := reportWrites(w, cfg)
+	jsonOut := indentJSON(reported, cfg.Console.JSONIndent)
+
+	if cfg.Console.DualOutput {
+		human, err := newCustomHandler(w, cfg, out, opts)
+		if err != nil {
+			return nil, err
+		}
+		return newDualHandler(human, slog.NewJSONHandler(jsonOut, opts)), nil
 	}
 
 	switch cfg.Console.Format {
 	case FormatJSON:
-		return slog.NewJSONHandler(os.Stderr, opts), nil
+		return slog.NewJSONHandler(jsonOut, opts), nil
 	case FormatText:
-		return slog.NewTextHandler(os.Stderr, opts), nil
+		return slog.NewTextHandler(reported, opts), nil
 	case FormatCustom:
-		return newCustomHandler(os.Stderr, cfg, &cfg.Console, opts)
+		return newCustomHandler(w, cfg, out, opts)
+	case FormatSyslog:
+		return newSyslogHandler(reported, opts), nil
+	case FormatLogfmt:
+		return newLogfmtHandler(reported, opts), nil
 	default:
 		return nil, fmt.Errorf("unsupported console format: %v", cfg.Console.Format)
 	}
 }
 
+// consoleOutput renders the console with color decided for its writer, see WithColorAuto
+type consoleOutput struct {
+	*ConsoleConfig
+	color bool
+}
+
+func (o consoleOutput) GetColor() bool {
+	return o.color
+}
+
+// writerOutput renders a WithWriter destination like the file, but in its own format
+type writerOutput struct {
+	*FileConfig
+	format OutputFormat
+}
+
+func (o writerOutput) GetFormat() OutputFormat {
+	return o.format
+}
+
+// newWriterHandler creates the handler for a WithWriter destination
+func newWriterHandler(cfg *Config, wc WriterConfig) (slog.Handler, error) {
+	if cfg.Encoder != nil {
+		return newEncoderHandler(wc.Writer, cfg), nil
+	}
+
+	opts := newHandlerOptions(cfg)
+	reported := reportWrites(wc.Writer, cfg)
+
+	switch wc.Format {
+	case FormatJSON:
+		return slog.NewJSONHandler(indentJSON(reported, cfg.File.JSONIndent), opts), nil
+	case FormatText:
+		return slog.NewTextHandler(reported, opts), nil
+	case FormatSyslog:
+		return newSyslogHandler(reported, opts), nil
+	case FormatLogfmt:
+		return newLogfmtHandler(reported, opts), nil
+	case FormatCustom:
+		return newCustomHandler(wc.Writer, cfg, writerOutput{FileConfig: &cfg.File, format: wc.Format}, opts)
+	default:
+		return nil, fmt.Errorf("unsupported writer format: %v", wc.Format)
+	}
+}
+
 func newFileHandler(cfg *Config) (slog.Handler, io.Closer, error) {
-	writer, err := newRotatingWriter(&rotatingConfig{
-		directory:     filepath.Dir(cfg.File.Path),
-		fileName:      filepath.Base(cfg.File.Path),
-		maxSizeMB:     cfg.File.MaxSizeMB,
-		retentionDays: cfg.File.RetentionDays,
-	})
+	return newFileHandlerAt(cfg, cfg.File.Path)
+}
+
+// newFileHandlerAt creates a file handler using the file settings of cfg but writing to path
+func newFileHandlerAt(cfg *Config, path string) (slog.Handler, io.Closer, error) {
+	return newFileHandlerFrom(cfg, &cfg.File, path)
+}
+
+// newFileHandlerFrom creates a file handler writing to path with the file settings fc
+func newFileHandlerFrom(cfg *Config, fc *FileConfig, path string) (slog.Handler, io.Closer, error) {
+	rotatingCfg := &rotatingConfig{
+		directory:       filepath.Dir(path),
+		fileName:        filepath.Base(path),
+		maxSizeMB:       fc.MaxSizeMB,
+		retentionDays:   fc.RetentionDays,
+		maxAge:          fc.MaxAge,
+		maxTotalSizeMB:  fc.MaxTotalSizeMB,
+		rotateInterval:  fc.RotationInterval,
+		onClosedWrite:   fc.OnClosedWrite,
+		atomicRecords:   fc.AtomicRecords,
+		unbuffered:      fc.Unbuffered,
+		bufferSize:      cmp.Or(fc.BufferSize, DefaultBufferSize),
+		rotationMarkers: fc.RotationMarkers,
+		jsonMarkers:     fc.Format == FormatJSON && cfg.Encoder == nil,
+		compressor:      fc.compressor,
+		closeTimeMeta:   fc.ReliableRetention,
+		fsync:           fc.Fsync,
+		fileMode:        fc.FileMode,
+		dirMode:         fc.DirMode,
+	}
+	if cfg.ErrorHandler != nil {
+		rotatingCfg.onError = cfg.reportError
+	}
+	if !fc.SyncWrites {
+		rotatingCfg.flushInterval = DefaultFlushInterval
+	}
+	if fc.RotationEvents {
+		rotatingCfg.eventLogger = internalLogger
+	}
+	writer, err := newRotatingWriter(rotatingCfg)
 	if err != nil {
 		return nil, nil, fmt.Errorf("rotating writer error: %w", err)
 	}
 
-	opts := &slog.HandlerOptions{
-		Level:       cfg.Level,
-		AddSource:   cfg.AddSource,
-		ReplaceAttr: cfg.ReplaceAttr,
+	if cfg.Encoder != nil {
+		return newEncoderHandler(writer, cfg), writer, nil
 	}
 
+	opts := newHandlerOptions(cfg)
+	reported := reportWrites(writer, cfg)
+
 	var handler slog.Handler
-	switch cfg.File.Format {
+	switch fc.Format {
 	case FormatJSON:
-		handler = slog.NewJSONHandler(writer, opts)
+		handler = slog.NewJSONHandler(indentJSON(reported, fc.JSONIndent), opts)
 	case FormatText:
-		handler = slog.NewTextHandler(writer, opts)
+		handler = slog.NewTextHandler(reported, opts)
+	case FormatSyslog:
+		handler = newSyslogHandler(reported, opts)
+	case FormatLogfmt:
+		handler = newLogfmtHandler(reported, opts)
 	case FormatCustom:
-		h, err := newCustomHandler(writer, cfg, &cfg.File, opts)
+		h, err := newCustomHandler(writer, cfg, fc, opts)
 		if err != nil {
 			writer.Close()
 			return nil, nil, err
@@ -130,19 +469,40 @@ func newFileHandler(cfg *Config) (slog.Handler, io.Closer, error) {
 		handler = h
 	default:
 		writer.Close()
-		return nil, nil, fmt.Errorf("unsupported file format: %v", cfg.File.Format)
+		return nil, nil, fmt.Errorf("unsupported file format: %v", fc.Format)
 	}
 
 	return handler, writer, nil
 }
 
-// multiCloser closes multiple closers
+// Flusher is implemented by closers that can write out buffered data without releasing
+// their resources. multiCloser flushes every Flusher before closing anything.
+type Flusher interface {
+	Flush() error
+}
+
+// multiCloser closes multiple closers in two phases: first every closer that is a
+// Flusher is flushed, then all are closed in registration order. So no destination
+// is closed while another still holds unflushed records.
 type multiCloser struct {
 	closers []io.Closer
 }
 
-func (mc *multiCloser) Close() error {
+// Flush flushes every closer that implements Flusher
+func (mc *multiCloser) Flush() error {
 	var firstErr error
+	for _, closer := range mc.closers {
+		if f, ok := closer.(Flusher); ok {
+			if err := f.Flush(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (mc *multiCloser) Close() error {
+	firstErr := mc.Flush()
 	for _, closer := range mc.closers {
 		if err := closer.Close(); err != nil && firstErr == nil {
 			firstErr = err
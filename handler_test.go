@@ -1,9 +1,14 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -223,3 +228,307 @@ func TestHandlerWithCustomWriter(t *testing.T) {
 		t.Fatal("Expected output, got none")
 	}
 }
+
+// closingBuffer is a bytes.Buffer that records being closed
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestWithWriter(t *testing.T) {
+	var custom bytes.Buffer
+	jsonOut := &closingBuffer{}
+
+	log, err := New(
+		WithConsole(true),
+		WithWriter(&custom, FormatCustom),
+		WithWriter(jsonOut, FormatJSON),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("to every writer", "key", "value")
+	log.Debug("filtered")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if got := custom.String(); !strings.Contains(got, "INFO to every writer key=value") || strings.Contains(got, "filtered") {
+		t.Errorf("Unexpected custom writer output: %q", got)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(jsonOut.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected a single JSON record, got %q: %v", jsonOut.String(), err)
+	}
+	if entry["msg"] != "to every writer" || entry["key"] != "value" {
+		t.Errorf("Unexpected JSON record: %v", entry)
+	}
+	if !jsonOut.closed {
+		t.Error("Expected a writer implementing io.Closer to be closed by Close")
+	}
+}
+
+func TestWithWriter_Validation(t *testing.T) {
+	cfg := DefaultConfig()
+	WithWriter(nil, FormatJSON)(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected error for nil writer")
+	}
+
+	cfg = DefaultConfig()
+	WithWriter(&bytes.Buffer{}, "xml")(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected error for unsupported writer format")
+	}
+
+	// A writer alone is enough of a destination
+	cfg = DefaultConfig()
+	WithConsole(false)(cfg)
+	WithWriter(&bytes.Buffer{}, "")(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("Expected writer-only config to be valid, got %v", err)
+	}
+	if cfg.Writers[0].Format != DefaultFormat {
+		t.Errorf("Expected empty writer format to default to %s, got %s", DefaultFormat, cfg.Writers[0].Format)
+	}
+}
+
+// TestWithErrorHandler_AllFormats tests that write failures reach the error handler once,
+// whichever handler renders the destination
+func TestWithErrorHandler_AllFormats(t *testing.T) {
+	for _, format := range []OutputFormat{FormatJSON, FormatText, FormatCustom, FormatSyslog, FormatLogfmt} {
+		t.Run(string(format), func(t *testing.T) {
+			var errs []error
+			log, err := New(
+				WithConsole(false),
+				WithWriter(failingWriter{}, format),
+				WithErrorHandler(func(err error) { errs = append(errs, err) }),
+			)
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			defer log.Close()
+
+			log.Info("lost")
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), "write failed") {
+				t.Errorf("Expected one write error, got %v", errs)
+			}
+		})
+	}
+
+	// Errors of the rotating file writer itself, here writing after Close, are reported too
+	var errs []error
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(filepath.Join(t.TempDir(), "app.log")),
+		WithFileFormat(FormatText),
+		WithErrorHandler(func(err error) { errs = append(errs, err) }),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Close()
+	log.Info("after close")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "closed") {
+		t.Errorf("Expected the closed writer's error, got %v", errs)
+	}
+}
+
+func TestWithMessageKey(t *testing.T) {
+	t.Run("Custom format renders keyed message", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithMessageKey("event")(cfg)
+		WithConsoleColor(false)(cfg)
+		WithConsoleFormatter("{level} {message} {attrs}")(cfg)
+
+		handler, err := newCustomHandler(&buf, cfg, &cfg.Console, newHandlerOptions(cfg))
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		slog.New(handler).Info("user login", "user", "john")
+
+		if got := buf.String(); got != "INFO event=user login user=john\n" {
+			t.Errorf("Unexpected output: %q", got)
+		}
+	})
+
+	t.Run("JSON format renames message key", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithMessageKey("event")(cfg)
+
+		slog.New(slog.NewJSONHandler(&buf, newHandlerOptions(cfg))).Info("user login")
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if entry["event"] != "user login" {
+			t.Errorf("Expected message under event key, got %v", entry)
+		}
+		if _, ok := entry["msg"]; ok {
+			t.Errorf("Expected msg key to be renamed, got %v", entry)
+		}
+	})
+
+	t.Run("User ReplaceAttr sees standard key", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithMessageKey("event")(cfg)
+		WithReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.MessageKey {
+				return slog.String(a.Key, strings.ToUpper(a.Value.String()))
+			}
+			return a
+		})(cfg)
+
+		slog.New(slog.NewTextHandler(&buf, newHandlerOptions(cfg))).Info("user login")
+
+		if output := buf.String(); !strings.Contains(output, `event="USER LOGIN"`) {
+			t.Errorf("Expected replaced message under event key, got %q", output)
+		}
+	})
+}
+
+func TestBadKeyHandling(t *testing.T) {
+	render := func(opts ...Option) string {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithConsoleColor(false)(cfg)
+		WithConsoleFormatter("{message} {attrs}")(cfg)
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		handler, err := newCustomHandler(&buf, cfg, &cfg.Console, newHandlerOptions(cfg))
+		if err != nil {
+			t.Fatalf("Failed to create handler: %v", err)
+		}
+		oddArgs := []any{"user", "john", "dangling"} // via a slice so vet doesn't flag the call
+		slog.New(handler).Info("odd args", oddArgs...)
+		return buf.String()
+	}
+
+	if got := render(); got != "odd args user=john !BADKEY=dangling\n" {
+		t.Errorf("Expected !BADKEY by default, got %q", got)
+	}
+	if got := render(WithBadKeyName("extra")); got != "odd args user=john extra=dangling\n" {
+		t.Errorf("Expected renamed bad key, got %q", got)
+	}
+	if got := render(WithBadKeyName("extra"), WithDropBadKeys(true)); got != "odd args user=john\n" {
+		t.Errorf("Expected bad key dropped, got %q", got)
+	}
+
+	t.Run("JSON output", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg := DefaultConfig()
+		WithBadKeyName("extra")(cfg)
+		slog.New(slog.NewJSONHandler(&buf, newHandlerOptions(cfg))).Info("odd args", []any{42}...)
+
+		var entry map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if entry["extra"] != float64(42) {
+			t.Errorf("Expected extra=42, got %v", entry)
+		}
+		if _, ok := entry[badKey]; ok {
+			t.Errorf("Expected %s to be renamed, got %v", badKey, entry)
+		}
+	})
+}
+
+// recordingCloser records its Flush and Close calls in a shared log
+type recordingCloser struct {
+	name  string
+	calls *[]string
+}
+
+func (c *recordingCloser) Flush() error {
+	*c.calls = append(*c.calls, "flush "+c.name)
+	return nil
+}
+
+func (c *recordingCloser) Close() error {
+	*c.calls = append(*c.calls, "close "+c.name)
+	return nil
+}
+
+// closeOnly is a closer without a Flush method
+type closeOnly struct {
+	name  string
+	calls *[]string
+}
+
+func (c *closeOnly) Close() error {
+	*c.calls = append(*c.calls, "close "+c.name)
+	return nil
+}
+
+func TestMultiCloser_FlushesBeforeClosing(t *testing.T) {
+	var calls []string
+	mc := &multiCloser{closers: []io.Closer{
+		&recordingCloser{name: "network", calls: &calls},
+		&closeOnly{name: "heartbeat", calls: &calls},
+		&recordingCloser{name: "file", calls: &calls},
+	}}
+
+	if err := mc.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	want := []string{"flush network", "flush file", "close network", "close heartbeat", "close file"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+func TestWithColorAuto(t *testing.T) {
+	render := func(opts ...Option) string {
+		t.Helper()
+		cfg := DefaultConfig()
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		var buf bytes.Buffer
+		handler, err := newConsoleHandlerTo(&buf, cfg)
+		if err != nil {
+			t.Fatalf("newConsoleHandlerTo() failed: %v", err)
+		}
+		slog.New(handler).Warn("piped", "k", "v")
+		return buf.String()
+	}
+
+	if out := render(WithColorAuto()); strings.Contains(out, "\033[") || !strings.Contains(out, "piped") {
+		t.Errorf("Expected no ANSI codes when the console isn't a terminal, got %q", out)
+	}
+	// An explicit WithConsoleColor is a hard override
+	if out := render(WithColorAuto(), WithConsoleColor(true)); !strings.Contains(out, "\033[") {
+		t.Errorf("Expected WithConsoleColor(true) to force colors, got %q", out)
+	}
+
+	// The decision is per writer and leaves the config alone
+	cfg := DefaultConfig()
+	WithColorAuto()(cfg)
+	if _, err := newConsoleHandlerTo(io.Discard, cfg); err != nil {
+		t.Fatalf("newConsoleHandlerTo() failed: %v", err)
+	}
+	if !cfg.Console.Color {
+		t.Error("Expected building a handler for a non-terminal not to clear Console.Color")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() failed: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if isTerminalWriter(w) {
+		t.Error("Expected a pipe not to be detected as a terminal")
+	}
+}
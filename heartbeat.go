@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatMessage is the message used for heartbeat records when none is given
+const DefaultHeartbeatMessage = "heartbeat"
+
+// heartbeat periodically emits an Info record so silent hangs can be detected
+type heartbeat struct {
+	logger   *slog.Logger
+	interval time.Duration
+	msg      string
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// startHeartbeat starts a goroutine logging msg through handler every interval
+func startHeartbeat(handler slog.Handler, interval time.Duration, msg string) *heartbeat {
+	if msg == "" {
+		msg = DefaultHeartbeatMessage
+	}
+	hb := &heartbeat{
+		logger:   slog.New(handler),
+		interval: interval,
+		msg:      msg,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go hb.run()
+	return hb
+}
+
+func (hb *heartbeat) run() {
+	defer close(hb.done)
+
+	ticker := time.NewTicker(hb.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			hb.emit()
+		case <-hb.stop:
+			return
+		}
+	}
+}
+
+// emit logs a single heartbeat record with basic runtime stats
+func (hb *heartbeat) emit() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	hb.logger.Info(hb.msg,
+		slog.Int("goroutines", runtime.NumGoroutine()),
+		slog.Uint64("heap_alloc", mem.HeapAlloc),
+		slog.Uint64("heap_sys", mem.HeapSys),
+	)
+}
+
+// Close stops the heartbeat goroutine and waits for it to exit
+func (hb *heartbeat) Close() error {
+	hb.once.Do(func() {
+		close(hb.stop)
+	})
+	<-hb.done
+	return nil
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithHeartbeat(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "heartbeat.log")
+
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(logPath),
+		WithFileFormat(FormatText),
+		WithHeartbeat(10*time.Millisecond, "alive"),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	output := string(content)
+	if !strings.Contains(output, "msg=alive") {
+		t.Fatalf("Expected at least one heartbeat line, got %q", output)
+	}
+	for _, key := range []string{"goroutines=", "heap_alloc=", "heap_sys="} {
+		if !strings.Contains(output, key) {
+			t.Errorf("Expected heartbeat to contain %s, got %q", key, output)
+		}
+	}
+
+	// No further heartbeats after Close
+	time.Sleep(40 * time.Millisecond)
+	after, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if len(after) != len(content) {
+		t.Errorf("Expected no heartbeats after Close, file grew from %d to %d bytes", len(content), len(after))
+	}
+}
+
+func TestHeartbeat_StopsOnClose(t *testing.T) {
+	hb := startHeartbeat(&mockHandler{enabled: true}, time.Millisecond, "")
+	if hb.msg != DefaultHeartbeatMessage {
+		t.Errorf("Expected default message %q, got %q", DefaultHeartbeatMessage, hb.msg)
+	}
+
+	if err := hb.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	select {
+	case <-hb.done:
+	default:
+		t.Error("Expected heartbeat goroutine to have exited after Close")
+	}
+
+	// Close is idempotent
+	if err := hb.Close(); err != nil {
+		t.Errorf("Second Close() failed: %v", err)
+	}
+}
+
+func TestWithHeartbeat_InvalidInterval(t *testing.T) {
+	if _, err := New(WithHeartbeat(-time.Second, "alive")); err == nil {
+		t.Error("Expected error for negative heartbeat interval")
+	}
+}
@@ -0,0 +1,10 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+)
+
+// internalLogger reports the logger's own diagnostics (e.g. rotation events) to stderr.
+// It never writes to a file destination, so it cannot feed back into a rotatingWriter.
+var internalLogger = slog.New(slog.NewTextHandler(os.Stderr, nil))
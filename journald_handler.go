@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultJournaldSocket is the systemd-journald native protocol socket
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// Journald priorities (syslog severities)
+const (
+	journaldPriorityCrit    = 2
+	journaldPriorityErr     = 3
+	journaldPriorityWarning = 4
+	journaldPriorityInfo    = 6
+	journaldPriorityDebug   = 7
+)
+
+// journaldPriority maps a slog level to a journald priority
+func journaldPriority(level slog.Level) int {
+	switch {
+	case level <= slog.LevelDebug:
+		return journaldPriorityDebug
+	case level <= slog.LevelInfo:
+		return journaldPriorityInfo
+	case level <= slog.LevelWarn:
+		return journaldPriorityWarning
+	case level <= slog.LevelError:
+		return journaldPriorityErr
+	default:
+		return journaldPriorityCrit
+	}
+}
+
+// journaldFieldName converts an attribute key into a valid journald field name:
+// uppercase ASCII letters, digits and underscores, not starting with an underscore or digit
+func journaldFieldName(key string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(key) {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+	return name
+}
+
+// appendJournaldField appends a field in the journald native protocol encoding.
+// Values containing newlines use the binary length-prefixed form.
+func appendJournaldField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	buf.Write(size[:])
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journaldHandler is a slog.Handler that writes records using the journald native protocol
+type journaldHandler struct {
+	mu         *sync.Mutex
+	conn       io.Writer
+	opts       slog.HandlerOptions
+	identifier string
+	groups     []string
+	preset     []byte // Pre-encoded fields from WithAttrs
+}
+
+// newJournaldHandler dials the journald socket and returns a handler and its closer
+func newJournaldHandler(cfg *Config) (slog.Handler, io.Closer, error) {
+	socket := cfg.Journald.SocketPath
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+	conn, err := dialJournald(socket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newJournaldWriterHandler(reportWrites(conn, cfg), *newHandlerOptions(cfg)), conn, nil
+}
+
+// newJournaldWriterHandler creates a journald handler over an already connected datagram writer
+func newJournaldWriterHandler(conn io.Writer, opts slog.HandlerOptions) *journaldHandler {
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	return &journaldHandler{
+		mu:         &sync.Mutex{},
+		conn:       conn,
+		opts:       opts,
+		identifier: filepath.Base(os.Args[0]),
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *journaldHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *journaldHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	appendJournaldField(&buf, "MESSAGE", r.Message)
+	appendJournaldField(&buf, "PRIORITY", strconv.Itoa(journaldPriority(r.Level)))
+	if h.identifier != "" {
+		appendJournaldField(&buf, "SYSLOG_IDENTIFIER", h.identifier)
+	}
+	if h.opts.AddSource && r.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{r.PC})
+		f, _ := fs.Next()
+		appendJournaldField(&buf, "CODE_FILE", f.File)
+		appendJournaldField(&buf, "CODE_LINE", strconv.Itoa(f.Line))
+		appendJournaldField(&buf, "CODE_FUNC", f.Function)
+	}
+	buf.Write(h.preset)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&buf, h.groups, a)
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.conn.Write(buf.Bytes())
+	return err
+}
+
+// appendAttr encodes an attribute as a journald field, flattening groups with underscores
+func (h *journaldHandler) appendAttr(buf *bytes.Buffer, groups []string, a slog.Attr) {
+	if h.opts.ReplaceAttr != nil && a.Value.Kind() != slog.KindGroup {
+		a = h.opts.ReplaceAttr(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		subGroups := groups
+		if a.Key != "" {
+			subGroups = append(slices.Clone(groups), a.Key)
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(buf, subGroups, ga)
+		}
+		return
+	}
+
+	name := journaldFieldName(strings.Join(append(slices.Clone(groups), a.Key), "_"))
+	var value string
+	switch a.Value.Kind() {
+	case slog.KindTime:
+		value = a.Value.Time().Format(time.RFC3339Nano)
+	default:
+		value = fmt.Sprintf("%v", a.Value.Any())
+	}
+	appendJournaldField(buf, name, value)
+}
+
+// WithAttrs implements slog.Handler
+func (h *journaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	var buf bytes.Buffer
+	buf.Write(h.preset)
+	for _, a := range attrs {
+		h.appendAttr(&buf, h.groups, a)
+	}
+	newHandler := *h
+	newHandler.groups = slices.Clone(h.groups)
+	newHandler.preset = buf.Bytes()
+	return &newHandler
+}
+
+// WithGroup implements slog.Handler
+func (h *journaldHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	newHandler := *h
+	newHandler.groups = append(slices.Clone(h.groups), name)
+	return &newHandler
+}
@@ -0,0 +1,18 @@
+//go:build linux
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"net"
+)
+
+// dialJournald connects to the journald native protocol datagram socket
+func dialJournald(socket string) (io.WriteCloser, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to journald socket %s: %w", socket, err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,184 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// parseJournaldFields decodes a journald native protocol datagram
+func parseJournaldFields(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		nl := bytes.IndexByte(data, '\n')
+		if nl < 0 {
+			t.Fatalf("Unterminated field in %q", data)
+		}
+		line := data[:nl]
+		if eq := bytes.IndexByte(line, '='); eq >= 0 {
+			fields[string(line[:eq])] = string(line[eq+1:])
+			data = data[nl+1:]
+			continue
+		}
+		// Binary form: NAME\n<uint64 LE length><value>\n
+		name := string(line)
+		data = data[nl+1:]
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[name] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldHandler(t *testing.T) {
+	dir, err := os.MkdirTemp("", "jd")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "journal.sock")
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Failed to listen on mock journald socket: %v", err)
+	}
+	defer listener.Close()
+
+	cfg := DefaultConfig()
+	cfg.Level = slog.LevelDebug
+	cfg.Journald = JournaldConfig{Enabled: true, SocketPath: socket}
+	handler, closer, err := newJournaldHandler(cfg)
+	if err != nil {
+		t.Fatalf("newJournaldHandler() failed: %v", err)
+	}
+	defer closer.Close()
+
+	receive := func() map[string]string {
+		t.Helper()
+		buf := make([]byte, 64*1024)
+		listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, err := listener.Read(buf)
+		if err != nil {
+			t.Fatalf("Failed to read datagram: %v", err)
+		}
+		return parseJournaldFields(t, buf[:n])
+	}
+
+	logger := slog.New(handler)
+
+	t.Run("Field encoding", func(t *testing.T) {
+		logger.With("service", "api").WithGroup("req").Warn("slow request", "user-id", 42)
+
+		fields := receive()
+		expected := map[string]string{
+			"MESSAGE":     "slow request",
+			"PRIORITY":    "4",
+			"SERVICE":     "api",
+			"REQ_USER_ID": "42",
+		}
+		for name, want := range expected {
+			if got := fields[name]; got != want {
+				t.Errorf("Expected %s=%q, got %q (fields: %v)", name, want, got, fields)
+			}
+		}
+	})
+
+	t.Run("Multi-line values use binary encoding", func(t *testing.T) {
+		logger.Error("line one\nline two")
+
+		fields := receive()
+		if fields["MESSAGE"] != "line one\nline two" {
+			t.Errorf("Expected multi-line message, got %q", fields["MESSAGE"])
+		}
+		if fields["PRIORITY"] != "3" {
+			t.Errorf("Expected PRIORITY=3, got %q", fields["PRIORITY"])
+		}
+	})
+
+	t.Run("Priority mapping", func(t *testing.T) {
+		levels := map[slog.Level]int{
+			slog.LevelDebug:     7,
+			slog.LevelInfo:      6,
+			slog.LevelWarn:      4,
+			slog.LevelError:     3,
+			slog.LevelError + 4: 2,
+		}
+		for level, want := range levels {
+			if got := journaldPriority(level); got != want {
+				t.Errorf("journaldPriority(%v) = %d, want %d", level, got, want)
+			}
+		}
+	})
+}
+
+func TestJournaldHandler_SharedReplaceAttr(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("Failed to listen on mock journald socket: %v", err)
+	}
+	defer listener.Close()
+
+	cfg := DefaultConfig()
+	cfg.Journald = JournaldConfig{Enabled: true, SocketPath: socket}
+	WithBadKeyName("extra")(cfg)
+	WithReplaceAttr(func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "password" {
+			return slog.String(a.Key, "***")
+		}
+		return a
+	})(cfg)
+	handler, closer, err := newJournaldHandler(cfg)
+	if err != nil {
+		t.Fatalf("newJournaldHandler() failed: %v", err)
+	}
+	defer closer.Close()
+
+	var text bytes.Buffer
+	slog.New(slog.NewTextHandler(&text, newHandlerOptions(cfg))).Info("login", "password", "hunter2", slog.String(badKey, "orphan"))
+	slog.New(handler).Info("login", "password", "hunter2", slog.String(badKey, "orphan"))
+
+	buf := make([]byte, 64*1024)
+	listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("Failed to read datagram: %v", err)
+	}
+	fields := parseJournaldFields(t, buf[:n])
+
+	// Both outputs carry the attributes as transformed by the shared ReplaceAttr chain
+	for key, want := range map[string]string{"password": "***", "extra": "orphan"} {
+		if !bytes.Contains(text.Bytes(), []byte(key+"="+want)) {
+			t.Errorf("Expected %s=%s in the text output, got %q", key, want, text.String())
+		}
+		if got := fields[journaldFieldName(key)]; got != want {
+			t.Errorf("Expected journald field %s=%q like the text output, got %q (fields: %v)", journaldFieldName(key), want, got, fields)
+		}
+	}
+	if _, ok := fields[journaldFieldName(badKey)]; ok {
+		t.Errorf("Expected no %s field, got %v", badKey, fields)
+	}
+}
+
+func TestJournaldFieldName(t *testing.T) {
+	tests := map[string]string{
+		"user":      "USER",
+		"user.id":   "USER_ID",
+		"_private":  "PRIVATE",
+		"1st":       "X1ST",
+		"requestID": "REQUESTID",
+	}
+	for key, want := range tests {
+		if got := journaldFieldName(key); got != want {
+			t.Errorf("journaldFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
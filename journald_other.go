@@ -0,0 +1,14 @@
+//go:build !linux
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// dialJournald reports that journald is unavailable on this platform
+func dialJournald(socket string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("journald logging is only supported on linux (current platform: %s)", runtime.GOOS)
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// jsonIndent is the indentation of each nesting level with WithJSONIndent
+const jsonIndent = "  "
+
+// jsonIndentWriter re-indents each JSON record written to it, one record per Write as
+// slog's JSON handler issues them. Records that fail to parse are written unchanged.
+type jsonIndentWriter struct {
+	w    io.Writer
+	pool sync.Pool
+}
+
+// newJSONIndentWriter wraps w so JSON records are pretty-printed
+func newJSONIndentWriter(w io.Writer) *jsonIndentWriter {
+	return &jsonIndentWriter{
+		w:    w,
+		pool: sync.Pool{New: func() any { return new(bytes.Buffer) }},
+	}
+}
+
+// indentJSON returns w wrapped in a jsonIndentWriter when enabled, w itself otherwise
+func indentJSON(w io.Writer, enabled bool) io.Writer {
+	if !enabled {
+		return w
+	}
+	return newJSONIndentWriter(w)
+}
+
+// Write implements io.Writer
+func (w *jsonIndentWriter) Write(p []byte) (int, error) {
+	buf := w.pool.Get().(*bytes.Buffer)
+	defer w.pool.Put(buf)
+	buf.Reset()
+
+	if err := json.Indent(buf, bytes.TrimRight(p, "\n"), "", jsonIndent); err != nil {
+		return w.w.Write(p)
+	}
+	// Readers split records on the newline after the closing brace
+	buf.WriteByte('\n')
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
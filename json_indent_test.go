@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// splitJSONRecords splits pretty-printed records after each unindented closing brace
+func splitJSONRecords(t *testing.T, s string) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, chunk := range strings.SplitAfter(s, "\n}\n") {
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(chunk), &record); err != nil {
+			t.Fatalf("Failed to parse record %q: %v", chunk, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestJSONIndentWriter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(newJSONIndentWriter(&buf), nil))
+	logger.Info("first", slog.Group("req", slog.String("path", "/login")))
+	logger.Warn("second", "n", 2)
+
+	out := buf.String()
+	if !strings.Contains(out, "\n  \"msg\": \"first\"") || !strings.Contains(out, "\n    \"path\": \"/login\"") {
+		t.Errorf("Expected two-space indentation per level, got:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("Expected the output to end with a newline after the last record, got %q", out)
+	}
+	records := splitJSONRecords(t, out)
+	if len(records) != 2 || records[0]["msg"] != "first" || records[1]["n"] != float64(2) {
+		t.Errorf("Unexpected records: %v", records)
+	}
+
+	// Anything that isn't JSON passes through unchanged
+	buf.Reset()
+	w := newJSONIndentWriter(&buf)
+	if n, err := w.Write([]byte("not json\n")); err != nil || n != 9 || buf.String() != "not json\n" {
+		t.Errorf("Expected non-JSON input to be written as is, got %d %v %q", n, err, buf.String())
+	}
+}
+
+func TestWithJSONIndent_DestinationScoped(t *testing.T) {
+	dir := t.TempDir()
+	compact := filepath.Join(dir, "compact.log")
+	pretty := filepath.Join(dir, "pretty.log")
+
+	// WithJSONIndent only concerns the console, files stay one record per line
+	log, err := New(WithConsole(false), WithFilePath(compact), WithFileFormat(FormatJSON), WithJSONIndent(true))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("compact", "k", "v")
+	log.Close()
+	if content := readFileString(t, compact); strings.Count(content, "\n") != 1 {
+		t.Errorf("Expected a single line in the file, got:\n%s", content)
+	}
+
+	log, err = New(WithConsole(false), WithFilePath(pretty), WithFileFormat(FormatJSON), WithFileJSONIndent(true))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("pretty", "k", "v")
+	log.Info("again")
+	log.Close()
+	content := readFileString(t, pretty)
+	if !strings.Contains(content, "\n  \"k\": \"v\"\n") {
+		t.Errorf("Expected an indented record in the file, got:\n%s", content)
+	}
+	if records := splitJSONRecords(t, content); len(records) != 2 {
+		t.Errorf("Expected 2 records, got %d", len(records))
+	}
+}
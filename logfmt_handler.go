@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// logfmtKey converts an attribute key into a bare logfmt key: any space, '=', '"' or
+// control character becomes '_', as keys cannot be quoted
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || unicode.IsControl(r) || unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtNeedsQuote reports whether a value must be quoted: it is empty or contains a
+// space, '=', '"', a backslash or a character that isn't printable
+func logfmtNeedsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLogfmtPair appends ` key=value`, quoting and escaping the value when needed
+func appendLogfmtPair(buf *bytes.Buffer, key, value string) {
+	buf.WriteByte(' ')
+	buf.WriteString(logfmtKey(key))
+	buf.WriteByte('=')
+	if logfmtNeedsQuote(value) {
+		buf.WriteString(strconv.Quote(value))
+	} else {
+		buf.WriteString(value)
+	}
+}
+
+// logfmtHandler is a slog.Handler that writes logfmt lines:
+//
+//	time=2024-05-01T10:00:00.000Z level=INFO msg="user logged in" user.id=42
+//
+// Attributes in groups get dotted keys and ReplaceAttr applies to the built-in
+// time, level, msg and source attributes too, as with slog's own handlers.
+type logfmtHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	opts  slog.HandlerOptions
+	attrs flatAttrs // Groups and pairs from WithAttrs/WithGroup
+}
+
+// newLogfmtHandler creates a handler writing logfmt lines to w
+func newLogfmtHandler(w io.Writer, opts *slog.HandlerOptions) *logfmtHandler {
+	h := &logfmtHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	h.attrs = newFlatAttrs(h.opts.ReplaceAttr, appendLogfmtAttr)
+	return h
+}
+
+// Enabled implements slog.Handler
+func (h *logfmtHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *logfmtHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	if !r.Time.IsZero() {
+		h.attrs.appendAttr(&buf, nil, slog.Time(slog.TimeKey, r.Time))
+	}
+	h.attrs.appendAttr(&buf, nil, slog.Any(slog.LevelKey, r.Level))
+	if h.opts.AddSource {
+		if src := explicitSource(r); src != nil {
+			h.attrs.appendAttr(&buf, nil, slog.Any(slog.SourceKey, src))
+		} else if r.PC != 0 {
+			f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+			h.attrs.appendAttr(&buf, nil, slog.Any(slog.SourceKey, &slog.Source{Function: f.Function, File: f.File, Line: f.Line}))
+		}
+	}
+	h.attrs.appendAttr(&buf, nil, slog.String(slog.MessageKey, r.Message))
+	h.attrs.appendRecord(&buf, r)
+	buf.WriteByte('\n')
+
+	// Every pair starts with a space, the line doesn't
+	line := bytes.TrimPrefix(buf.Bytes(), []byte{' '})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(line)
+	return err
+}
+
+// appendLogfmtAttr encodes a flattened attribute as a pair
+func appendLogfmtAttr(buf *bytes.Buffer, key string, val slog.Value) {
+	var value string
+	switch val.Kind() {
+	case slog.KindTime:
+		value = val.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		switch v := val.Any().(type) {
+		case *slog.Source:
+			value = fmt.Sprintf("%s:%d", v.File, v.Line)
+		case error:
+			value = v.Error()
+		default:
+			value = fmt.Sprintf("%+v", v)
+		}
+	default:
+		value = val.String()
+	}
+	appendLogfmtPair(buf, key, value)
+}
+
+// WithAttrs implements slog.Handler
+func (h *logfmtHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	newHandler := *h
+	newHandler.attrs = h.attrs.withAttrs(attrs)
+	return &newHandler
+}
+
+// WithGroup implements slog.Handler
+func (h *logfmtHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	newHandler := *h
+	newHandler.attrs = h.attrs.withGroup(name)
+	return &newHandler
+}
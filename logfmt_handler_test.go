@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logfmtLine handles a record built from msg and attrs and returns the line without the newline
+func logfmtLine(t *testing.T, h slog.Handler, buf *bytes.Buffer, msg string, attrs ...slog.Attr) string {
+	t.Helper()
+	buf.Reset()
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, msg, 0)
+	r.AddAttrs(attrs...)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	line := buf.String()
+	if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+		t.Fatalf("Expected a single newline-terminated line, got %q", line)
+	}
+	return strings.TrimSuffix(line, "\n")
+}
+
+func TestLogfmtHandler_Quoting(t *testing.T) {
+	var buf bytes.Buffer
+	h := newLogfmtHandler(&buf, nil)
+
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{"bare", slog.String("user", "alice"), "user=alice"},
+		{"space", slog.String("name", "a b"), `name="a b"`},
+		{"quote", slog.String("q", `say "hi"`), `q="say \"hi\""`},
+		{"equals", slog.String("expr", "a=b"), `expr="a=b"`},
+		{"backslash", slog.String("path", `C:\logs`), `path="C:\\logs"`},
+		{"newline", slog.String("text", "line1\nline2"), `text="line1\nline2"`},
+		{"empty", slog.String("empty", ""), `empty=""`},
+		{"unicode", slog.String("city", "Zürich"), "city=Zürich"},
+		{"int", slog.Int("n", 42), "n=42"},
+		{"bool", slog.Bool("ok", true), "ok=true"},
+		{"duration", slog.Duration("took", 1500*time.Millisecond), "took=1.5s"},
+		{"error", slog.Any("err", errors.New("no such file")), `err="no such file"`},
+		{"key with space", slog.String("a key", "v"), "a_key=v"},
+		{"key with equals and quote", slog.String(`k="x"`, "v"), "k__x_=v"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := `level=INFO msg=hello ` + tt.want
+			if got := logfmtLine(t, h, &buf, "hello", tt.attr); got != want {
+				t.Errorf("Expected %s, got %s", want, got)
+			}
+		})
+	}
+
+	if got := logfmtLine(t, h, &buf, "user logged in"); got != `level=INFO msg="user logged in"` {
+		t.Errorf("Expected the message to be quoted, got %s", got)
+	}
+}
+
+func TestLogfmtHandler_Groups(t *testing.T) {
+	var buf bytes.Buffer
+	var h slog.Handler = newLogfmtHandler(&buf, nil)
+	h = h.WithAttrs([]slog.Attr{slog.String("service", "api")}).WithGroup("req").WithAttrs([]slog.Attr{slog.String("id", "r1")})
+
+	got := logfmtLine(t, h, &buf, "done",
+		slog.Int("status", 200),
+		slog.Group("user", slog.Int("id", 7), slog.String("role", "admin")),
+		slog.Group("", slog.String("inline", "x")),
+	)
+	want := "level=INFO msg=done service=api req.id=r1 req.status=200 req.user.id=7 req.user.role=admin req.inline=x"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestLogfmtHandler_ReplaceAttr(t *testing.T) {
+	var buf bytes.Buffer
+	h := newLogfmtHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch {
+			case a.Key == slog.LevelKey:
+				return slog.String(a.Key, strings.ToLower(a.Value.Any().(slog.Level).String()))
+			case a.Key == "password":
+				return slog.String(a.Key, "***")
+			case len(groups) > 0 && a.Key == "drop":
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+
+	got := logfmtLine(t, h.WithGroup("g"), &buf, "login",
+		slog.String("password", "secret"),
+		slog.String("drop", "me"),
+	)
+	if want := "level=info msg=login g.password=***"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestFormatLogfmt(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(logPath),
+		WithFileFormat(FormatLogfmt),
+		WithAddSource(true),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.With("component", "db").Warn("slow query", "table", "users", "took", 2*time.Second)
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	line := strings.TrimSpace(readFileString(t, logPath))
+	if !strings.HasPrefix(line, "time=") {
+		t.Errorf("Expected the line to start with the time, got %s", line)
+	}
+	for _, want := range []string{
+		" level=WARN ",
+		" source=",
+		"logfmt_handler_test.go:",
+		` msg="slow query" component=db table=users took=2s`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %s", want, line)
+		}
+	}
+}
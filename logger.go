@@ -1,15 +1,27 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"log"
 	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Logger wraps slog.Logger with automatic resource management
 // By embedding *slog.Logger, it inherits all methods like Info, Error, Debug, Warn, With, WithGroup, etc.
 type Logger struct {
 	*slog.Logger
-	closer io.Closer
+	closer   io.Closer
+	filePath string         // Path of the log file, empty when file logging is disabled
+	levelVar *slog.LevelVar // Live level threshold, nil for loggers not built by New
+
+	fileWriter *rotatingWriter // Writer of the log file, nil without file logging or with routing
+	profile    *handleProfile  // Self-profiling measurements, nil when disabled
 }
 
 // New creates a new Logger with automatic resource cleanup
@@ -20,8 +32,12 @@ func New(opts ...Option) (*Logger, error) {
 		return nil, err
 	}
 	return &Logger{
-		Logger: slog.New(result.handler),
-		closer: result.closer,
+		Logger:     slog.New(result.handler),
+		closer:     result.closer,
+		filePath:   result.filePath,
+		levelVar:   result.levelVar,
+		fileWriter: result.fileWriter,
+		profile:    result.profile,
 	}, nil
 }
 
@@ -42,11 +58,172 @@ func (l *Logger) SetDefault() {
 	slog.SetDefault(l.Logger)
 }
 
+// SetDefaultRestore sets the current logger as the default logger and returns a function
+// that restores the previous default, e.g. defer log.SetDefaultRestore()().
+// The standard log package's output and flags, which SetDefault redirects, are restored too.
+func (l *Logger) SetDefaultRestore() (restore func()) {
+	prev := slog.Default()
+	prevOutput, prevFlags := log.Writer(), log.Flags()
+
+	slog.SetDefault(l.Logger)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			slog.SetDefault(prev)
+			log.SetOutput(prevOutput)
+			log.SetFlags(prevFlags)
+		})
+	}
+}
+
 // Close cleans up any resources held by the logger
 // Always call this when you're done with the logger to prevent resource leaks
 func (l *Logger) Close() error {
-	if l.closer != nil {
-		return l.closer.Close()
+	return l.CloseContext(context.Background())
+}
+
+// CloseContext is Close with a deadline: it returns ctx.Err() if flushing and closing the
+// destinations hasn't finished when ctx is done, e.g. because a hung filesystem blocks a
+// flush or compression, so shutdown fits a grace period such as a Kubernetes pod's.
+// The cleanup keeps running in the background and its error is then lost.
+func (l *Logger) CloseContext(ctx context.Context) error {
+	if l.closer == nil {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- l.closer.Close()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetLevel changes the minimum level of the logger at runtime, e.g. to switch to Debug
+// from a signal handler or admin endpoint. It applies to every destination and to all
+// loggers derived from this one. Loggers not created by New are left unchanged.
+func (l *Logger) SetLevel(level slog.Level) {
+	if l.levelVar != nil {
+		l.levelVar.Set(level)
+	}
+}
+
+// GetLevel returns the current minimum level of the logger.
+// Loggers not created by New report slog's default, Info.
+func (l *Logger) GetLevel() slog.Level {
+	if l.levelVar == nil {
+		return slog.LevelInfo
+	}
+	return l.levelVar.Level()
+}
+
+// SetFilePath switches file output to a new path at runtime, e.g. when migrating a tenant,
+// keeping the rotation and retention settings. Records logged concurrently end up entirely
+// in either the old or the new file. The path is used as given (no ~ or $VAR expansion).
+// It fails if file logging is disabled or routed with WithRoutingKey.
+func (l *Logger) SetFilePath(path string) error {
+	if l.fileWriter == nil {
+		if l.filePath != "" {
+			return fmt.Errorf("cannot change the file path of a routed logger")
+		}
+		return fmt.Errorf("file logging is not enabled")
+	}
+	if path == "" {
+		return fmt.Errorf("file path must not be empty")
+	}
+	if err := l.fileWriter.setPath(path); err != nil {
+		return fmt.Errorf("failed to switch log file: %w", err)
 	}
 	return nil
 }
+
+// Debugv logs at LevelDebug with typed attributes, avoiding the boxing of the variadic any API.
+// It is equivalent to LogAttrs(context.Background(), slog.LevelDebug, msg, attrs...).
+func (l *Logger) Debugv(msg string, attrs ...slog.Attr) {
+	l.logAttrs(slog.LevelDebug, msg, attrs)
+}
+
+// Infov logs at LevelInfo with typed attributes, avoiding the boxing of the variadic any API
+func (l *Logger) Infov(msg string, attrs ...slog.Attr) {
+	l.logAttrs(slog.LevelInfo, msg, attrs)
+}
+
+// Warnv logs at LevelWarn with typed attributes, avoiding the boxing of the variadic any API
+func (l *Logger) Warnv(msg string, attrs ...slog.Attr) {
+	l.logAttrs(slog.LevelWarn, msg, attrs)
+}
+
+// Errorv logs at LevelError with typed attributes, avoiding the boxing of the variadic any API
+func (l *Logger) Errorv(msg string, attrs ...slog.Attr) {
+	l.logAttrs(slog.LevelError, msg, attrs)
+}
+
+// logAttrs logs with typed attributes for the level methods, e.g. Infov
+func (l *Logger) logAttrs(level slog.Level, msg string, attrs []slog.Attr) {
+	logAttrsAt(context.Background(), l.Logger, 2, level, msg, attrs) // skip [logAttrs, level method]
+}
+
+// logAttrsAt builds the record the way slog.Logger.LogAttrs does, but records as the source
+// the function skip frames above its caller instead of this package, e.g. the caller of a
+// level method
+func logAttrsAt(ctx context.Context, logger *slog.Logger, skip int, level slog.Level, msg string, attrs []slog.Attr) {
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:]) // skip [Callers, logAttrsAt] too
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+// WriterAt returns an io.Writer that logs each line written to it as a record at level.
+// Input is split on newlines and trailing newlines are trimmed, so no blank records are
+// emitted. Useful for libraries that log to an io.Writer, e.g.
+// http.Server{ErrorLog: log.New(l.WriterAt(slog.LevelError), "", 0)}.
+func (l *Logger) WriterAt(level slog.Level) io.Writer {
+	return &levelWriter{logger: l.Logger, level: level}
+}
+
+// levelWriter adapts a logger to io.Writer at a fixed level
+type levelWriter struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// Write implements io.Writer
+func (w *levelWriter) Write(p []byte) (int, error) {
+	text := strings.TrimRight(string(p), "\r\n")
+	if text == "" {
+		return len(p), nil
+	}
+	for _, line := range strings.Split(text, "\n") {
+		w.logger.Log(context.Background(), w.level, strings.TrimSuffix(line, "\r"))
+	}
+	return len(p), nil
+}
+
+// WithError returns a child logger whose records all carry err under the "error" key,
+// like Event.Err. A nil error returns l itself.
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+	return &Logger{
+		Logger:     slog.New(l.Handler().WithAttrs([]slog.Attr{slog.Any("error", err)})),
+		filePath:   l.filePath,
+		levelVar:   l.levelVar,
+		fileWriter: l.fileWriter,
+		profile:    l.profile,
+	}
+}
+
+// Stats returns the handling overhead measured with WithSelfProfiling,
+// or the zero Stats when self-profiling is disabled
+func (l *Logger) Stats() Stats {
+	return l.profile.stats()
+}
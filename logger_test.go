@@ -2,6 +2,9 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -55,6 +58,39 @@ func TestSetDefault(t *testing.T) {
 	}
 }
 
+func TestSetDefaultRestore(t *testing.T) {
+	originalDefault := slog.Default()
+	originalOutput, originalFlags := log.Writer(), log.Flags()
+
+	var buf bytes.Buffer
+	logger := &Logger{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+
+	restore := logger.SetDefaultRestore()
+	if slog.Default() != logger.Logger {
+		t.Fatal("Expected logger to become the default")
+	}
+	slog.Info("via default")
+	log.Print("via log package")
+	if !strings.Contains(buf.String(), "via default") || !strings.Contains(buf.String(), "via log package") {
+		t.Errorf("Expected default and log package output in logger, got %q", buf.String())
+	}
+
+	restore()
+	if slog.Default() != originalDefault {
+		t.Error("Expected previous default logger to be restored")
+	}
+	if log.Writer() != originalOutput || log.Flags() != originalFlags {
+		t.Error("Expected log package output and flags to be restored")
+	}
+
+	// Restoring twice is harmless
+	slog.SetDefault(originalDefault)
+	restore()
+	if slog.Default() != originalDefault {
+		t.Error("Expected second restore to be a no-op")
+	}
+}
+
 func TestLoggerMethods(t *testing.T) {
 	// Create a test logger with a buffer
 	var buf bytes.Buffer
@@ -95,6 +131,43 @@ func TestLoggerMethods(t *testing.T) {
 	}
 }
 
+func TestLoggerWriterAt(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := &Logger{
+		Logger: slog.New(handler),
+	}
+
+	w := logger.WriterAt(slog.LevelWarn)
+	input := "first line\nsecond line\r\nthird line\n\n"
+	n, err := w.Write([]byte(input))
+	if err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if n != len(input) {
+		t.Errorf("Expected %d bytes written, got %d", len(input), n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 records, got %d: %q", len(lines), buf.String())
+	}
+	for i, want := range []string{"first line", "second line", "third line"} {
+		if !strings.Contains(lines[i], "level=WARN") || !strings.Contains(lines[i], `msg="`+want+`"`) {
+			t.Errorf("Line %d = %q, expected WARN record with message %q", i, lines[i], want)
+		}
+	}
+
+	// Newline-only input produces no records
+	buf.Reset()
+	if _, err := w.Write([]byte("\n")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no record for blank input, got %q", buf.String())
+	}
+}
+
 func TestCustomHandlerIntegration(t *testing.T) {
 	// Create a temporary file for testing
 	tmpFile, err := os.CreateTemp("", "logger_test_*.log")
@@ -309,3 +382,296 @@ func TestResourceLeakPrevention(t *testing.T) {
 		time.Sleep(100 * time.Millisecond)
 	})
 }
+
+func TestWithLevelVar(t *testing.T) {
+	var levelVar slog.LevelVar
+	levelVar.Set(slog.LevelWarn)
+
+	logPath := filepath.Join(t.TempDir(), "levelvar.log")
+	logger, err := New(
+		WithConsole(false),
+		WithFilePath(logPath),
+		WithFileFormat(FormatText),
+		WithLevel(slog.LevelDebug), // overridden by the LevelVar
+		WithLevelVar(&levelVar),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	if logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Expected Info to be disabled while the var is Warn")
+	}
+	logger.Info("dropped info")
+	logger.Warn("kept warn")
+
+	// Changing the external var changes the effective level immediately
+	levelVar.Set(slog.LevelDebug)
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected Debug to be enabled after lowering the var")
+	}
+	logger.Debug("kept debug")
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	output := string(content)
+	if strings.Contains(output, "dropped info") {
+		t.Errorf("Expected info record to be filtered, got %q", output)
+	}
+	if !strings.Contains(output, "kept warn") || !strings.Contains(output, "kept debug") {
+		t.Errorf("Expected warn and debug records, got %q", output)
+	}
+}
+
+func TestLoggerSetLevel(t *testing.T) {
+	for _, format := range []OutputFormat{FormatCustom, FormatJSON, FormatText} {
+		t.Run(string(format), func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "level.log")
+			logger, err := New(WithConsole(false), WithFilePath(logPath), WithFileFormat(format))
+			if err != nil {
+				t.Fatalf("Failed to create logger: %v", err)
+			}
+			child := logger.With("component", "db")
+
+			if got := logger.GetLevel(); got != slog.LevelInfo {
+				t.Errorf("Expected initial level INFO, got %v", got)
+			}
+			child.Debug("suppressed debug")
+
+			logger.SetLevel(slog.LevelDebug)
+			if got := logger.GetLevel(); got != slog.LevelDebug {
+				t.Errorf("Expected level DEBUG after SetLevel, got %v", got)
+			}
+			child.Debug("visible debug")
+
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close() failed: %v", err)
+			}
+			content, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("Failed to read log file: %v", err)
+			}
+			output := string(content)
+			if strings.Contains(output, "suppressed debug") {
+				t.Errorf("Expected debug to be suppressed before SetLevel, got %q", output)
+			}
+			if !strings.Contains(output, "visible debug") {
+				t.Errorf("Expected debug to appear after SetLevel, got %q", output)
+			}
+		})
+	}
+
+	// A logger not built by New has no level to change
+	def := Default()
+	def.SetLevel(slog.LevelDebug)
+	if got := def.GetLevel(); got != slog.LevelInfo {
+		t.Errorf("Expected Default() to report INFO, got %v", got)
+	}
+}
+
+func TestLoggerAttrMethods(t *testing.T) {
+	handler := NewCaptureHandler(slog.LevelInfo)
+	logger := &Logger{Logger: slog.New(handler)}
+
+	logger.Debugv("dropped", slog.Int("n", 0))
+	logger.Infov("info", slog.Int("n", 1))
+	logger.Warnv("warn", slog.Int("n", 2))
+	logger.Errorv("error", slog.Int("n", 3), slog.Group("req", slog.String("id", "r-1")))
+
+	records := handler.Records()
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	wantLevels := []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+	for i, rec := range records {
+		if rec.Level != wantLevels[i] {
+			t.Errorf("Record %d: expected level %v, got %v", i, wantLevels[i], rec.Level)
+		}
+		if v, ok := rec.Attr("n"); !ok || v.Int64() != int64(i+1) {
+			t.Errorf("Record %d: expected n=%d, got %v", i, i+1, v)
+		}
+		if rec.Source == nil || !strings.HasSuffix(rec.Source.File, "logger_test.go") {
+			t.Errorf("Record %d: expected the caller as source, got %+v", i, rec.Source)
+		}
+	}
+	if v, ok := records[2].Attr("req.id"); !ok || v.String() != "r-1" {
+		t.Errorf("Expected req.id=r-1, got %v", v)
+	}
+}
+
+func TestLoggerWithError(t *testing.T) {
+	handler := NewCaptureHandler(slog.LevelInfo)
+	logger := &Logger{Logger: slog.New(handler)}
+	errTimeout := errors.New("timeout")
+
+	child := logger.WithError(errTimeout)
+	child.Warn("retrying", "attempt", 1)
+	child.Error("giving up")
+	child.With("op", "sync").Info("cleanup")
+	logger.Info("unrelated")
+
+	records := handler.Records()
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(records))
+	}
+	for i, rec := range records[:3] {
+		if v, ok := rec.Attr("error"); !ok || v.Any() != errTimeout {
+			t.Errorf("Record %d (%s): expected error=%v, got %v", i, rec.Message, errTimeout, v)
+		}
+	}
+	if _, ok := records[3].Attr("error"); ok {
+		t.Error("Expected the parent logger to be unaffected")
+	}
+
+	if logger.WithError(nil) != logger {
+		t.Error("Expected WithError(nil) to return the same logger")
+	}
+}
+
+func TestLoggerSetFilePath(t *testing.T) {
+	tempDir := t.TempDir()
+	oldPath := filepath.Join(tempDir, "tenant-a.log")
+	newPath := filepath.Join(tempDir, "moved", "tenant-b.log")
+
+	l, err := New(WithConsole(false), WithFilePath(oldPath), WithFileFormatter("{message} {attrs}"))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer l.Close()
+
+	// A writer keeps logging while the path is switched; no record may be lost or split
+	const total = 2000
+	switched := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < total; i++ {
+			if i == total/2 {
+				<-switched
+			}
+			l.Info("record", "i", i)
+		}
+	}()
+
+	l.Info("before switch")
+	if err := l.SetFilePath(newPath); err != nil {
+		t.Fatalf("SetFilePath() failed: %v", err)
+	}
+	close(switched)
+	<-done
+	l.Info("after switch")
+
+	oldContent, err := os.ReadFile(oldPath)
+	if err != nil {
+		t.Fatalf("Failed to read old file: %v", err)
+	}
+	newContent, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatalf("Failed to read new file: %v", err)
+	}
+	if !strings.Contains(string(oldContent), "before switch") || strings.Contains(string(oldContent), "after switch") {
+		t.Errorf("Unexpected old file content: %q", oldContent)
+	}
+	if !strings.Contains(string(newContent), "after switch") || strings.Contains(string(newContent), "before switch") {
+		t.Errorf("Unexpected new file content: %q", newContent)
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(oldContent)+string(newContent), "\n") {
+		if strings.HasPrefix(line, "record i=") {
+			seen[line] = true
+		}
+	}
+	if len(seen) != total {
+		t.Errorf("Expected %d complete records across both files, got %d", total, len(seen))
+	}
+
+	if files, err := l.RotatedFiles(); err != nil || len(files) != 0 {
+		t.Errorf("Expected RotatedFiles to follow the new path, got %v (err %v)", files, err)
+	}
+}
+
+func TestLoggerSetFilePath_Errors(t *testing.T) {
+	consoleOnly, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	if err := consoleOnly.SetFilePath(filepath.Join(t.TempDir(), "app.log")); err == nil {
+		t.Error("Expected an error without file logging")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(WithConsole(false), WithFilePath(path))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer l.Close()
+
+	// A path under a regular file can't be opened; logging continues on the old file
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("Failed to create blocker: %v", err)
+	}
+	if err := l.SetFilePath(filepath.Join(blocker, "app.log")); err == nil {
+		t.Error("Expected an error for an unusable path")
+	}
+	l.Info("still here")
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "still here") {
+		t.Errorf("Expected logging to continue on the old file, got %q", content)
+	}
+}
+
+// blockingWriter is an io.WriteCloser whose Close hangs until release is closed, like a stuck disk
+type blockingWriter struct {
+	bytes.Buffer
+	release chan struct{}
+	closed  chan struct{}
+}
+
+func (w *blockingWriter) Close() error {
+	<-w.release
+	close(w.closed)
+	return nil
+}
+
+func TestLoggerCloseContext(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{}), closed: make(chan struct{})}
+	log, err := New(WithConsole(false), WithWriter(out, FormatText))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("before shutdown")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = log.CloseContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected CloseContext to return within the deadline, took %v", elapsed)
+	}
+
+	// The cleanup goes on in the background
+	close(out.release)
+	select {
+	case <-out.closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the background cleanup to close the writer")
+	}
+
+	// Without a deadline issue it returns the result of closing
+	log, err = New(WithConsole(false), WithWriter(&bytes.Buffer{}, FormatText))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := log.CloseContext(context.Background()); err != nil {
+		t.Errorf("CloseContext() failed: %v", err)
+	}
+}
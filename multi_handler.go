@@ -18,6 +18,9 @@ func newMultiHandler(handlers ...slog.Handler) slog.Handler {
 }
 
 // Enabled implements slog.Handler
+// It reports true if ANY child is enabled, since slog.Logger only builds a record once
+// for all destinations. Handle then re-checks each child, so a child whose own Enabled
+// fails never formats or writes the record.
 func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	for _, handler := range h.handlers {
 		if handler.Enabled(ctx, level) {
@@ -29,14 +32,28 @@ func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 
 // Handle implements slog.Handler
 func (h *multiHandler) Handle(ctx context.Context, r slog.Record) error {
-	var errs []error
-
-	// Distribute the record to all handlers sequentially
+	// Only children interested in this level do any work. The array keeps the usual
+	// handful of destinations off the heap.
+	var buf [4]slog.Handler
+	enabled := buf[:0]
 	for _, handler := range h.handlers {
 		if handler.Enabled(ctx, r.Level) {
-			if err := handler.Handle(ctx, r.Clone()); err != nil {
-				errs = append(errs, err)
-			}
+			enabled = append(enabled, handler)
+		}
+	}
+
+	// A single interested child gets the record as is; cloning is only
+	// needed when several handlers could modify the shared attributes
+	if len(enabled) == 1 {
+		return enabled[0].Handle(ctx, r)
+	}
+
+	var errs []error
+
+	// Distribute the record to all enabled handlers sequentially
+	for _, handler := range enabled {
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
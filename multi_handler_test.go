@@ -123,6 +123,71 @@ func TestMultiHandler(t *testing.T) {
 	})
 }
 
+// TestMultiHandler_MixedLevels tests that only children enabled for a record's level write it
+func TestMultiHandler_HandleAllocs(t *testing.T) {
+	ctx := context.Background()
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	for _, tt := range []struct {
+		name     string
+		handlers []slog.Handler
+	}{
+		{"Single enabled", []slog.Handler{&mockHandler{enabled: true}, &mockHandler{}}},
+		{"Several enabled", []slog.Handler{&mockHandler{enabled: true}, &mockHandler{enabled: true}, &mockHandler{}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newMultiHandler(tt.handlers...)
+			if allocs := testing.AllocsPerRun(100, func() { h.Handle(ctx, r) }); allocs != 0 {
+				t.Errorf("Expected Handle not to allocate, got %.1f allocations per record", allocs)
+			}
+		})
+	}
+}
+
+func TestMultiHandler_MixedLevels(t *testing.T) {
+	var debugBuf, errorBuf bytes.Buffer
+	debugHandler := slog.NewTextHandler(&debugBuf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	errorHandler := slog.NewTextHandler(&errorBuf, &slog.HandlerOptions{Level: slog.LevelError})
+	logger := slog.New(newMultiHandler(debugHandler, errorHandler))
+
+	// Expensive values are only resolved by handlers that actually write the record
+	var resolved int32
+	expensive := countingValuer{count: &resolved}
+
+	logger.Info("info only for debug handler", "payload", expensive)
+	if !strings.Contains(debugBuf.String(), "info only for debug handler") {
+		t.Errorf("Expected debug handler to write info record, got %q", debugBuf.String())
+	}
+	if errorBuf.Len() != 0 {
+		t.Errorf("Expected error handler to write nothing, got %q", errorBuf.String())
+	}
+	if n := atomic.LoadInt32(&resolved); n != 1 {
+		t.Errorf("Expected value resolved once by the enabled handler, got %d", n)
+	}
+
+	logger.Error("error for both")
+	if !strings.Contains(debugBuf.String(), "error for both") || !strings.Contains(errorBuf.String(), "error for both") {
+		t.Errorf("Expected both handlers to write error record, got %q and %q", debugBuf.String(), errorBuf.String())
+	}
+
+	logger.Debug("debug only", "payload", expensive)
+	if strings.Contains(errorBuf.String(), "debug only") {
+		t.Errorf("Expected error handler to skip debug record, got %q", errorBuf.String())
+	}
+	if n := atomic.LoadInt32(&resolved); n != 2 {
+		t.Errorf("Expected value resolved by the enabled handler only, got %d", n)
+	}
+}
+
+// countingValuer counts how many times it is resolved
+type countingValuer struct {
+	count *int32
+}
+
+func (v countingValuer) LogValue() slog.Value {
+	atomic.AddInt32(v.count, 1)
+	return slog.StringValue("expensive")
+}
+
 // TestMultiHandler_ConcurrentWrites tests concurrent writes to multiple handlers
 func TestMultiHandler_ConcurrentWrites(t *testing.T) {
 	var buf1, buf2 bytes.Buffer
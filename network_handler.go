@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNetworkTimeout bounds dialing the collector and each write to it
+const DefaultNetworkTimeout = 5 * time.Second
+
+// Bounds of the delay between redials while the collector is unreachable
+const (
+	minNetworkRedialDelay = 100 * time.Millisecond
+	maxNetworkRedialDelay = 30 * time.Second
+)
+
+// errNetworkClosed is returned by writes after the network writer was closed
+var errNetworkClosed = errors.New("network writer is closed")
+
+// errNetworkUnavailable is returned by writes dropped while waiting to redial the collector
+var errNetworkUnavailable = errors.New("network collector unavailable, waiting to redial")
+
+// networkWriter is an io.Writer over a net.Conn to a log collector.
+// A failed TCP write drops the connection and the next write dials again,
+// so a restarted collector is picked up without restarting the application.
+// While dialing fails, redials back off exponentially and the records in
+// between are dropped, so logging goroutines aren't each held up by a dial.
+type networkWriter struct {
+	mu      sync.Mutex
+	network string
+	address string
+	timeout time.Duration
+	conn    net.Conn
+	closed  bool
+
+	redialDelay time.Duration    // Wait after the last failed dial, doubled on each failure
+	nextDial    time.Time        // No dial is attempted before this time
+	now         func() time.Time // clock for redial delays, replaceable in tests
+}
+
+// newNetworkWriter dials address, failing if the collector can't be reached
+func newNetworkWriter(network, address string, timeout time.Duration) (*networkWriter, error) {
+	w := &networkWriter{network: network, address: address, timeout: timeout, now: time.Now}
+	if err := w.dialLocked(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// dialLocked connects to the collector. A failure delays the next attempt, twice as long as
+// the previous one up to maxNetworkRedialDelay; a success resets the delay.
+func (w *networkWriter) dialLocked() error {
+	conn, err := net.DialTimeout(w.network, w.address, w.timeout)
+	if err != nil {
+		w.redialDelay = min(max(2*w.redialDelay, minNetworkRedialDelay), maxNetworkRedialDelay)
+		w.nextDial = w.now().Add(w.redialDelay)
+		return fmt.Errorf("failed to dial %s %s: %w", w.network, w.address, err)
+	}
+	w.conn = conn
+	w.redialDelay = 0
+	return nil
+}
+
+// Write sends p over the connection, redialing first if a previous write dropped it.
+// Each write is one record, so over UDP every record is a single datagram. Until the
+// redial delay of a failed dial elapses, p is dropped with errNetworkUnavailable.
+func (w *networkWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, errNetworkClosed
+	}
+	if w.conn == nil {
+		if w.now().Before(w.nextDial) {
+			return 0, errNetworkUnavailable
+		}
+		if err := w.dialLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	n, err := w.conn.Write(p)
+	if err != nil && strings.HasPrefix(w.network, "tcp") {
+		// The stream may hold a partial record, start over on a fresh connection
+		w.conn.Close()
+		w.conn = nil
+	}
+	return n, err
+}
+
+// Close closes the connection; later writes fail with errNetworkClosed
+func (w *networkWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// newNetworkHandler connects to the configured collector and returns a JSON handler
+// writing one line per record, with the connection as its closer
+func newNetworkHandler(cfg *Config) (slog.Handler, io.Closer, error) {
+	writer, err := newNetworkWriter(cfg.Network.Network, cfg.Network.Address, DefaultNetworkTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slog.NewJSONHandler(reportWrites(writer, cfg), newHandlerOptions(cfg)), writer, nil
+}
+
+// isValidNetwork reports whether network is a stream or datagram network supported by WithNetwork
+func isValidNetwork(network string) bool {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readJSONLine reads one JSON record from r, failing the test on timeout or bad JSON
+func readJSONLine(t *testing.T, conn net.Conn, r *bufio.Reader) map[string]any {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := r.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read record: %v", err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON line %q: %v", line, err)
+	}
+	return entry
+}
+
+func TestWithNetwork_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	filePath := filepath.Join(t.TempDir(), "app.log")
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(filePath),
+		WithNetwork("tcp", ln.Addr().String()),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	defer conn.Close()
+
+	log.Info("shipped", "user", "alice")
+	entry := readJSONLine(t, conn, bufio.NewReader(conn))
+	if entry["msg"] != "shipped" || entry["user"] != "alice" {
+		t.Errorf("Unexpected record: %v", entry)
+	}
+	if content := readFileString(t, filePath); !strings.Contains(content, "shipped") {
+		t.Errorf("Expected the file destination to receive the record too, got %q", content)
+	}
+}
+
+func TestWithNetwork_UDP(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer pc.Close()
+
+	log, err := New(WithConsole(false), WithNetwork("udp", pc.LocalAddr().String()))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	log.Warn("datagram", "n", 1)
+
+	buf := make([]byte, 64*1024)
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read datagram: %v", err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf[:n], &entry); err != nil {
+		t.Fatalf("Expected one JSON record per datagram, got %q: %v", buf[:n], err)
+	}
+	if entry["msg"] != "datagram" || entry["level"] != "WARN" {
+		t.Errorf("Unexpected record: %v", entry)
+	}
+}
+
+func TestNetworkWriter_RedialsAfterFailedWrite(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	w, err := newNetworkWriter("tcp", ln.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("newNetworkWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	// The collector drops the first connection
+	first, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	first.Close()
+
+	// Writes to a closed peer only fail once the reset arrives
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := w.Write([]byte("lost\n")); err != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected a write to the closed connection to fail")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := w.Write([]byte("{\"msg\":\"after redial\"}\n")); err != nil {
+		t.Fatalf("Expected the next write to redial, got %v", err)
+	}
+	second, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	defer second.Close()
+	if entry := readJSONLine(t, second, bufio.NewReader(second)); entry["msg"] != "after redial" {
+		t.Errorf("Unexpected record after redial: %v", entry)
+	}
+}
+
+func TestNetworkWriter_RedialBackoff(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	w, err := newNetworkWriter("tcp", ln.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("newNetworkWriter() failed: %v", err)
+	}
+	defer w.Close()
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	// The collector goes away along with the connection
+	ln.Close()
+	w.conn.Close()
+	w.conn = nil
+
+	if _, err := w.Write([]byte("dial fails\n")); err == nil || errors.Is(err, errNetworkUnavailable) {
+		t.Fatalf("Expected the dial error, got %v", err)
+	}
+	if _, err := w.Write([]byte("dropped\n")); !errors.Is(err, errNetworkUnavailable) {
+		t.Fatalf("Expected the record to be dropped without dialing, got %v", err)
+	}
+
+	// Each failed redial doubles the delay, up to the maximum
+	want := minNetworkRedialDelay
+	for i := 0; i < 12; i++ {
+		if w.redialDelay != want {
+			t.Fatalf("Attempt %d: expected a redial delay of %v, got %v", i, want, w.redialDelay)
+		}
+		now = now.Add(w.redialDelay)
+		if _, err := w.Write([]byte("dial fails\n")); errors.Is(err, errNetworkUnavailable) {
+			t.Fatalf("Attempt %d: expected a redial once the delay elapsed", i)
+		}
+		want = min(2*want, maxNetworkRedialDelay)
+	}
+}
+
+func TestNetworkWriter_Close(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	w, err := newNetworkWriter("tcp", ln.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("newNetworkWriter() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Close() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Second Close() failed: %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, errNetworkClosed) {
+		t.Errorf("Expected errNetworkClosed after Close, got %v", err)
+	}
+}
+
+func TestWithNetwork_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		network string
+		address string
+	}{
+		{"unsupported network", "unix", "/tmp/collector.sock"},
+		{"empty address", "tcp", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			WithNetwork(tt.network, tt.address)(cfg)
+			if err := validateConfig(cfg); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+
+	// Network alone is enough of a destination
+	cfg := DefaultConfig()
+	WithConsole(false)(cfg)
+	WithNetwork("udp", "127.0.0.1:514")(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("Expected network-only config to be valid, got %v", err)
+	}
+}
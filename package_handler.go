@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// PackageKey is the attribute key for the caller's package import path
+const PackageKey = "pkg"
+
+// callerPackage returns the import path of the package a fully-qualified function name
+// belongs to, e.g. "example.com/app/internal/db" for "example.com/app/internal/db.(*Conn).Query".
+// The package ends at the first dot after the last slash, as the path itself may contain dots.
+func callerPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	if dot := strings.IndexByte(function[slash+1:], '.'); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// packageHandler is a slog.Handler that adds the caller's package to each record
+type packageHandler struct {
+	handler slog.Handler
+}
+
+// newPackageHandler wraps a handler so each record with a caller carries a pkg attribute
+func newPackageHandler(handler slog.Handler) slog.Handler {
+	return &packageHandler{handler: handler}
+}
+
+// Enabled implements slog.Handler
+func (h *packageHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *packageHandler) Handle(ctx context.Context, r slog.Record) error {
+	function := ""
+	if src := explicitSource(r); src != nil {
+		function = src.Function
+	} else if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		function = frame.Function
+	}
+	if function == "" {
+		return h.handler.Handle(ctx, r)
+	}
+	r = r.Clone()
+	r.AddAttrs(slog.String(PackageKey, callerPackage(function)))
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *packageHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &packageHandler{handler: h.handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h *packageHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &packageHandler{handler: h.handler.WithGroup(name)}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestCallerPackage(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{"github.com/simp-lee/logger.TestCallerPackage", "github.com/simp-lee/logger"},
+		{"example.com/app/internal/db.(*Conn).Query", "example.com/app/internal/db"},
+		{"example.com/app/internal/db.Open.func1", "example.com/app/internal/db"},
+		{"main.main", "main"},
+		{"main", "main"},
+	}
+	for _, tt := range tests {
+		if got := callerPackage(tt.function); got != tt.want {
+			t.Errorf("callerPackage(%q) = %q, want %q", tt.function, got, tt.want)
+		}
+	}
+}
+
+func TestPackagePlaceholder(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.AddSource = true
+
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{pkg} {level} {message}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+	slog.New(handler).Info("hello")
+
+	if got := strings.TrimSpace(buf.String()); got != "github.com/simp-lee/logger INFO hello" {
+		t.Errorf("Unexpected output %q", got)
+	}
+
+	// Without a source, {pkg} is empty and its surrounding space is collapsed
+	buf.Reset()
+	cfg.AddSource = false
+	handler, _ = newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{level} {pkg} {message}",
+	}, nil)
+	slog.New(handler).Info("hello")
+	if got := strings.TrimSpace(buf.String()); got != "INFO hello" {
+		t.Errorf("Unexpected output without source %q", got)
+	}
+}
+
+func TestPackageHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newPackageHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.Info("implicit")
+	logger.Info("explicit", slog.Any(slog.SourceKey, &slog.Source{Function: "example.com/app/internal/db.Open", File: "db.go", Line: 1}))
+
+	entries := decodeLines(t, &buf)
+	if got := entries[0][PackageKey]; got != "github.com/simp-lee/logger" {
+		t.Errorf("Expected pkg from the call site, got %v", got)
+	}
+	if got := entries[1][PackageKey]; got != "example.com/app/internal/db" {
+		t.Errorf("Expected pkg from the explicit source, got %v", got)
+	}
+
+	var entry map[string]any
+	buf.Reset()
+	if err := slog.New(newPackageHandler(slog.NewJSONHandler(&buf, nil))).Handler().Handle(context.Background(), slog.Record{Message: "no pc"}); err != nil {
+		t.Fatalf("Handle failed: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if _, ok := entry[PackageKey]; ok {
+		t.Error("Expected no pkg attribute for a record without a caller")
+	}
+}
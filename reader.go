@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// OpenLogFile opens a current or rotated log file for reading.
+// Files ending in .gz are transparently decompressed; other files are returned as-is.
+func OpenLogFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to open gzip log file: %w", err)
+	}
+	return &gzipReadCloser{Reader: gz, file: f}, nil
+}
+
+// gzipReadCloser closes both the gzip stream and the underlying file
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r *gzipReadCloser) Close() error {
+	gzErr := r.Reader.Close()
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	return gzErr
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenLogFile(t *testing.T) {
+	tempDir := t.TempDir()
+	content := "line one\nline two\n"
+
+	t.Run("Plain file", func(t *testing.T) {
+		path := filepath.Join(tempDir, "app.20240101.120000.000.log")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		r, err := OpenLogFile(path)
+		if err != nil {
+			t.Fatalf("OpenLogFile() failed: %v", err)
+		}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ReadAll failed: %v", err)
+		}
+		if string(data) != content {
+			t.Errorf("Expected %q, got %q", content, data)
+		}
+	})
+
+	t.Run("Gzip file", func(t *testing.T) {
+		path := filepath.Join(tempDir, "app.20240101.120000.000.log.gz")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		gz := gzip.NewWriter(f)
+		if _, err := gz.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write gzip data: %v", err)
+		}
+		gz.Close()
+		f.Close()
+
+		r, err := OpenLogFile(path)
+		if err != nil {
+			t.Fatalf("OpenLogFile() failed: %v", err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("ReadAll failed: %v", err)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+		if string(data) != content {
+			t.Errorf("Expected %q, got %q", content, data)
+		}
+	})
+
+	t.Run("Corrupt gzip file", func(t *testing.T) {
+		path := filepath.Join(tempDir, "corrupt.log.gz")
+		if err := os.WriteFile(path, []byte("not gzip"), 0o644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := OpenLogFile(path); err == nil {
+			t.Error("Expected error for corrupt gzip file")
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		if _, err := OpenLogFile(filepath.Join(tempDir, "missing.log")); err == nil {
+			t.Error("Expected error for missing file")
+		}
+	})
+}
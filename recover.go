@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Attribute keys used for recovered panics
+const (
+	PanicTypeKey  = "panic_type"
+	PanicValueKey = "panic_value"
+	StackKey      = "stack"
+)
+
+// maxStackFrames bounds the number of frames captured for a stack trace
+const maxStackFrames = 64
+
+// Recover logs a panic in progress as an Error record and stops it. It must be deferred
+// directly, e.g. defer log.Recover(). The record carries the panic's dynamic type
+// (panic_type), its value as a structured attribute (panic_value) and the stack of the
+// panicking goroutine (stack), so panic logs can be queried by field.
+func (l *Logger) Recover() {
+	if p := recover(); p != nil {
+		pcs := make([]uintptr, maxStackFrames)
+		n := runtime.Callers(2, pcs) // skip [Callers, Recover]
+		l.logPanic(p, pcs[:n])
+	}
+}
+
+// logPanic emits the structured record for a recovered panic value with the stack pcs.
+// The record's source is the panicking function rather than this file.
+func (l *Logger) logPanic(p any, pcs []uintptr) {
+	ctx := context.Background()
+	if !l.Enabled(ctx, slog.LevelError) {
+		return
+	}
+	r := slog.NewRecord(time.Now(), slog.LevelError, "panic recovered", panicPC(pcs))
+	r.AddAttrs(
+		slog.String(PanicTypeKey, fmt.Sprintf("%T", p)),
+		slog.Any(PanicValueKey, p),
+		slog.String(StackKey, formatStack(pcs)),
+	)
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// panicPC returns the first PC of pcs outside the runtime, i.e. the frame that panicked
+// above the runtime's panic machinery, or 0 if there is none
+func panicPC(pcs []uintptr) uintptr {
+	for i, pc := range pcs {
+		frame, _ := runtime.CallersFrames(pcs[i : i+1]).Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			return pc
+		}
+	}
+	return 0
+}
+
+// formatStack formats the frames of pcs, one "function\n\tfile:line" entry per frame,
+// leaving out leading runtime frames
+func formatStack(pcs []uintptr) string {
+	frames := runtime.CallersFrames(pcs)
+
+	var b strings.Builder
+	leading := true
+	for {
+		frame, more := frames.Next()
+		// Drop runtime frames (e.g. runtime.gopanic) above the code that panicked
+		if !(leading && strings.HasPrefix(frame.Function, "runtime.")) {
+			leading = false
+			fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type panicPayload struct {
+	Code   int    `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func panicWithPayload() {
+	panic(panicPayload{Code: 42, Reason: "bad state"})
+}
+
+func TestLoggerRecover_StructuredFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	func() {
+		defer logger.Recover()
+		panicWithPayload()
+	}()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+
+	if entry["level"] != "ERROR" || entry["msg"] != "panic recovered" {
+		t.Errorf("Unexpected level/message: %v", entry)
+	}
+	if got := entry[PanicTypeKey]; got != "logger.panicPayload" {
+		t.Errorf("Expected panic_type logger.panicPayload, got %v", got)
+	}
+	value, ok := entry[PanicValueKey].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected panic_value to be a structured object, got %T: %v", entry[PanicValueKey], entry[PanicValueKey])
+	}
+	if value["code"] != float64(42) || value["reason"] != "bad state" {
+		t.Errorf("Unexpected panic_value fields: %v", value)
+	}
+
+	stack, _ := entry[StackKey].(string)
+	if !strings.HasPrefix(stack, "github.com/simp-lee/logger.panicWithPayload") {
+		t.Errorf("Expected stack to start at the panicking function, got %q", stack)
+	}
+	if !strings.Contains(stack, "recover_test.go:") {
+		t.Errorf("Expected stack to contain file and line, got %q", stack)
+	}
+}
+
+func TestLoggerRecover_Source(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &Logger{Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}))}
+
+	func() {
+		defer logger.Recover()
+		panicWithPayload()
+	}()
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", buf.String(), err)
+	}
+	source, _ := entry[slog.SourceKey].(map[string]any)
+	if file, _ := source["file"].(string); filepath.Base(file) != "recover_test.go" {
+		t.Errorf("Expected the source in recover_test.go, got %v", source)
+	}
+	if fn, _ := source["function"].(string); !strings.HasSuffix(fn, ".panicWithPayload") {
+		t.Errorf("Expected the panicking function as the source, got %v", source)
+	}
+}
+
+func TestLoggerRecover_NoPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &Logger{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+
+	func() {
+		defer logger.Recover()
+	}()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output without a panic, got %q", buf.String())
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// registry holds named loggers shared across the process
+var registry = struct {
+	mu      sync.RWMutex
+	loggers map[string]*Logger
+}{
+	loggers: make(map[string]*Logger),
+}
+
+// Register stores the logger under the given name, replacing any previous registration
+func Register(name string, l *Logger) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.loggers[name] = l
+}
+
+// Get returns the logger registered under the given name
+func Get(name string) (*Logger, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	l, ok := registry.loggers[name]
+	return l, ok
+}
+
+// CloseAll closes every registered logger and clears the registry
+// It is intended for process shutdown, e.g. defer logger.CloseAll() in main
+func CloseAll() error {
+	registry.mu.Lock()
+	loggers := registry.loggers
+	registry.loggers = make(map[string]*Logger)
+	registry.mu.Unlock()
+
+	var errs []error
+	for _, l := range loggers {
+		if err := l.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Levels returns the current level of every registered logger, keyed by name.
+// Loggers not created by New (e.g. Default()) have no adjustable level and are omitted.
+func Levels() map[string]slog.Level {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	levels := make(map[string]slog.Level, len(registry.loggers))
+	for name, l := range registry.loggers {
+		if l.levelVar != nil {
+			levels[name] = l.levelVar.Level()
+		}
+	}
+	return levels
+}
+
+// SetNamedLevel changes the level of the logger registered under name at runtime.
+// The change applies to every logger derived from it, e.g. via With or WithGroup.
+func SetNamedLevel(name string, level slog.Level) error {
+	registry.mu.RLock()
+	l, ok := registry.loggers[name]
+	registry.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no logger registered as %q", name)
+	}
+	if l.levelVar == nil {
+		return fmt.Errorf("logger %q has no adjustable level", name)
+	}
+	l.levelVar.Set(level)
+	return nil
+}
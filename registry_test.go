@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("Register and Get", func(t *testing.T) {
+		defer CloseAll()
+
+		l, err := New()
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		Register("app", l)
+
+		got, ok := Get("app")
+		if !ok || got != l {
+			t.Errorf("Expected registered logger to be returned")
+		}
+		if _, ok := Get("missing"); ok {
+			t.Errorf("Expected missing logger lookup to fail")
+		}
+	})
+
+	t.Run("CloseAll closes file loggers", func(t *testing.T) {
+		tempDir := t.TempDir()
+		paths := []string{
+			filepath.Join(tempDir, "a.log"),
+			filepath.Join(tempDir, "b.log"),
+		}
+
+		for i, path := range paths {
+			l, err := New(WithConsole(false), WithFilePath(path))
+			if err != nil {
+				t.Fatalf("Failed to create logger: %v", err)
+			}
+			Register(filepath.Base(path), l)
+			l.Info("message", "index", i)
+		}
+
+		if err := CloseAll(); err != nil {
+			t.Fatalf("CloseAll() failed: %v", err)
+		}
+
+		for _, path := range paths {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", path, err)
+			}
+			if !strings.Contains(string(content), "message") {
+				t.Errorf("Expected %s to contain the flushed message, got %q", path, content)
+			}
+		}
+
+		for _, path := range paths {
+			l, ok := Get(filepath.Base(path))
+			if ok {
+				t.Errorf("Expected registry to be cleared after CloseAll")
+			}
+			if l != nil {
+				t.Errorf("Expected nil logger after CloseAll")
+			}
+		}
+	})
+
+	t.Run("CloseAll on empty registry", func(t *testing.T) {
+		if err := CloseAll(); err != nil {
+			t.Errorf("Expected no error on empty registry, got %v", err)
+		}
+	})
+	t.Run("Levels and SetNamedLevel", func(t *testing.T) {
+		defer CloseAll()
+
+		api, err := New(WithLevel(slog.LevelInfo))
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		db, err := New(WithLevel(slog.LevelWarn))
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		Register("api", api)
+		Register("db", db)
+		Register("default", Default())
+		child := api.With("component", "handler")
+
+		if err := SetNamedLevel("api", slog.LevelDebug); err != nil {
+			t.Fatalf("SetNamedLevel() failed: %v", err)
+		}
+
+		levels := Levels()
+		want := map[string]slog.Level{"api": slog.LevelDebug, "db": slog.LevelWarn}
+		if len(levels) != len(want) || levels["api"] != want["api"] || levels["db"] != want["db"] {
+			t.Errorf("Expected levels %v, got %v", want, levels)
+		}
+		if !api.Enabled(context.Background(), slog.LevelDebug) || !child.Enabled(context.Background(), slog.LevelDebug) {
+			t.Error("Expected api and loggers derived from it to enable Debug")
+		}
+		if db.Enabled(context.Background(), slog.LevelInfo) {
+			t.Error("Expected db level to be unchanged")
+		}
+
+		if err := SetNamedLevel("missing", slog.LevelDebug); err == nil {
+			t.Error("Expected an error for an unregistered name")
+		}
+		if err := SetNamedLevel("default", slog.LevelDebug); err == nil {
+			t.Error("Expected an error for a logger without an adjustable level")
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SuppressedRepeated is the suppression report reason for records dropped by WithSampling
+const SuppressedRepeated = "repeated"
+
+// RepeatedMessageKey is the attribute of a sampling summary holding the dropped records' message
+const RepeatedMessageKey = "repeated_message"
+
+// SamplingConfig caps how often the same record can be logged, see WithSampling
+type SamplingConfig struct {
+	Thereafter int           // Records of each level and message kept per Tick, later ones are dropped
+	Tick       time.Duration // Length of the sampling window
+}
+
+// repeatKey identifies records that count as duplicates
+type repeatKey struct {
+	level slog.Level
+	msg   string
+}
+
+// repeatWindow counts the records of one key in the current window
+type repeatWindow struct {
+	start   time.Time
+	count   int
+	dropped int
+}
+
+// repeatSampler drops records repeating the same level and message more than thereafter
+// times per tick, and logs how many were dropped once the window is over. A background
+// sweep emits the summaries of windows that ended, so a burst that stops is still reported.
+type repeatSampler struct {
+	thereafter int
+	tick       time.Duration
+	now        func() time.Time // clock for windows, replaceable in tests
+	suppressed *suppressionStats
+
+	mu      sync.Mutex
+	windows map[repeatKey]*repeatWindow
+	handler slog.Handler // Receives the summaries, set by wrap
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newRepeatSampler creates a sampler; call wrap to insert it and Close to stop its sweep
+func newRepeatSampler(sc SamplingConfig, suppressed *suppressionStats) *repeatSampler {
+	return &repeatSampler{
+		thereafter: sc.Thereafter,
+		tick:       sc.Tick,
+		now:        time.Now,
+		suppressed: suppressed,
+		windows:    make(map[repeatKey]*repeatWindow),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// wrap returns handler decorated with the sampler and starts the sweep
+func (s *repeatSampler) wrap(handler slog.Handler) slog.Handler {
+	s.handler = handler
+	go s.run()
+	return &repeatSamplingHandler{handler: handler, sampler: s}
+}
+
+func (s *repeatSampler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep(false)
+		case <-s.stop:
+			// Report what was dropped in the current windows rather than losing it
+			s.sweep(true)
+			return
+		}
+	}
+}
+
+// keep reports whether a record passes sampling, emitting the summary of the key's
+// previous window when a new one starts
+func (s *repeatSampler) keep(level slog.Level, msg string) bool {
+	key := repeatKey{level: level, msg: msg}
+	now := s.now()
+
+	s.mu.Lock()
+	w := s.windows[key]
+	if w == nil || now.Sub(w.start) >= s.tick {
+		s.windows[key] = &repeatWindow{start: now, count: 1}
+		s.mu.Unlock()
+		if w != nil {
+			s.summarize(key, w.dropped)
+		}
+		return true
+	}
+	w.count++
+	if w.count <= s.thereafter {
+		s.mu.Unlock()
+		return true
+	}
+	w.dropped++
+	s.mu.Unlock()
+	return false
+}
+
+// sweep forgets the windows that ended, or all of them, and emits their summaries
+func (s *repeatSampler) sweep(all bool) {
+	now := s.now()
+	dropped := make(map[repeatKey]int)
+
+	s.mu.Lock()
+	for key, w := range s.windows {
+		if all || now.Sub(w.start) >= s.tick {
+			dropped[key] = w.dropped
+			delete(s.windows, key)
+		}
+	}
+	s.mu.Unlock()
+
+	for key, n := range dropped {
+		s.summarize(key, n)
+	}
+}
+
+// summarize logs a single record for the n records of key dropped in one window
+func (s *repeatSampler) summarize(key repeatKey, n int) {
+	if n == 0 {
+		return
+	}
+	ctx := context.Background()
+	if !s.handler.Enabled(ctx, key.level) {
+		return
+	}
+	r := slog.NewRecord(s.now(), key.level, fmt.Sprintf("dropped %d messages", n), 0)
+	r.AddAttrs(
+		slog.String(RepeatedMessageKey, key.msg),
+		slog.Int("dropped", n),
+		slog.Duration("tick", s.tick),
+	)
+	_ = s.handler.Handle(ctx, r)
+}
+
+// Close stops the sweep, emitting the summaries of the current windows, and waits for it to exit
+func (s *repeatSampler) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	<-s.done
+	return nil
+}
+
+// repeatSamplingHandler is a slog.Handler that drops records rejected by a repeatSampler
+type repeatSamplingHandler struct {
+	handler slog.Handler
+	sampler *repeatSampler // Shared by handlers derived via WithAttrs/WithGroup
+}
+
+// Enabled implements slog.Handler
+func (h *repeatSamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *repeatSamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.keep(r.Level, r.Message) {
+		h.sampler.suppressed.add(SuppressedRepeated)
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *repeatSamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &repeatSamplingHandler{handler: h.handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup implements slog.Handler
+func (h *repeatSamplingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &repeatSamplingHandler{handler: h.handler.WithGroup(name), sampler: h.sampler}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// decodeJSONLines decodes every JSON record written to buf
+func decodeJSONLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestWithSampling(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(
+		WithConsole(false),
+		WithWriter(&buf, FormatJSON),
+		WithSampling(SamplingConfig{Thereafter: 10, Tick: time.Hour}),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		log.Info("hot loop", "i", i)
+	}
+	log.Info("other message")
+	log.Warn("hot loop") // Another level is another key
+
+	if n := len(decodeJSONLines(t, &buf)); n != 12 {
+		t.Fatalf("Expected 12 records to get through before Close, got %d", n)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	entries := decodeJSONLines(t, &buf)
+	if len(entries) != 13 {
+		t.Fatalf("Expected a single summary on Close, got %d records", len(entries))
+	}
+	summary := entries[12]
+	if summary["msg"] != "dropped 990 messages" || summary["level"] != "INFO" ||
+		summary[RepeatedMessageKey] != "hot loop" || summary["dropped"] != float64(990) {
+		t.Errorf("Unexpected summary: %v", summary)
+	}
+}
+
+func TestRepeatSampler_WindowRollover(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	capture := NewCaptureHandler(slog.LevelDebug)
+	sampler := newRepeatSampler(SamplingConfig{Thereafter: 2, Tick: time.Hour}, nil)
+	sampler.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	logger := slog.New(sampler.wrap(capture))
+	defer sampler.Close()
+
+	for i := 0; i < 5; i++ {
+		logger.Error("disk full")
+	}
+	mu.Lock()
+	now = now.Add(time.Hour)
+	mu.Unlock()
+	logger.Error("disk full") // Starts a new window, reporting the previous one
+
+	var messages []string
+	for _, r := range capture.Records() {
+		messages = append(messages, r.Message)
+	}
+	want := "disk full,disk full,dropped 3 messages,disk full"
+	if got := strings.Join(messages, ","); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestRepeatSampler_CountsSuppressed(t *testing.T) {
+	stats := &suppressionStats{}
+	sampler := newRepeatSampler(SamplingConfig{Thereafter: 1, Tick: time.Hour}, stats)
+	logger := slog.New(sampler.wrap(&mockHandler{enabled: true}))
+	for i := 0; i < 4; i++ {
+		logger.Info("same")
+	}
+	sampler.Close()
+	if got := stats.swap()[SuppressedRepeated]; got != 3 {
+		t.Errorf("Expected 3 repeated records counted, got %d", got)
+	}
+}
+
+func TestWithSampling_Validation(t *testing.T) {
+	for _, sc := range []SamplingConfig{{Thereafter: -1, Tick: time.Second}, {Thereafter: 5}} {
+		cfg := DefaultConfig()
+		WithSampling(sc)(cfg)
+		if err := validateConfig(cfg); err == nil {
+			t.Errorf("Expected validation error for %+v", sc)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// metaSuffix is appended to a rotated file's name for the sidecar recording when it was closed
+const metaSuffix = ".meta"
+
+// writeCloseTime records closedAt as the close time of the rotated file at path in its sidecar.
+// Retention then ages the file by that time rather than by its modification time, which
+// backup or sync tools may touch. The sidecar is created with the log files' mode.
+func writeCloseTime(path string, closedAt time.Time, mode os.FileMode) error {
+	data := closedAt.UTC().AppendFormat(nil, time.RFC3339Nano)
+	if err := os.WriteFile(path+metaSuffix, append(data, '\n'), mode); err != nil {
+		return fmt.Errorf("failed to write retention metadata: %w", err)
+	}
+	return nil
+}
+
+// readCloseTime returns the close time recorded in the sidecar of the rotated file at path,
+// or false if there is no readable sidecar
+func readCloseTime(path string) (time.Time, bool) {
+	data, err := os.ReadFile(path + metaSuffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+	closedAt, err := time.Parse(time.RFC3339Nano, string(bytes.TrimSpace(data)))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return closedAt, true
+}
+
+// removeRotated removes a rotated file together with its sidecar, if any
+func removeRotated(path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := os.Remove(path + metaSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// sidecarOf returns the rotated file name a sidecar belongs to, if name is a sidecar
+func sidecarOf(name string) (string, bool) {
+	return strings.CutSuffix(name, metaSuffix)
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// rotateOnce writes a line and rotates w, returning the rotated file's path
+func rotateOnce(t *testing.T, w *rotatingWriter) string {
+	t.Helper()
+	if _, err := w.Write([]byte("line\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	event, err := w.rotate()
+	if err != nil || event == nil {
+		t.Fatalf("rotate() failed: %v", err)
+	}
+	return event.oldFile
+}
+
+func TestReliableRetention(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "app.log",
+		maxAge:        time.Hour,
+		closeTimeMeta: true,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	expired := rotateOnce(t, w)
+	w.now = func() time.Time { return time.Now().Add(time.Second) } // distinct rotated name
+	recent := rotateOnce(t, w)
+
+	if closedAt, ok := readCloseTime(expired); !ok || time.Since(closedAt) > time.Minute {
+		t.Fatalf("Expected a sidecar with the rotation time, got %v (found %v)", closedAt, ok)
+	}
+
+	// A backup tool touched the expired file; its sidecar still says it was closed 2h ago
+	if err := writeCloseTime(expired, time.Now().Add(-2*time.Hour), DefaultFileMode); err != nil {
+		t.Fatalf("writeCloseTime failed: %v", err)
+	}
+	now := time.Now()
+	if err := os.Chtimes(expired, now, now); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	// The recent file merely looks old
+	old := now.Add(-2 * time.Hour)
+	if err := os.Chtimes(recent, old, old); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	w.cleanOldLogs(context.Background())
+
+	for _, path := range []string{expired, expired + metaSuffix} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed by its recorded close time", filepath.Base(path))
+		}
+	}
+	for _, path := range []string{recent, recent + metaSuffix} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be kept by its recorded close time: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestReliableRetention_OrphanedSidecarRemoved(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "app.log",
+		retentionDays: 7,
+		closeTimeMeta: true,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	rotated := rotateOnce(t, w)
+	if err := os.Remove(rotated); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	// A user's file that merely ends in .meta is left alone
+	unrelated := filepath.Join(tempDir, "notes.meta")
+	if err := os.WriteFile(unrelated, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	w.cleanOldLogs(context.Background())
+
+	if _, err := os.Stat(rotated + metaSuffix); !os.IsNotExist(err) {
+		t.Error("Expected the sidecar of a deleted file to be removed")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("Expected unrelated .meta file to be kept: %v", err)
+	}
+}
+
+func TestWithReliableRetention(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(WithConsole(false), WithFilePath(logPath), WithReliableRetention(true))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer l.Close()
+	if !l.fileWriter.config.closeTimeMeta {
+		t.Error("Expected the file writer to record close times")
+	}
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rotatedTimestampLayout is the timestamp layout used in rotated file names
+const rotatedTimestampLayout = "20060102.150405.000"
+
+// RotatedFileInfo describes a rotated log file
+type RotatedFileInfo struct {
+	Path       string    // Full path of the rotated file
+	Start      time.Time // End of the previous rotated file, zero for the oldest one
+	End        time.Time // Rotation time, parsed from the file name
+	ModTime    time.Time // Last modification time
+	Size       int64     // Size in bytes (compressed size for compressed files)
+	Compressed bool      // The file is compressed (.gz or the extension of a WithCompressor codec)
+}
+
+// RotatedFiles lists the rotated files of the logger's log file, oldest first.
+// Files are matched against the rotation naming scheme, name.YYYYMMDD.HHMMSS.mmm[.N].ext[.gz],
+// where .gz may also be the extension of any codec registered with WithCompressor,
+// so unrelated files in the directory are ignored.
+func (l *Logger) RotatedFiles() ([]RotatedFileInfo, error) {
+	if l.filePath == "" {
+		return nil, fmt.Errorf("file logging is not enabled")
+	}
+	if l.fileWriter != nil {
+		return listRotatedFiles(l.fileWriter.path()) // follows SetFilePath
+	}
+	return listRotatedFiles(l.filePath)
+}
+
+// rotatedFilePattern matches rotated names of fileName, capturing the timestamp,
+// the optional collision counter and the optional compression suffix
+func rotatedFilePattern(fileName string) *regexp.Regexp {
+	ext := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+	exts := compressedExts()
+	for i, e := range exts {
+		exts[i] = regexp.QuoteMeta(e)
+	}
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(base) +
+		`\.(\d{8}\.\d{6}\.\d{3})(?:\.(\d+))?` + regexp.QuoteMeta(ext) + `(` + strings.Join(exts, "|") + `)?$`)
+}
+
+// listRotatedFiles scans the directory of path for its rotated files
+func listRotatedFiles(path string) ([]RotatedFileInfo, error) {
+	directory := filepath.Dir(path)
+	pattern := rotatedFilePattern(filepath.Base(path))
+
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	type rotatedFile struct {
+		info    RotatedFileInfo
+		counter int
+	}
+	var files []rotatedFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := pattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+		end, err := time.ParseInLocation(rotatedTimestampLayout, m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Removed between listing and stat, e.g. by retention cleanup
+			continue
+		}
+		counter, _ := strconv.Atoi(m[2])
+		files = append(files, rotatedFile{
+			info: RotatedFileInfo{
+				Path:       filepath.Join(directory, entry.Name()),
+				End:        end,
+				ModTime:    info.ModTime(),
+				Size:       info.Size(),
+				Compressed: m[3] != "",
+			},
+			counter: counter,
+		})
+	}
+
+	// Oldest first; collisions within the same millisecond keep their counter order
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].info.End.Equal(files[j].info.End) {
+			return files[i].info.End.Before(files[j].info.End)
+		}
+		return files[i].counter < files[j].counter
+	})
+
+	result := make([]RotatedFileInfo, len(files))
+	for i, f := range files {
+		result[i] = f.info
+		if i > 0 {
+			result[i].Start = result[i-1].End
+		}
+	}
+	return result, nil
+}
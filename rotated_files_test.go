@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "app.log")
+
+	log, err := New(WithConsole(false), WithFilePath(logPath))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	files := map[string]string{
+		"app.20240102.030405.006.log":       "second",
+		"app.20240101.120000.000.log":       "first",
+		"app.20240102.030405.006.1.log":     "collision",
+		"app.20240103.000000.000.log.gz":    "gz",
+		"app.log":                           "current",
+		"app.20240103.log":                  "not a rotated name",
+		"other.20240101.120000.000.log":     "other log",
+		"app.20240101.120000.000.txt":       "wrong extension",
+		"app-extra.20240101.120000.000.log": "different base",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	rotated, err := log.RotatedFiles()
+	if err != nil {
+		t.Fatalf("RotatedFiles() failed: %v", err)
+	}
+
+	want := []struct {
+		name       string
+		end        time.Time
+		compressed bool
+	}{
+		{"app.20240101.120000.000.log", time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local), false},
+		{"app.20240102.030405.006.log", time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.Local), false},
+		{"app.20240102.030405.006.1.log", time.Date(2024, 1, 2, 3, 4, 5, 6e6, time.Local), false},
+		{"app.20240103.000000.000.log.gz", time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local), true},
+	}
+	if len(rotated) != len(want) {
+		t.Fatalf("Expected %d rotated files, got %d: %+v", len(want), len(rotated), rotated)
+	}
+	for i, w := range want {
+		got := rotated[i]
+		if got.Path != filepath.Join(dir, w.name) {
+			t.Errorf("File %d: expected path %s, got %s", i, w.name, got.Path)
+		}
+		if !got.End.Equal(w.end) {
+			t.Errorf("File %d: expected end %v, got %v", i, w.end, got.End)
+		}
+		if got.Compressed != w.compressed {
+			t.Errorf("File %d: expected compressed=%v", i, w.compressed)
+		}
+		if got.Size != int64(len(files[w.name])) {
+			t.Errorf("File %d: expected size %d, got %d", i, len(files[w.name]), got.Size)
+		}
+		if got.ModTime.IsZero() {
+			t.Errorf("File %d: expected a modification time", i)
+		}
+		if i == 0 && !got.Start.IsZero() {
+			t.Errorf("Expected zero start for the oldest file, got %v", got.Start)
+		}
+		if i > 0 && !got.Start.Equal(rotated[i-1].End) {
+			t.Errorf("File %d: expected start %v, got %v", i, rotated[i-1].End, got.Start)
+		}
+	}
+}
+
+func TestRotatedFiles_NoFileLogging(t *testing.T) {
+	log, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	if _, err := log.RotatedFiles(); err == nil {
+		t.Error("Expected error without file logging")
+	}
+}
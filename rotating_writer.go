@@ -2,11 +2,16 @@ package logger
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -14,10 +19,37 @@ import (
 
 // rotatingConfig defines parameters for log file rotation
 type rotatingConfig struct {
-	directory     string // Directory to store log files
-	fileName      string // Base name of the log file
-	maxSizeMB     int    // Maximum size in MB before rotation
-	retentionDays int    // Number of days to keep log files
+	directory      string        // Directory to store log files
+	fileName       string        // Base name of the log file
+	maxSizeMB      int           // Maximum size in MB before rotation
+	retentionDays  int           // Number of days to keep log files
+	maxAge         time.Duration // Retention as a duration, overrides retentionDays when positive
+	maxTotalSizeMB int           // Cap on the combined size of rotated files, 0 disables it
+	rotateInterval time.Duration // Also rotate at every multiple of this interval since midnight, 0 disables
+
+	onClosedWrite   func(p []byte)    // Receives writes attempted after Close, nil returns an error instead
+	atomicRecords   bool              // Hold an advisory file lock around each write for multi-process appends
+	unbuffered      bool              // Write straight to the file without a bufio.Writer
+	bufferSize      int               // Capacity of the bufio.Writer
+	flushInterval   time.Duration     // Flush the buffer periodically instead of after every write, 0 disables
+	rotationMarkers bool              // Write a sentinel line at the end of each rotated file and the top of the next
+	jsonMarkers     bool              // Write rotation markers as JSON objects, for JSON-line files
+	eventLogger     *slog.Logger      // Receives a record for each rotation, nil disables rotation events
+	onRotated       func(path string) // Called outside the lock with each rotated file's path
+	compressor      *compressor       // Compresses each rotated file, nil keeps them as is
+	closeTimeMeta   bool              // Record each rotated file's close time in a .meta sidecar used by retention
+	onError         func(err error)   // Receives background flush and rotation failures, nil logs a warning
+	fsync           bool              // Sync the file to stable storage after each flush
+	fileMode        os.FileMode       // Permissions of created files, 0 uses DefaultFileMode
+	dirMode         os.FileMode       // Permissions of a created directory, 0 uses DefaultDirMode
+}
+
+// rotationEvent describes a completed rotation
+type rotationEvent struct {
+	oldFile string    // Path of the rotated (renamed) file
+	newFile string    // Path of the fresh current file
+	size    int64     // Size of the file at rotation time
+	at      time.Time // Rotation time, when the file was closed
 }
 
 // rotatingWriter handles log file rotation and writing.
@@ -26,36 +58,105 @@ type rotatingWriter struct {
 	mutex        sync.Mutex
 	rotateSignal chan struct{}
 	cleanupTimer *time.Timer
+	rotateTimer  *time.Timer   // Fires interval rotations, nil when rotateInterval is 0
+	flushStop    chan struct{} // Stops the periodic flush loop, nil when flushInterval is 0
+	flushDone    chan struct{}
 	closed       bool // flag to track if the writer is closed
 	file         *os.File
 	buf          *bufio.Writer
 	currentSize  int64 // bytes written to current file (including buffered)
+
+	// Scheduled cleanups in flight; Close cancels and waits for them
+	cleanupWG     sync.WaitGroup
+	cleanupCtx    context.Context
+	cleanupCancel context.CancelFunc
+
+	now    func() time.Time                    // clock used for rotated file names, replaceable in tests
+	sync   func(f *os.File) error              // commits a file to stable storage, replaceable in tests
+	rename func(oldPath, newPath string) error // renames the file on rotation, replaceable in tests
 }
 
 // newRotatingWriter creates a new rotatingWriter instance.
 func newRotatingWriter(cfg *rotatingConfig) (*rotatingWriter, error) {
 	w := &rotatingWriter{
-		config:       cfg,
-		rotateSignal: make(chan struct{}, 1),
+		config: cfg,
+		now:    time.Now,
+		sync:   (*os.File).Sync,
+		rename: os.Rename,
 	}
 	// NOTE: we intentionally do NOT open the file here to avoid
 	// keeping descriptors open for handlers that are constructed
 	// but never used in tests (some tests create a handler and never write).
 	// The file is opened lazily on first Write or after rotation.
 
-	// Start the rotation monitor
-	go w.rotateMonitor()
+	// Start the rotation monitor only when rotation is enabled;
+	// append-only logging (no size limit or interval) needs neither the channel nor the goroutine
+	if cfg.maxSizeMB > 0 || cfg.rotateInterval > 0 {
+		w.rotateSignal = make(chan struct{}, 1)
+		var intervalC <-chan time.Time
+		if cfg.rotateInterval > 0 {
+			w.rotateTimer = time.NewTimer(timeUntilNextInterval(w.now(), cfg.rotateInterval))
+			intervalC = w.rotateTimer.C
+		}
+		go w.rotateMonitor(intervalC)
+	}
 
-	// Set up the cleanup timer to run once a day
-	w.cleanupTimer = time.AfterFunc(timeUntilNextDay(), func() {
-		w.cleanOldLogs(context.Background())
-		// Reschedule the cleanup every 24 hours
-		w.cleanupTimer.Reset(time.Hour * 24)
-	})
+	// With async writes the buffer is only flushed when full, on rotation, on Close and by this loop
+	if cfg.flushInterval > 0 && !cfg.unbuffered {
+		w.flushStop = make(chan struct{})
+		w.flushDone = make(chan struct{})
+		go w.flushLoop(cfg.flushInterval)
+	}
+
+	// Set up the cleanup timer to run once a day, or every maxAge when that is shorter
+	w.cleanupCtx, w.cleanupCancel = context.WithCancel(context.Background())
+	w.mutex.Lock()
+	w.cleanupTimer = time.AfterFunc(min(timeUntilNextDay(), w.cleanupInterval()), w.runScheduledCleanup)
+	w.mutex.Unlock()
 
 	return w, nil
 }
 
+// runScheduledCleanup runs a timer-triggered cleanup and reschedules the next one.
+func (w *rotatingWriter) runScheduledCleanup() {
+	if !w.runCleanup() {
+		return
+	}
+
+	// Reschedule the next cleanup, unless closed meanwhile
+	w.mutex.Lock()
+	if !w.closed {
+		w.cleanupTimer.Reset(w.cleanupInterval())
+	}
+	w.mutex.Unlock()
+}
+
+// cleanupInterval returns the time between scheduled cleanups: a day, or maxAge when it is
+// shorter, so files outlive a sub-day max age by at most that age again
+func (w *rotatingWriter) cleanupInterval() time.Duration {
+	if w.config.maxAge > 0 && w.config.maxAge < 24*time.Hour {
+		return w.config.maxAge
+	}
+	return 24 * time.Hour
+}
+
+// runCleanup runs cleanOldLogs unless the writer is closed, reporting whether it ran.
+// It registers with cleanupWG under the mutex, so Close either prevents it from
+// starting or waits for it to finish.
+func (w *rotatingWriter) runCleanup() bool {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return false
+	}
+	w.cleanupWG.Add(1)
+	w.mutex.Unlock()
+	defer w.cleanupWG.Done()
+
+	w.cleanOldLogs(w.cleanupCtx)
+	return true
+}
+
 // timeUntilNextDay returns the duration until the next day.
 func timeUntilNextDay() time.Duration {
 	now := time.Now()
@@ -64,12 +165,100 @@ func timeUntilNextDay() time.Duration {
 	return next.Sub(now)
 }
 
-// rotateMonitor listens for rotation signals and performs log rotation.
-func (w *rotatingWriter) rotateMonitor() {
-	for range w.rotateSignal {
-		if err := w.rotate(); err != nil {
-			// Log the error, but continue operating
-			slog.Warn("Error during log rotation", slog.Any("error", err))
+// timeUntilNextInterval returns the duration until the next multiple of interval since
+// local midnight, so an hourly interval rotates at the top of every hour.
+// Intervals longer than a day simply run from now.
+func timeUntilNextInterval(now time.Time, interval time.Duration) time.Duration {
+	if interval > 24*time.Hour {
+		return interval
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add((now.Sub(midnight)/interval + 1) * interval)
+	if nextMidnight := midnight.AddDate(0, 0, 1); next.After(nextMidnight) {
+		next = nextMidnight // intervals that don't divide the day restart at midnight
+	}
+	return next.Sub(now)
+}
+
+// rotateMonitor listens for size rotation signals and interval ticks and performs log rotation.
+// It exits when Close closes rotateSignal.
+func (w *rotatingWriter) rotateMonitor(intervalC <-chan time.Time) {
+	for {
+		select {
+		case _, ok := <-w.rotateSignal:
+			if !ok {
+				return
+			}
+		case <-intervalC:
+			// Don't produce empty rotated files when nothing was logged during the interval
+			w.mutex.Lock()
+			empty := w.currentSize == 0
+			w.mutex.Unlock()
+			if empty {
+				w.resetRotateTimer()
+				continue
+			}
+		}
+
+		event, err := w.rotate()
+		// Whatever triggered the rotation, the next interval rotation is rescheduled
+		w.resetRotateTimer()
+		if err != nil {
+			// Report the error, but continue operating (if the file was rotated, event is set)
+			w.reportError("Error during log rotation", err)
+		}
+		// A size budget must hold between the daily cleanups, so enforce it after every rotation
+		if event != nil && w.config.maxTotalSizeMB > 0 {
+			w.runCleanup()
+		}
+		// Emitted after the mutex is released and never through this writer, to avoid circular logging
+		if event != nil && w.config.eventLogger != nil {
+			w.config.eventLogger.Info("Log file rotated",
+				slog.String("old_file", event.oldFile),
+				slog.String("new_file", event.newFile),
+				slog.Int64("size", event.size),
+			)
+		}
+	}
+}
+
+// resetRotateTimer schedules the next interval rotation, unless the writer is closed
+func (w *rotatingWriter) resetRotateTimer() {
+	if w.rotateTimer == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.closed {
+		w.rotateTimer.Reset(timeUntilNextInterval(w.now(), w.config.rotateInterval))
+	}
+}
+
+// reportError hands a background failure, which no caller of Write sees, to onError,
+// or logs it as a warning with msg
+func (w *rotatingWriter) reportError(msg string, err error) {
+	if w.config.onError != nil {
+		w.config.onError(err)
+		return
+	}
+	slog.Warn(msg, slog.Any("error", err))
+}
+
+// flushLoop flushes the buffer every interval until flushStop is closed
+func (w *rotatingWriter) flushLoop(interval time.Duration) {
+	defer close(w.flushDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				w.reportError("Error flushing log file", err)
+			}
+		case <-w.flushStop:
+			return
 		}
 	}
 }
@@ -77,26 +266,39 @@ func (w *rotatingWriter) rotateMonitor() {
 // Write implements io.Writer interface for rotatingWriter.
 func (w *rotatingWriter) Write(p []byte) (n int, err error) {
 	w.mutex.Lock()
-	defer w.mutex.Unlock()
 
 	// Check if the writer has been closed to avoid panic on closed channel
 	if w.closed {
+		w.mutex.Unlock()
+		// Hand the lost bytes to the callback outside the lock, so it may safely log elsewhere
+		if w.config.onClosedWrite != nil {
+			w.config.onClosedWrite(bytes.Clone(p))
+			return len(p), nil
+		}
 		return 0, fmt.Errorf("writer has been closed")
 	}
-	if w.file == nil || w.buf == nil { // should not happen, but be defensive
+	defer w.mutex.Unlock()
+	// Nothing to write: avoid opening the file or flushing for an empty buffer
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if w.file == nil || (w.buf == nil && !w.config.unbuffered) { // should not happen, but be defensive
 		if err := w.openCurrentFile(); err != nil {
 			return 0, err
 		}
 	}
 
-	n, err = w.buf.Write(p)
-	if err != nil {
-		return n, fmt.Errorf("failed to write to buffer: %w", err)
-	}
-	// Flush immediately to satisfy tests that read the file right after Write.
-	if err := w.buf.Flush(); err != nil {
-		return n, fmt.Errorf("failed to flush buffer: %w", err)
+	if w.config.atomicRecords {
+		// Other processes appending to the same file take the same lock,
+		// so a record of any size lands in one piece
+		if err := lockFile(w.file); err != nil {
+			return 0, fmt.Errorf("failed to lock log file: %w", err)
+		}
+		defer unlockFile(w.file)
 	}
+
+	n, err = w.writeLocked(p)
+	// Counted even when the write failed: a failed fsync leaves the bytes in the file
 	w.currentSize += int64(n)
 
 	// Rotation check (include buffered data)
@@ -106,11 +308,75 @@ func (w *rotatingWriter) Write(p []byte) (n int, err error) {
 		default:
 		}
 	}
-	return n, nil
+	return n, err
 }
 
 // rotate performs log rotation by renaming the current log file.
-func (w *rotatingWriter) rotate() error {
+// It returns a nil event when there was no file to rotate.
+// Only the flush, rename and reopen happen under the mutex; syncing, closing and
+// compressing the rotated file and the onRotated hook run afterwards, so writers are
+// not blocked by them. A failure to sync or close the rotated file is returned along
+// with the event, once the remaining steps are done, as the rotation itself happened.
+func (w *rotatingWriter) rotate() (*rotationEvent, error) {
+	event, oldFile, err := w.swapFile()
+	if oldFile != nil {
+		// Best effort, the data is already flushed, unless durability was asked for
+		if syncErr := w.sync(oldFile); syncErr != nil && w.config.fsync && err == nil {
+			err = fmt.Errorf("failed to sync rotated file: %w", syncErr)
+		}
+		if closeErr := oldFile.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close rotated file: %w", closeErr)
+		}
+	}
+	if event == nil {
+		return nil, err
+	}
+	if w.config.compressor != nil {
+		w.compressRotated(event)
+	}
+	// Written once the rotated file has its final name, i.e. after compression
+	if w.config.closeTimeMeta {
+		if err := writeCloseTime(event.oldFile, event.at, w.fileMode()); err != nil {
+			slog.Warn("Error recording rotated file close time",
+				slog.String("file", event.oldFile),
+				slog.Any("error", err),
+			)
+		}
+	}
+	if w.config.onRotated != nil {
+		w.config.onRotated(event.oldFile)
+	}
+	return event, err
+}
+
+// compressRotated compresses the rotated file of event and points event at the result.
+// Like cleanups it registers with cleanupWG, so Close waits for it; after Close, or when
+// compression fails, the file stays uncompressed.
+func (w *rotatingWriter) compressRotated(event *rotationEvent) {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return
+	}
+	w.cleanupWG.Add(1)
+	w.mutex.Unlock()
+	defer w.cleanupWG.Done()
+
+	compressed, err := w.config.compressor.compressFile(event.oldFile)
+	if compressed != "" {
+		event.oldFile = compressed
+	}
+	if err != nil {
+		slog.Warn("Error compressing rotated log file",
+			slog.String("file", event.oldFile),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// swapFile renames the current log file and opens a fresh one under the mutex.
+// The previous file handle, if still open, is returned for the caller to close.
+func (w *rotatingWriter) swapFile() (event *rotationEvent, oldFile *os.File, err error) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
@@ -118,26 +384,14 @@ func (w *rotatingWriter) rotate() error {
 
 	// Check if the file exists before rotating
 	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
-		return nil
+		return nil, nil, nil
 	} else if err != nil {
-		return fmt.Errorf("failed to check log file: %w", err)
-	}
-
-	// Flush buffered data before rotation
-	if w.buf != nil {
-		_ = w.buf.Flush() // ignore flush error, we'll catch write/open errors later
-	}
-	if w.file != nil {
-		// Close current file before renaming (required on Windows)
-		if err := w.file.Close(); err != nil {
-			return fmt.Errorf("failed to close file before rotation: %w", err)
-		}
-		w.file = nil
-		w.buf = nil
+		return nil, nil, fmt.Errorf("failed to check log file: %w", err)
 	}
 
 	ext := filepath.Ext(w.config.fileName)
-	timestamp := time.Now().Format("20060102.150405.000")
+	rotatedAt := w.now()
+	timestamp := rotatedAt.Format(rotatedTimestampLayout)
 
 	// Generate a unique filename for the rotated log
 	newPath := filepath.Join(w.config.directory, fmt.Sprintf("%s.%s%s",
@@ -147,9 +401,12 @@ func (w *rotatingWriter) rotate() error {
 
 	// Ensure the new path is unique by adding a counter if needed
 	counter := 0
+	// (files from a previous run may already use this timestamp and its .N variants)
 	for {
 		if _, err := os.Stat(newPath); os.IsNotExist(err) {
 			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("failed to check rotated file name: %w", err)
 		}
 		counter++
 		newPath = filepath.Join(w.config.directory, fmt.Sprintf("%s.%s.%d%s",
@@ -159,28 +416,138 @@ func (w *rotatingWriter) rotate() error {
 			ext))
 	}
 
+	// Flush buffered data before rotation
+	if w.buf != nil {
+		_ = w.buf.Flush() // ignore flush error, we'll catch write/open errors later
+	}
+	if w.file != nil {
+		if runtime.GOOS == "windows" {
+			// Close current file before renaming (required on Windows)
+			if err := w.file.Close(); err != nil {
+				return nil, nil, fmt.Errorf("failed to close file before rotation: %w", err)
+			}
+		} else {
+			// Elsewhere the open handle follows the rename and is closed outside the lock
+			oldFile = w.file
+		}
+		w.file = nil
+		w.buf = nil
+	}
+
 	// Rename the current log file
-	if err := os.Rename(oldPath, newPath); err != nil {
-		return fmt.Errorf("failed to rotate log file: %w", err)
+	if err := w.rename(oldPath, newPath); err != nil {
+		return nil, oldFile, fmt.Errorf("failed to rotate log file: %w", err)
 	}
+	// Only once renamed, so a file that failed to rotate doesn't claim it was
+	if w.config.rotationMarkers {
+		w.appendEndMarker(oldFile, newPath, w.rotationMarker(rotatedAt, filepath.Base(oldPath), ""))
+	}
+
+	event = &rotationEvent{oldFile: newPath, newFile: oldPath, size: w.currentSize, at: rotatedAt}
 
 	// Open a new current file
 	if err := w.openCurrentFile(); err != nil {
-		return fmt.Errorf("failed to open new log file after rotation: %w", err)
+		return nil, oldFile, fmt.Errorf("failed to open new log file after rotation: %w", err)
 	}
 	w.currentSize = 0
+	if w.config.rotationMarkers {
+		n, _ := w.writeLocked(w.rotationMarker(rotatedAt, "", filepath.Base(newPath)))
+		w.currentSize = int64(n)
+	}
+	return event, oldFile, nil
+}
+
+// appendEndMarker writes the end marker to the rotated file, through its still open handle or,
+// where it was closed for the rename, by reopening it. Like the flush before the rename, errors
+// are ignored: a missing marker shouldn't stop rotation.
+func (w *rotatingWriter) appendEndMarker(oldFile *os.File, rotatedPath string, marker []byte) {
+	if oldFile != nil {
+		_, _ = oldFile.Write(marker)
+		return
+	}
+	f, err := os.OpenFile(rotatedPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return
+	}
+	_, _ = f.Write(marker)
+	_ = f.Close()
+}
+
+// rotationMarker builds the sentinel line written at the end of a rotated file (continuedIn set)
+// and the top of its successor (continuedFrom set).
+// JSON files get a JSON object so line-oriented JSON parsers keep working.
+func (w *rotatingWriter) rotationMarker(at time.Time, continuedIn, continuedFrom string) []byte {
+	at = at.UTC()
+	if w.config.jsonMarkers {
+		marker, _ := json.Marshal(struct {
+			Time          time.Time `json:"time"`
+			Msg           string    `json:"msg"`
+			ContinuedIn   string    `json:"continued_in,omitempty"`
+			ContinuedFrom string    `json:"continued_from,omitempty"`
+		}{at, "log rotated", continuedIn, continuedFrom})
+		return append(marker, '\n')
+	}
+	if continuedIn != "" {
+		return fmt.Appendf(nil, "--- rotated at %s, continued in %s ---\n", at.Format(time.RFC3339Nano), continuedIn)
+	}
+	return fmt.Appendf(nil, "--- rotated at %s, continued from %s ---\n", at.Format(time.RFC3339Nano), continuedFrom)
+}
+
+// writeLocked writes p to the open file, through the buffer when there is one.
+// The caller must hold the mutex.
+func (w *rotatingWriter) writeLocked(p []byte) (int, error) {
+	if w.buf == nil {
+		n, err := w.file.Write(p)
+		if err != nil {
+			return n, fmt.Errorf("failed to write to log file: %w", err)
+		}
+		return n, w.syncLocked()
+	}
+	n, err := w.buf.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("failed to write to buffer: %w", err)
+	}
+	// Flush immediately so the file can be read right after Write, unless writes are async.
+	// Atomic records always flush, as the file lock only covers what reaches the file.
+	if w.config.flushInterval > 0 && !w.config.atomicRecords {
+		return n, nil
+	}
+	if err := w.buf.Flush(); err != nil {
+		return n, fmt.Errorf("failed to flush buffer: %w", err)
+	}
+	return n, w.syncLocked()
+}
+
+// syncLocked fsyncs the open file when the writer is configured to.
+// The caller must hold the mutex.
+func (w *rotatingWriter) syncLocked() error {
+	if !w.config.fsync || w.file == nil {
+		return nil
+	}
+	if err := w.sync(w.file); err != nil {
+		return fmt.Errorf("failed to sync log file: %w", err)
+	}
 	return nil
 }
 
+// cleanupBatchSize is the number of directory entries read per batch during cleanup
+const cleanupBatchSize = 256
+
 func (w *rotatingWriter) cleanOldLogs(ctx context.Context) {
 	w.mutex.Lock()
 	cutoffTime := time.Now().AddDate(0, 0, -w.config.retentionDays)
+	if w.config.maxAge > 0 {
+		cutoffTime = time.Now().Add(-w.config.maxAge)
+	}
+	maxTotalSize := int64(w.config.maxTotalSizeMB) * 1024 * 1024
 	directory := w.config.directory
 	fileName := w.config.fileName
+	closeTimeMeta := w.config.closeTimeMeta
 	w.mutex.Unlock()
 
-	// Read the log directory without holding the lock
-	entries, err := os.ReadDir(directory)
+	// Stream the log directory in batches without holding the lock,
+	// so directories shared with many unrelated files don't load all entries at once
+	dir, err := os.Open(directory)
 	if err != nil {
 		// Log the error without holding the lock to avoid deadlock
 		slog.Warn("Error reading directory",
@@ -189,9 +556,16 @@ func (w *rotatingWriter) cleanOldLogs(ctx context.Context) {
 		)
 		return
 	}
+	defer dir.Close()
+
+	// Only files named by rotate() are eligible, so e.g. a user's test.log.bak is never deleted
+	rotated := rotatedFilePattern(fileName)
 
 	var removed, retained, skipped int
-	for _, entry := range entries {
+	var kept []rotatedLog         // Files surviving age pruning, only collected for the size cap
+	var sidecars []string         // Sidecar names seen, to remove those whose file is gone
+	seen := make(map[string]bool) // Rotated file names seen
+	for {
 		select {
 		case <-ctx.Done():
 			// Log cleanup cancelled (without holding the lock)
@@ -202,22 +576,20 @@ func (w *rotatingWriter) cleanOldLogs(ctx context.Context) {
 			)
 			return
 		default:
-			if entry.IsDir() {
-				skipped++
-				continue
-			}
-
-			// Skip files that don't match the log file name
-			isRotatedLog := strings.HasPrefix(
-				entry.Name(),
-				strings.TrimSuffix(fileName, filepath.Ext(fileName)),
-			) &&
-				entry.Name() != fileName
+		}
 
-			if !isRotatedLog {
-				skipped++
+		entries, err := dir.ReadDir(cleanupBatchSize)
+		for _, entry := range entries {
+			// Cheap name-based filtering first; only matching files are stat'ed
+			if entry.IsDir() || !rotated.MatchString(entry.Name()) {
+				if owner, ok := sidecarOf(entry.Name()); ok && !entry.IsDir() && rotated.MatchString(owner) {
+					sidecars = append(sidecars, entry.Name())
+				} else {
+					skipped++
+				}
 				continue
 			}
+			seen[entry.Name()] = true
 
 			info, err := entry.Info()
 			if err != nil {
@@ -229,9 +601,17 @@ func (w *rotatingWriter) cleanOldLogs(ctx context.Context) {
 				continue
 			}
 
+			// Age by the recorded close time when there is one, else by the modification time
+			age := info.ModTime()
+			if closeTimeMeta {
+				if closedAt, ok := readCloseTime(filepath.Join(directory, entry.Name())); ok {
+					age = closedAt
+				}
+			}
+
 			// Remove files older than the cutoff time
-			if info.ModTime().Before(cutoffTime) {
-				if err := os.Remove(filepath.Join(directory, entry.Name())); err != nil {
+			if age.Before(cutoffTime) {
+				if err := removeRotated(filepath.Join(directory, entry.Name())); err != nil {
 					// Log the error without holding the lock to avoid deadlock
 					slog.Warn("Error removing old log file",
 						"file", entry.Name(),
@@ -242,8 +622,41 @@ func (w *rotatingWriter) cleanOldLogs(ctx context.Context) {
 				}
 			} else {
 				retained++
+				if maxTotalSize > 0 {
+					kept = append(kept, rotatedLog{name: entry.Name(), modTime: age, size: info.Size()})
+				}
 			}
 		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			slog.Warn("Error reading directory",
+				slog.String("directory", directory),
+				slog.Any("error", err),
+			)
+			return
+		}
+	}
+
+	// Sidecars of files deleted by other means are useless; the listing is not a snapshot,
+	// so check a file that wasn't seen is really gone
+	for _, name := range sidecars {
+		owner, _ := sidecarOf(name)
+		if seen[owner] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(directory, owner)); os.IsNotExist(err) {
+			_ = os.Remove(filepath.Join(directory, name))
+		}
+	}
+
+	// Age takes priority; then remove the oldest files until the rest fit the size budget
+	if maxTotalSize > 0 {
+		n := removeOverBudget(directory, kept, maxTotalSize)
+		removed += n
+		retained -= n
 	}
 
 	// Log the cleanup results without holding the lock
@@ -254,13 +667,111 @@ func (w *rotatingWriter) cleanOldLogs(ctx context.Context) {
 	)
 }
 
-// Close stops the cleanup timer and closes the rotatingWriter.
-func (w *rotatingWriter) Close() error {
+// rotatedLog is a rotated file considered by the total size cap
+type rotatedLog struct {
+	name    string
+	modTime time.Time // Close time from the sidecar when recorded
+	size    int64
+}
+
+// removeOverBudget deletes the oldest of files until their total size is within maxTotalSize
+// and returns how many were removed. The current log file is never among files.
+func removeOverBudget(directory string, files []rotatedLog, maxTotalSize int64) int {
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	if total <= maxTotalSize {
+		return 0
+	}
+
+	// Oldest first; names embed the rotation time, so they break modification time ties
+	slices.SortFunc(files, func(a, b rotatedLog) int {
+		if c := a.modTime.Compare(b.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	removed := 0
+	for _, f := range files {
+		if total <= maxTotalSize {
+			break
+		}
+		if err := removeRotated(filepath.Join(directory, f.name)); err != nil {
+			slog.Warn("Error removing log file over the size budget",
+				"file", f.name,
+				slog.Any("error", err),
+			)
+			continue
+		}
+		total -= f.size
+		removed++
+	}
+	return removed
+}
+
+// path returns the path of the current log file
+func (w *rotatingWriter) path() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return filepath.Join(w.config.directory, w.config.fileName)
+}
+
+// setPath switches the writer to a new current file, keeping all rotation and retention
+// settings. The new file is opened before the old one is closed, so on error the writer
+// keeps writing to the old file. Writes serialize on the mutex, so each record lands
+// entirely in either the old or the new file.
+func (w *rotatingWriter) setPath(path string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.closed {
+		return fmt.Errorf("writer has been closed")
+	}
+
+	if w.buf != nil {
+		if err := w.buf.Flush(); err != nil {
+			return fmt.Errorf("failed to flush buffer: %w", err)
+		}
+	}
+	oldFile, oldBuf, oldSize := w.file, w.buf, w.currentSize
+	oldDirectory, oldFileName := w.config.directory, w.config.fileName
+
+	w.config.directory, w.config.fileName = filepath.Dir(path), filepath.Base(path)
+	if err := w.openCurrentFile(); err != nil {
+		w.config.directory, w.config.fileName = oldDirectory, oldFileName
+		w.file, w.buf, w.currentSize = oldFile, oldBuf, oldSize
+		return err
+	}
+
+	if oldFile != nil {
+		if err := oldFile.Close(); err != nil {
+			return fmt.Errorf("failed to close previous log file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Flush writes out buffered data without closing the file
+func (w *rotatingWriter) Flush() error {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
+	if w.closed || w.buf == nil {
+		return nil
+	}
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("failed to flush buffer: %w", err)
+	}
+	return w.syncLocked()
+}
+
+// Close stops the cleanup timer, waits for any in-flight cleanup and closes the rotatingWriter.
+func (w *rotatingWriter) Close() error {
+	w.mutex.Lock()
 
 	// Prevent multiple closes
 	if w.closed {
+		w.mutex.Unlock()
 		return nil
 	}
 	w.closed = true
@@ -268,28 +779,51 @@ func (w *rotatingWriter) Close() error {
 	if w.cleanupTimer != nil {
 		w.cleanupTimer.Stop()
 	}
-	close(w.rotateSignal)
+	if w.rotateTimer != nil {
+		w.rotateTimer.Stop()
+	}
+	w.mutex.Unlock()
+
+	// Wait for an in-flight cleanup without holding the lock, which it needs
+	if w.cleanupCancel != nil {
+		w.cleanupCancel()
+	}
+	w.cleanupWG.Wait()
+	// Likewise for the flush loop; the final flush happens below
+	if w.flushStop != nil {
+		close(w.flushStop)
+		<-w.flushDone
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.rotateSignal != nil {
+		close(w.rotateSignal)
+	}
 
 	if w.buf != nil {
 		_ = w.buf.Flush()
 	}
 	if w.file != nil {
+		syncErr := w.syncLocked()
 		if err := w.file.Close(); err != nil {
 			return err
 		}
 		w.file = nil
 		w.buf = nil
+		return syncErr
 	}
 	return nil
 }
 
 // openCurrentFile opens or creates the current log file and prepares buffered writer.
 func (w *rotatingWriter) openCurrentFile() error {
-	if err := os.MkdirAll(w.config.directory, 0o755); err != nil {
+	if err := os.MkdirAll(w.config.directory, w.dirMode()); err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 	path := filepath.Join(w.config.directory, w.config.fileName)
-	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, w.fileMode())
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
@@ -299,8 +833,25 @@ func (w *rotatingWriter) openCurrentFile() error {
 		return fmt.Errorf("failed to stat log file: %w", err)
 	}
 	w.file = f
-	// 64KB buffer (reasonable default)
-	w.buf = bufio.NewWriterSize(f, 64*1024)
+	if !w.config.unbuffered {
+		w.buf = bufio.NewWriterSize(f, w.config.bufferSize)
+	}
 	w.currentSize = info.Size()
 	return nil
 }
+
+// fileMode returns the permissions for files the writer creates
+func (w *rotatingWriter) fileMode() os.FileMode {
+	if w.config.fileMode == 0 {
+		return DefaultFileMode
+	}
+	return w.config.fileMode
+}
+
+// dirMode returns the permissions for the directory the writer creates
+func (w *rotatingWriter) dirMode() os.FileMode {
+	if w.config.dirMode == 0 {
+		return DefaultDirMode
+	}
+	return w.config.dirMode
+}
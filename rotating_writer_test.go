@@ -1,13 +1,18 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -838,7 +843,7 @@ func TestCleanOldLogs_DoesNotDeleteNonLogFiles(t *testing.T) {
 	}
 	defer writer.Close()
 
-	// Create files that should NOT be deleted but may be deleted due to current implementation
+	// Create files that should NOT be deleted
 	// These files have different patterns that should not match rotation patterns
 	filesToKeep := []string{
 		"application.log", // Different application log
@@ -849,12 +854,13 @@ func TestCleanOldLogs_DoesNotDeleteNonLogFiles(t *testing.T) {
 		"config.json",     // Config file
 	}
 
-	// Files that the current implementation WILL delete (documenting current behavior)
-	// These should ideally be kept but current logic is too broad
-	filesThatWillBeDeleted := []string{
-		"test.log.config", // Starts with "test" (current logic will delete)
-		"test.log.bak",    // Starts with "test" (current logic will delete)
-		"test.abc.log",    // Starts with "test" (current logic will delete)
+	// Files sharing the log basename as a prefix that rotate() never produces; they must be kept
+	prefixedFilesToKeep := []string{
+		"test.log.config",
+		"test.log.bak",
+		"test.abc.log",
+		"test.20230101.120000.000.log.bak", // Rotated name with a user suffix
+		"test.2023-01-01.log",              // Different timestamp layout
 	}
 
 	// Create old log files that SHOULD be deleted (using correct rotation naming pattern)
@@ -877,10 +883,10 @@ func TestCleanOldLogs_DoesNotDeleteNonLogFiles(t *testing.T) {
 		}
 	}
 
-	// Create files that will unfortunately be deleted by current implementation
-	for _, filename := range filesThatWillBeDeleted {
+	// Create old files with the same prefix that are not rotated logs
+	for _, filename := range prefixedFilesToKeep {
 		path := filepath.Join(tmpDir, filename)
-		if err := os.WriteFile(path, []byte("this will be deleted"), 0644); err != nil {
+		if err := os.WriteFile(path, []byte("keep this file too"), 0644); err != nil {
 			t.Fatalf("Failed to create file %s: %v", filename, err)
 		}
 		if err := os.Chtimes(path, oldTime, oldTime); err != nil {
@@ -910,14 +916,11 @@ func TestCleanOldLogs_DoesNotDeleteNonLogFiles(t *testing.T) {
 		}
 	}
 
-	// Document current behavior: these files will be deleted by current implementation
-	// This is actually undesirable behavior that should be fixed
-	for _, filename := range filesThatWillBeDeleted {
+	// Only the exact rotation naming scheme is eligible, so prefixed files survive even when old
+	for _, filename := range prefixedFilesToKeep {
 		path := filepath.Join(tmpDir, filename)
-		if _, err := os.Stat(path); !os.IsNotExist(err) {
-			t.Logf("UNEXPECTED: File %s was kept (current implementation usually deletes files starting with log basename)", filename)
-		} else {
-			t.Logf("DOCUMENTED ISSUE: File %s was deleted by current implementation (this may need fixing)", filename)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			t.Errorf("File %s only shares the log basename and should have been kept", filename)
 		}
 	}
 
@@ -928,10 +931,6 @@ func TestCleanOldLogs_DoesNotDeleteNonLogFiles(t *testing.T) {
 			t.Errorf("File %s should have been deleted but still exists", filename)
 		}
 	}
-
-	// This test documents that the current cleanOldLogs implementation needs improvement
-	// to be more precise about which files to delete
-	t.Log("This test reveals that cleanOldLogs may need more precise file matching logic")
 }
 
 // TestRotationThenImmediateWrite tests that after rotation, the first log to new file is not lost
@@ -1146,6 +1145,89 @@ func TestTimeUntilNextDay(t *testing.T) {
 	}
 }
 
+func TestTimeUntilNextInterval(t *testing.T) {
+	loc := time.FixedZone("TEST", -5*3600)
+	at := func(h, m, sec int) time.Time { return time.Date(2025, 3, 10, h, m, sec, 0, loc) }
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		interval time.Duration
+		want     time.Duration
+	}{
+		{"Hourly mid-hour", at(14, 20, 0), time.Hour, 40 * time.Minute},
+		{"Hourly on the hour", at(14, 0, 0), time.Hour, time.Hour},
+		{"Daily at local midnight", at(18, 0, 0), 24 * time.Hour, 6 * time.Hour},
+		{"Non-dividing interval restarts at midnight", at(23, 0, 0), 7 * time.Hour, time.Hour},
+		{"Longer than a day runs from now", at(9, 0, 0), 48 * time.Hour, 48 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timeUntilNextInterval(tt.now, tt.interval); got != tt.want {
+				t.Errorf("timeUntilNextInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRotatingWriter_RotationInterval tests that the writer rotates on its interval without
+// reaching the size limit, skips intervals with no writes, and stops rotating after Close
+func TestRotatingWriter_RotationInterval(t *testing.T) {
+	tempDir := t.TempDir()
+	const interval = 200 * time.Millisecond
+	rotated := make(chan string, 10)
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:      tempDir,
+		fileName:       "test.log",
+		maxSizeMB:      10,
+		retentionDays:  7,
+		rotateInterval: interval,
+		onRotated:      func(path string) { rotated <- path },
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+
+	if _, err := w.Write([]byte("first interval\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	var path string
+	select {
+	case path = <-rotated:
+	case <-time.After(5 * interval):
+		t.Fatal("Interval rotation did not happen")
+	}
+	if !rotatedFilePattern("test.log").MatchString(filepath.Base(path)) {
+		t.Errorf("Rotated file %q does not use the timestamp naming scheme", path)
+	}
+	if content, err := os.ReadFile(path); err != nil || string(content) != "first interval\n" {
+		t.Errorf("Unexpected rotated content %q (err %v)", content, err)
+	}
+
+	// Nothing is written during the next intervals, so no empty files are rotated
+	select {
+	case path := <-rotated:
+		t.Errorf("Unexpected rotation of an empty file: %s", path)
+	case <-time.After(3 * interval):
+	}
+
+	if _, err := w.Write([]byte("closing\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	select {
+	case path := <-rotated:
+		t.Errorf("Unexpected rotation after Close: %s", path)
+	case <-time.After(2 * interval):
+	}
+	if content, err := os.ReadFile(filepath.Join(tempDir, "test.log")); err != nil || string(content) != "closing\n" {
+		t.Errorf("Expected the current file to keep the last write, got %q (err %v)", content, err)
+	}
+}
+
 // TestRotatingWriter_ErrorConditions tests various error conditions
 func TestRotatingWriter_ErrorConditions(t *testing.T) {
 	t.Run("Write to invalid path", func(t *testing.T) {
@@ -1272,3 +1354,1188 @@ func TestRotatingWriter_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+// TestRotatingWriter_EmptyWrite tests that empty writes are no-ops
+func TestRotatingWriter_EmptyWrite(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "test.log")
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	for _, p := range [][]byte{nil, {}} {
+		n, err := w.Write(p)
+		if err != nil || n != 0 {
+			t.Errorf("Expected (0, nil) for empty write, got (%d, %v)", n, err)
+		}
+	}
+
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected no log file after empty writes, stat err: %v", err)
+	}
+	if w.currentSize != 0 {
+		t.Errorf("Expected currentSize 0 after empty writes, got %d", w.currentSize)
+	}
+
+	data := []byte("real write\n")
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := os.Stat(logPath); err != nil {
+		t.Fatalf("Expected log file after real write: %v", err)
+	}
+	if w.currentSize != int64(len(data)) {
+		t.Errorf("Expected currentSize %d, got %d", len(data), w.currentSize)
+	}
+}
+
+// TestRotationDisabledNoMonitor tests that no rotation goroutine is started when rotation is disabled
+func TestRotationDisabledNoMonitor(t *testing.T) {
+	tempDir := t.TempDir()
+
+	before := runtime.NumGoroutine()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     0,
+		retentionDays: 7,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	after := runtime.NumGoroutine()
+
+	if delta := after - before; delta > 0 {
+		t.Errorf("Expected no new goroutines with rotation disabled, got %d", delta)
+	}
+	if w.rotateSignal != nil {
+		t.Error("Expected no rotate signal channel with rotation disabled")
+	}
+
+	if _, err := w.Write([]byte("append only\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Second Close failed: %v", err)
+	}
+}
+
+// TestRotatingWriter_OnClosedWrite tests that writes after Close are handed to the callback
+func TestRotatingWriter_OnClosedWrite(t *testing.T) {
+	var received [][]byte
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     t.TempDir(),
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+		onClosedWrite: func(p []byte) {
+			received = append(received, p)
+		},
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+
+	if _, err := w.Write([]byte("before close\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data := []byte("after close\n")
+	n, err := w.Write(data)
+	if err != nil {
+		t.Fatalf("Expected no error with callback, got %v", err)
+	}
+	if n != len(data) {
+		t.Errorf("Expected %d bytes reported, got %d", len(data), n)
+	}
+	data[0] = 'X' // The callback must receive its own copy
+	if len(received) != 1 || string(received[0]) != "after close\n" {
+		t.Errorf("Expected callback to receive the record, got %q", received)
+	}
+}
+
+// TestLogger_OnClosedWrite tests the option through the public API
+func TestLogger_OnClosedWrite(t *testing.T) {
+	var received []string
+	logger, err := New(
+		WithConsole(false),
+		WithFilePath(filepath.Join(t.TempDir(), "app.log")),
+		WithOnClosedWrite(func(p []byte) {
+			received = append(received, string(p))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	logger.Close()
+	logger.Info("late message")
+
+	if len(received) != 1 || !strings.Contains(received[0], "late message") {
+		t.Errorf("Expected late record to reach the callback, got %q", received)
+	}
+}
+
+// TestRotatingWriter_RotationEvents tests that a structured record is emitted on rotation
+func TestRotatingWriter_RotationEvents(t *testing.T) {
+	tempDir := t.TempDir()
+	var events safeBuffer
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+		eventLogger:   slog.New(slog.NewJSONHandler(&events, nil)),
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	data := bytes.Repeat([]byte("x"), 1024*1024+1)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for events.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var event map[string]any
+	if err := json.Unmarshal(events.Bytes(), &event); err != nil {
+		t.Fatalf("Failed to parse rotation event %q: %v", events.String(), err)
+	}
+	if event["msg"] != "Log file rotated" {
+		t.Errorf("Unexpected event message: %v", event["msg"])
+	}
+	if event["new_file"] != filepath.Join(tempDir, "test.log") {
+		t.Errorf("Unexpected new_file: %v", event["new_file"])
+	}
+	oldFile, _ := event["old_file"].(string)
+	if _, err := os.Stat(oldFile); err != nil {
+		t.Errorf("Expected old_file %q to exist: %v", oldFile, err)
+	}
+	if size, _ := event["size"].(float64); int(size) != len(data) {
+		t.Errorf("Expected size %d, got %v", len(data), event["size"])
+	}
+}
+
+// safeBuffer is a bytes.Buffer safe for concurrent use
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
+
+func (b *safeBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+func (b *safeBuffer) String() string {
+	return string(b.Bytes())
+}
+
+// TestRotate_CollidingTimestampFiles tests that rotation picks a unique name when files
+// from a previous run already use the same timestamp
+func TestRotate_CollidingTimestampFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	fixed := time.Date(2024, 5, 6, 7, 8, 9, 123_000_000, time.Local)
+
+	// Simulate a previous run that rotated several times within the same millisecond
+	existing := []string{
+		"test.20240506.070809.123.log",
+		"test.20240506.070809.123.1.log",
+		"test.20240506.070809.123.2.log",
+	}
+	for _, name := range existing {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte("previous run\n"), 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	// A near-max current file left over from the previous run
+	if err := os.WriteFile(filepath.Join(tempDir, "test.log"), []byte("leftover\n"), 0o644); err != nil {
+		t.Fatalf("Failed to create current file: %v", err)
+	}
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+	w.now = func() time.Time { return fixed }
+
+	if _, err := w.Write([]byte("new run\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	event, err := w.rotate()
+	if err != nil {
+		t.Fatalf("rotate() failed: %v", err)
+	}
+
+	want := filepath.Join(tempDir, "test.20240506.070809.123.3.log")
+	if event == nil || event.oldFile != want {
+		t.Fatalf("Expected rotated file %s, got %+v", want, event)
+	}
+	content, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatalf("Failed to read rotated file: %v", err)
+	}
+	if string(content) != "leftover\nnew run\n" {
+		t.Errorf("Unexpected rotated content: %q", content)
+	}
+	for _, name := range existing {
+		content, err := os.ReadFile(filepath.Join(tempDir, name))
+		if err != nil || string(content) != "previous run\n" {
+			t.Errorf("Expected %s from the previous run to be untouched, got %q (%v)", name, content, err)
+		}
+	}
+}
+
+// TestCleanOldLogs_ManyUnrelatedFiles tests that cleanup completes promptly in a directory
+// shared with many unrelated files and spanning several read batches
+func TestCleanOldLogs_ManyUnrelatedFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	oldTime := time.Now().AddDate(0, 0, -10)
+
+	const unrelated = 3000
+	for i := 0; i < unrelated; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("other-%04d.dat", i))
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatalf("Failed to create unrelated file: %v", err)
+		}
+	}
+	rotated := []string{"test.20230101.120000.000.log", "test.20230102.120000.000.log"}
+	for _, name := range rotated {
+		path := filepath.Join(tempDir, name)
+		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+			t.Fatalf("Failed to create rotated file: %v", err)
+		}
+		if err := os.Chtimes(path, oldTime, oldTime); err != nil {
+			t.Fatalf("Failed to set file time: %v", err)
+		}
+	}
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	start := time.Now()
+	w.cleanOldLogs(context.Background())
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Cleanup took too long: %v", elapsed)
+	}
+
+	for _, name := range rotated {
+		if _, err := os.Stat(filepath.Join(tempDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", name)
+		}
+	}
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != unrelated {
+		t.Errorf("Expected %d unrelated files to remain, got %d", unrelated, len(entries))
+	}
+}
+
+// TestCleanOldLogs_CancelledContext tests that a cancelled cleanup stops before removing files
+func TestCleanOldLogs_CancelledContext(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "test.20230101.120000.000.log")
+	if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+		t.Fatalf("Failed to create rotated file: %v", err)
+	}
+	oldTime := time.Now().AddDate(0, 0, -10)
+	if err := os.Chtimes(path, oldTime, oldTime); err != nil {
+		t.Fatalf("Failed to set file time: %v", err)
+	}
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w.cleanOldLogs(ctx)
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected file to be kept after cancelled cleanup: %v", err)
+	}
+}
+
+func BenchmarkCleanOldLogs_ManyUnrelatedFiles(b *testing.B) {
+	tempDir := b.TempDir()
+	for i := 0; i < 5000; i++ {
+		if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("other-%04d.dat", i)), nil, 0o644); err != nil {
+			b.Fatalf("Failed to create file: %v", err)
+		}
+	}
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+	})
+	if err != nil {
+		b.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.cleanOldLogs(context.Background())
+	}
+}
+
+// TestRotatingWriter_CloseDuringScheduledCleanup tests closing right as the cleanup timer fires:
+// Close must wait for the in-flight cleanup and the timer must not be re-armed afterwards
+func TestRotatingWriter_CloseDuringScheduledCleanup(t *testing.T) {
+	tempDir := t.TempDir()
+	for i := 0; i < 500; i++ {
+		if err := os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("other-%03d.dat", i)), nil, 0o644); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+	}
+
+	for i := 0; i < 50; i++ {
+		w, err := newRotatingWriter(&rotatingConfig{
+			directory:     tempDir,
+			fileName:      "test.log",
+			maxSizeMB:     1,
+			retentionDays: 7,
+		})
+		if err != nil {
+			t.Fatalf("newRotatingWriter() failed: %v", err)
+		}
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write() failed: %v", err)
+		}
+
+		// Fire the cleanup timer now and race Close against it
+		w.mutex.Lock()
+		w.cleanupTimer.Reset(time.Duration(i%3) * time.Millisecond)
+		w.mutex.Unlock()
+		time.Sleep(time.Duration(i%2) * time.Millisecond)
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+
+		// No cleanup may still be running, and the timer must stay stopped
+		done := make(chan struct{})
+		go func() {
+			w.cleanupWG.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Cleanup still running after Close returned")
+		}
+		w.mutex.Lock()
+		rearmed := w.cleanupTimer.Stop()
+		w.mutex.Unlock()
+		if rearmed {
+			t.Fatal("Cleanup timer was re-armed after Close")
+		}
+	}
+}
+
+// TestRotatingWriter_AtomicRecords writes records larger than PIPE_BUF through two writers
+// with separate file descriptors, as two processes would, and checks no record is interleaved
+func TestRotatingWriter_AtomicRecords(t *testing.T) {
+	if !fileLockSupported {
+		t.Skip("flock is not supported on this platform")
+	}
+
+	tempDir := t.TempDir()
+	newWriter := func() *rotatingWriter {
+		w, err := newRotatingWriter(&rotatingConfig{
+			directory:     tempDir,
+			fileName:      "shared.log",
+			retentionDays: 7,
+			atomicRecords: true,
+		})
+		if err != nil {
+			t.Fatalf("newRotatingWriter() failed: %v", err)
+		}
+		return w
+	}
+	writers := []*rotatingWriter{newWriter(), newWriter()}
+
+	const recordSize = 256 * 1024
+	const recordsPerWriter = 20
+	var wg sync.WaitGroup
+	for i, w := range writers {
+		wg.Add(1)
+		go func(w *rotatingWriter, fill byte) {
+			defer wg.Done()
+			record := append(bytes.Repeat([]byte{fill}, recordSize-1), '\n')
+			for j := 0; j < recordsPerWriter; j++ {
+				if _, err := w.Write(record); err != nil {
+					t.Errorf("Write() failed: %v", err)
+					return
+				}
+			}
+		}(w, byte('a'+i))
+	}
+	wg.Wait()
+	for _, w := range writers {
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "shared.log"))
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != len(writers)*recordsPerWriter {
+		t.Fatalf("Expected %d records, got %d", len(writers)*recordsPerWriter, len(lines))
+	}
+	for i, line := range lines {
+		if len(line) != recordSize-1 || strings.Trim(line, line[:1]) != "" {
+			t.Fatalf("Record %d is interleaved or truncated (length %d)", i, len(line))
+		}
+	}
+}
+
+// TestRotatingWriter_WritesDuringRotation tests that writes racing a size-triggered rotation
+// all land in a log file, and that the rotated file is synced outside the mutex,
+// so those writes don't wait for it
+func TestRotatingWriter_WritesDuringRotation(t *testing.T) {
+	tempDir := t.TempDir()
+	rotatedCh := make(chan string, 1)
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+		onRotated: func(path string) {
+			select {
+			case rotatedCh <- path:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	// Without fsync, the rotated file is the only one synced. The mutex may be held briefly
+	// by a racing Write, but never for the whole sync as it would be if taken by rotate.
+	var syncedUnlocked atomic.Bool
+	w.sync = func(f *os.File) error {
+		for i := 0; i < 100 && !syncedUnlocked.Load(); i++ {
+			if w.mutex.TryLock() {
+				w.mutex.Unlock()
+				syncedUnlocked.Store(true)
+			} else {
+				time.Sleep(time.Millisecond)
+			}
+		}
+		return f.Sync()
+	}
+
+	line := []byte("written during rotation\n")
+	const maxWrites = 20000 // Well below a second rotation's worth of data
+	stop := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		var writes int
+		var maxStall time.Duration
+		for writes < maxWrites {
+			select {
+			case <-stop:
+				t.Logf("max write latency during rotation: %v over %d writes", maxStall, writes)
+				done <- writes
+				return
+			default:
+			}
+			start := time.Now()
+			if _, err := w.Write(line); err != nil {
+				t.Errorf("Write during rotation failed: %v", err)
+			}
+			maxStall = max(maxStall, time.Since(start))
+			writes++
+		}
+		<-stop
+		done <- writes
+	}()
+
+	if _, err := w.Write(append(bytes.Repeat([]byte("x"), 1024*1024), '\n')); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	select {
+	case <-rotatedCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Rotation did not happen")
+	}
+	close(stop)
+	writes := <-done
+
+	if !syncedUnlocked.Load() {
+		t.Error("Expected the rotated file to be synced without holding the mutex")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	// Writes after the size was exceeded may signal another rotation, so count in every file
+	paths, err := filepath.Glob(filepath.Join(tempDir, "test*.log"))
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	var lines int
+	for _, path := range paths {
+		lines += strings.Count(readFileString(t, path), string(line))
+	}
+	if lines != writes {
+		t.Errorf("Expected all %d racing writes in %v, found %d", writes, paths, lines)
+	}
+}
+
+// TestCleanOldLogs_MaxAge tests that a sub-day max age removes files older than the
+// duration but younger than a day, and takes precedence over retentionDays
+func TestCleanOldLogs_MaxAge(t *testing.T) {
+	tempDir := t.TempDir()
+
+	expired := filepath.Join(tempDir, "test.20250101.000000.000.log")
+	recent := filepath.Join(tempDir, "test.20250101.060000.000.log")
+	for path, age := range map[string]time.Duration{expired: 3 * time.Hour, recent: 30 * time.Minute} {
+		if err := os.WriteFile(path, []byte("log"), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+		modTime := time.Now().Add(-age)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set times on %s: %v", path, err)
+		}
+	}
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		retentionDays: 7,
+		maxAge:        2 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	w.cleanOldLogs(context.Background())
+
+	if _, err := os.Stat(expired); !os.IsNotExist(err) {
+		t.Errorf("Expected %s older than max age to be removed, stat err = %v", expired, err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("Expected %s within max age to be kept: %v", recent, err)
+	}
+}
+
+// TestRotatingWriter_MaxAgeSchedule tests that a sub-day max age schedules cleanups at
+// that interval rather than daily, so expired files go without a manual cleanup
+func TestRotatingWriter_MaxAgeSchedule(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		retentionDays: 7,
+		maxAge:        50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	if got := w.cleanupInterval(); got != 50*time.Millisecond {
+		t.Errorf("Expected cleanups every 50ms, got %v", got)
+	}
+
+	// Created after the first cleanup may have run, it expires within a later one
+	rotated := filepath.Join(tempDir, "test.20250101.000000.000.log")
+	if err := os.WriteFile(rotated, []byte("log"), 0644); err != nil {
+		t.Fatalf("Failed to create %s: %v", rotated, err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(rotated); os.IsNotExist(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the scheduled cleanup to remove the expired file")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Retention of a day or longer keeps the daily schedule
+	daily := &rotatingWriter{config: &rotatingConfig{maxAge: 48 * time.Hour}}
+	if got := daily.cleanupInterval(); got != 24*time.Hour {
+		t.Errorf("Expected daily cleanups for a 48h max age, got %v", got)
+	}
+}
+
+// TestRotatingWriter_Unbuffered tests that an unbuffered writer has no bufio.Writer and
+// its records are readable as soon as Write returns
+func TestRotatingWriter_Unbuffered(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tempDir,
+		fileName:      "test.log",
+		maxSizeMB:     1,
+		retentionDays: 7,
+		unbuffered:    true,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	path := filepath.Join(tempDir, "test.log")
+	var want []byte
+	for i := 0; i < 3; i++ {
+		line := []byte(fmt.Sprintf("record %d\n", i))
+		if _, err := w.Write(line); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		want = append(want, line...)
+
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read log file: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("After write %d expected %q, got %q", i, want, got)
+		}
+	}
+	if w.buf != nil {
+		t.Error("Expected no bufio.Writer in unbuffered mode")
+	}
+	if w.currentSize != int64(len(want)) {
+		t.Errorf("Expected size %d, got %d", len(want), w.currentSize)
+	}
+}
+
+func BenchmarkRotatingWriter_Buffering(b *testing.B) {
+	line := []byte("2025/01/02 15:04:05 INFO benchmark record user=john status=200\n")
+	for _, unbuffered := range []bool{false, true} {
+		b.Run(fmt.Sprintf("unbuffered=%v", unbuffered), func(b *testing.B) {
+			w, err := newRotatingWriter(&rotatingConfig{
+				directory:  b.TempDir(),
+				fileName:   "bench.log",
+				unbuffered: unbuffered,
+			})
+			if err != nil {
+				b.Fatalf("newRotatingWriter() failed: %v", err)
+			}
+			defer w.Close()
+
+			b.SetBytes(int64(len(line)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := w.Write(line); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// TestRotatingWriter_RotationMarkers tests that rotation markers end the rotated file and
+// start the new one, as text or as JSON objects
+// TestRotatingWriter_RotationMarkersFailedRename tests that a file whose rename failed gets
+// no end marker and keeps receiving records
+func TestRotatingWriter_RotationMarkersFailedRename(t *testing.T) {
+	tempDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:       tempDir,
+		fileName:        "test.log",
+		retentionDays:   7,
+		rotationMarkers: true,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+	w.rename = func(string, string) error { return errors.New("rename refused") }
+
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := w.rotate(); err == nil || !strings.Contains(err.Error(), "rename refused") {
+		t.Fatalf("Expected the rename error, got %v", err)
+	}
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	content := readFileString(t, filepath.Join(tempDir, "test.log"))
+	if content != "before\nafter\n" {
+		t.Errorf("Expected no rotation marker in a file that wasn't rotated, got %q", content)
+	}
+}
+
+func TestRotatingWriter_RotationMarkers(t *testing.T) {
+	rotateWithMarkers := func(t *testing.T, jsonMarkers bool) (rotated, current []byte, rotatedPath string) {
+		tempDir := t.TempDir()
+		rotatedCh := make(chan string, 1)
+		w, err := newRotatingWriter(&rotatingConfig{
+			directory:       tempDir,
+			fileName:        "test.log",
+			maxSizeMB:       1,
+			retentionDays:   7,
+			rotationMarkers: true,
+			jsonMarkers:     jsonMarkers,
+			onRotated:       func(path string) { rotatedCh <- path },
+		})
+		if err != nil {
+			t.Fatalf("newRotatingWriter() failed: %v", err)
+		}
+		defer w.Close()
+
+		if _, err := w.Write(append(bytes.Repeat([]byte("x"), 1024*1024), '\n')); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		select {
+		case rotatedPath = <-rotatedCh:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Rotation did not happen")
+		}
+		if _, err := w.Write([]byte("after rotation\n")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+
+		if rotated, err = os.ReadFile(rotatedPath); err != nil {
+			t.Fatalf("Failed to read rotated file: %v", err)
+		}
+		if current, err = os.ReadFile(filepath.Join(tempDir, "test.log")); err != nil {
+			t.Fatalf("Failed to read current file: %v", err)
+		}
+		return rotated, current, rotatedPath
+	}
+
+	lines := func(data []byte) []string {
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		rotated, current, rotatedPath := rotateWithMarkers(t, false)
+
+		rotatedLines := lines(rotated)
+		last := rotatedLines[len(rotatedLines)-1]
+		if !strings.HasPrefix(last, "--- rotated at ") || !strings.HasSuffix(last, ", continued in test.log ---") {
+			t.Errorf("Unexpected end marker %q", last)
+		}
+		currentLines := lines(current)
+		want := ", continued from " + filepath.Base(rotatedPath) + " ---"
+		if !strings.HasPrefix(currentLines[0], "--- rotated at ") || !strings.HasSuffix(currentLines[0], want) {
+			t.Errorf("Unexpected start marker %q", currentLines[0])
+		}
+		if len(currentLines) != 2 || currentLines[1] != "after rotation" {
+			t.Errorf("Expected the record after the start marker, got %q", currentLines)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		rotated, current, rotatedPath := rotateWithMarkers(t, true)
+
+		var end, start map[string]any
+		rotatedLines := lines(rotated)
+		if err := json.Unmarshal([]byte(rotatedLines[len(rotatedLines)-1]), &end); err != nil {
+			t.Fatalf("End marker is not JSON: %v", err)
+		}
+		if err := json.Unmarshal([]byte(lines(current)[0]), &start); err != nil {
+			t.Fatalf("Start marker is not JSON: %v", err)
+		}
+		if end["continued_in"] != "test.log" || end["time"] == nil {
+			t.Errorf("Unexpected end marker %v", end)
+		}
+		if start["continued_from"] != filepath.Base(rotatedPath) || start["time"] != end["time"] {
+			t.Errorf("Unexpected start marker %v", start)
+		}
+	})
+}
+
+// TestCleanOldLogs_MaxTotalSize tests that the oldest rotated files are removed until the
+// rest fit the size cap, never counting or deleting the current file
+func TestCleanOldLogs_MaxTotalSize(t *testing.T) {
+	tempDir := t.TempDir()
+	const fileSize = 400 * 1024
+
+	// Current file larger than the whole budget
+	current := filepath.Join(tempDir, "test.log")
+	if err := os.WriteFile(current, bytes.Repeat([]byte("c"), 3*1024*1024), 0644); err != nil {
+		t.Fatalf("Failed to create current file: %v", err)
+	}
+
+	var rotated []string
+	for i := 0; i < 5; i++ {
+		path := filepath.Join(tempDir, fmt.Sprintf("test.20250101.00000%d.000.log", i))
+		if err := os.WriteFile(path, bytes.Repeat([]byte("r"), fileSize), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+		modTime := time.Now().Add(time.Duration(i-5) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set times on %s: %v", path, err)
+		}
+		rotated = append(rotated, path)
+	}
+
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:      tempDir,
+		fileName:       "test.log",
+		retentionDays:  7,
+		maxTotalSizeMB: 1,
+	})
+	if err != nil {
+		t.Fatalf("newRotatingWriter() failed: %v", err)
+	}
+	defer w.Close()
+
+	w.cleanOldLogs(context.Background())
+
+	// 5 x 400KB exceeds 1MB; only the two newest (800KB) fit
+	for i, path := range rotated {
+		_, err := os.Stat(path)
+		if i < 3 && !os.IsNotExist(err) {
+			t.Errorf("Expected old file %s to be removed, stat err = %v", filepath.Base(path), err)
+		}
+		if i >= 3 && err != nil {
+			t.Errorf("Expected newest file %s to survive: %v", filepath.Base(path), err)
+		}
+	}
+	if _, err := os.Stat(current); err != nil {
+		t.Errorf("Expected current file to be kept: %v", err)
+	}
+}
+
+func TestRotatingWriter_BufferSize(t *testing.T) {
+	newFileLogger := func(t *testing.T, opts ...Option) *rotatingWriter {
+		t.Helper()
+		l, err := New(append([]Option{WithConsole(false), WithFilePath(filepath.Join(t.TempDir(), "app.log"))}, opts...)...)
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		t.Cleanup(func() { l.Close() })
+		l.Info("open the file")
+		return l.fileWriter
+	}
+
+	if w := newFileLogger(t); w.buf == nil || w.buf.Size() != DefaultBufferSize {
+		t.Errorf("Expected a %d byte buffer by default", DefaultBufferSize)
+	}
+	if w := newFileLogger(t, WithBufferSize(4096)); w.buf == nil || w.buf.Size() != 4096 {
+		t.Error("Expected a 4096 byte buffer")
+	}
+	if w := newFileLogger(t, WithBufferSize(0)); w.buf == nil || w.buf.Size() != DefaultBufferSize {
+		t.Errorf("Expected buffer size 0 to use the %d byte default", DefaultBufferSize)
+	}
+	if w := newFileLogger(t, WithBufferSize(4096), WithUnbuffered(true)); w.buf != nil {
+		t.Error("Expected WithUnbuffered to drop the buffer whatever its size")
+	}
+
+	// Records larger than a tiny buffer still arrive intact
+	w := newFileLogger(t, WithBufferSize(16))
+	record := bytes.Repeat([]byte("y"), 1000)
+	if _, err := w.Write(record); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	content, err := os.ReadFile(w.path())
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !bytes.HasSuffix(content, record) {
+		t.Errorf("Expected the large record to be written in full")
+	}
+
+	if _, err := New(WithFilePath(filepath.Join(t.TempDir(), "app.log")), WithBufferSize(-1)); err == nil {
+		t.Error("Expected an error for a negative buffer size")
+	}
+}
+
+func TestRotatingWriter_AsyncWrites(t *testing.T) {
+	tmpDir := t.TempDir()
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     tmpDir,
+		fileName:      "async.log",
+		flushInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	defer w.Close()
+
+	path := filepath.Join(tmpDir, "async.log")
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); len(content) != 0 {
+		t.Errorf("Expected the record to stay buffered until the next flush, got %q", content)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		content, _ := os.ReadFile(path)
+		if string(content) == "first\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the periodic flush to write the record, got %q", content)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Close flushes whatever is still buffered and stops the flush loop
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if string(content) != "first\nsecond\n" {
+		t.Errorf("Expected both records after Close, got %q", content)
+	}
+	select {
+	case <-w.flushDone:
+	default:
+		t.Error("Expected the flush loop to exit on Close")
+	}
+}
+
+func TestRotatingWriter_BackgroundFlushError(t *testing.T) {
+	errs := make(chan error, 10)
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory:     t.TempDir(),
+		fileName:      "async.log",
+		flushInterval: 10 * time.Millisecond,
+		onError:       func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("buffered\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// Make the next flush fail, as a vanished disk would
+	w.mutex.Lock()
+	w.file.Close()
+	w.mutex.Unlock()
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("Expected a non-nil error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the failed background flush to reach onError")
+	}
+}
+
+func TestRotatingWriter_Fsync(t *testing.T) {
+	for _, fsync := range []bool{false, true} {
+		w, err := newRotatingWriter(&rotatingConfig{
+			directory: t.TempDir(),
+			fileName:  "audit.log",
+			maxSizeMB: 100,
+			fsync:     fsync,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create writer: %v", err)
+		}
+		var synced int
+		w.sync = func(f *os.File) error {
+			synced++
+			return nil
+		}
+
+		for i := 0; i < 3; i++ {
+			if _, err := w.Write([]byte("record\n")); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+		}
+		writes := synced
+		if _, err := w.rotate(); err != nil {
+			t.Fatalf("rotate failed: %v", err)
+		}
+		rotation := synced - writes
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		closing := synced - writes - rotation
+
+		want := [3]int{0, 1, 0} // The rotated file is always synced, best effort
+		if fsync {
+			want = [3]int{3, 1, 1}
+		}
+		if got := [3]int{writes, rotation, closing}; got != want {
+			t.Errorf("fsync=%v: expected syncs on write, rotation and close %v, got %v", fsync, want, got)
+		}
+	}
+
+	// A failing sync is reported by Write, but the written bytes still count towards rotation
+	var rotated []string
+	w, err := newRotatingWriter(&rotatingConfig{
+		directory: t.TempDir(),
+		fileName:  "audit.log",
+		fsync:     true,
+		onRotated: func(path string) { rotated = append(rotated, path) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create writer: %v", err)
+	}
+	defer w.Close()
+	w.sync = func(*os.File) error { return errors.New("device gone") }
+	if n, err := w.Write([]byte("record\n")); err == nil || !strings.Contains(err.Error(), "device gone") || n != 7 {
+		t.Errorf("Expected the sync error from Write after 7 bytes, got %d %v", n, err)
+	}
+	if w.currentSize != 7 {
+		t.Errorf("Expected the written bytes to be counted, got a size of %d", w.currentSize)
+	}
+
+	// A rotated file that fails to sync is rotated all the same, hooks included
+	event, err := w.rotate()
+	if err == nil || !strings.Contains(err.Error(), "failed to sync rotated file") {
+		t.Errorf("Expected the rotated file's sync error, got %v", err)
+	}
+	if event == nil || len(rotated) != 1 || rotated[0] != event.oldFile {
+		t.Errorf("Expected the rotation to complete despite the sync error, got event %v and hooks %v", event, rotated)
+	}
+}
+
+func TestWithFsync(t *testing.T) {
+	l, err := New(WithConsole(false), WithFilePath(filepath.Join(t.TempDir(), "app.log")), WithFsync(true))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer l.Close()
+	if !l.fileWriter.config.fsync {
+		t.Error("Expected WithFsync to enable fsync on the file writer")
+	}
+	if DefaultConfig().File.Fsync {
+		t.Error("Expected fsync to be off by default")
+	}
+}
+
+func TestWithFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits")
+	}
+	dir := filepath.Join(t.TempDir(), "private")
+	logPath := filepath.Join(dir, "app.log")
+	l, err := New(
+		WithConsole(false),
+		WithFilePath(logPath),
+		WithFileMode(0o600),
+		WithDirMode(0o700),
+		WithReliableRetention(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer l.Close()
+
+	l.Info("secret")
+	event, err := l.fileWriter.rotate()
+	if err != nil || event == nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	l.Info("after rotation")
+
+	for path, want := range map[string]os.FileMode{
+		dir:                        0o700,
+		logPath:                    0o600,
+		event.oldFile:              0o600,
+		event.oldFile + metaSuffix: 0o600,
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", path, err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("Expected %s to have mode %#o, got %#o", filepath.Base(path), want, got)
+		}
+	}
+}
+
+func TestWithSyncWrites(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(WithConsole(false), WithFilePath(logPath), WithSyncWrites(false))
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	if l.fileWriter.config.flushInterval != DefaultFlushInterval {
+		t.Errorf("Expected flush interval %v, got %v", DefaultFlushInterval, l.fileWriter.config.flushInterval)
+	}
+	l.Info("buffered")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "buffered") {
+		t.Errorf("Expected the record to be flushed on Close, got %q", content)
+	}
+
+	// Sync writes are the default
+	if cfg := DefaultConfig(); !cfg.File.SyncWrites {
+		t.Error("Expected SyncWrites to default to true")
+	}
+}
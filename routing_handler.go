@@ -0,0 +1,358 @@
+package logger
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRoutingMaxOpen is the default maximum number of routed files kept open at once
+const DefaultRoutingMaxOpen = 64
+
+// routingIdleTimeout is how long a routed file may stay unused before it is closed
+const routingIdleTimeout = 10 * time.Minute
+
+// routeEntry is a lazily created file destination for a single routing value
+type routeEntry struct {
+	key      string
+	handler  slog.Handler
+	closer   io.Closer
+	lastUsed time.Time
+	refs     int  // Records currently being written through this entry
+	evicted  bool // Removed from the cache, closed once refs drops to zero
+}
+
+// routingState is the per-key file cache shared by a routingHandler and all handlers derived from it
+type routingState struct {
+	mu           sync.Mutex
+	cfg          *Config
+	attrKey      string
+	pathTemplate string
+	maxOpen      int
+	idleTimeout  time.Duration
+	now          func() time.Time
+
+	fallback       slog.Handler // Default file for records lacking the routing key
+	fallbackCloser io.Closer
+
+	routes map[string]*list.Element
+	lru    *list.List // Most recently used entries at the front
+	closed bool
+}
+
+// routingHandler is a slog.Handler that writes each record to a file chosen by the value of one attribute
+type routingHandler struct {
+	state *routingState
+	// ops replays WithAttrs/WithGroup calls on the per-key handlers, which may be created later
+	ops        []func(slog.Handler) slog.Handler
+	routeValue string         // Routing value bound through WithAttrs, if any
+	grouped    bool           // A group is open, so record attributes can no longer carry the routing key
+	derived    *derivedRoutes // Destination handlers with ops applied, nil without ops
+}
+
+// derivedRoutes caches the destination handlers of a derived routingHandler, so its
+// attributes and groups are applied once per destination rather than on every record
+type derivedRoutes struct {
+	mu       sync.Mutex
+	fallback slog.Handler
+	routes   map[string]derivedRoute
+}
+
+// derivedRoute is the derived handler of a route entry. A key routed again after its entry
+// was evicted gets a new entry, which replaces the stale handler.
+type derivedRoute struct {
+	entry   *routeEntry
+	handler slog.Handler
+}
+
+// newRoutingHandler creates a routing handler whose default destination is cfg.File.Path
+func newRoutingHandler(cfg *Config) (slog.Handler, io.Closer, error) {
+	fallback, fallbackCloser, err := newFileHandlerAt(cfg, cfg.File.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	state := &routingState{
+		cfg:            cfg,
+		attrKey:        cfg.File.RoutingKey,
+		pathTemplate:   cfg.File.RoutingPath,
+		maxOpen:        cfg.File.RoutingMaxOpen,
+		idleTimeout:    routingIdleTimeout,
+		now:            time.Now,
+		fallback:       fallback,
+		fallbackCloser: fallbackCloser,
+		routes:         make(map[string]*list.Element),
+		lru:            list.New(),
+	}
+	return &routingHandler{state: state}, state, nil
+}
+
+// Enabled implements slog.Handler
+func (h *routingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	// Every destination shares the same level configuration
+	return h.state.fallback.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *routingHandler) Handle(ctx context.Context, r slog.Record) error {
+	value := h.routeValue
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key == h.state.attrKey {
+				value = a.Value.Resolve().String()
+				return false
+			}
+			return true
+		})
+	}
+
+	key := sanitizeRouteValue(value)
+	if key == "" {
+		return h.fallbackHandler().Handle(ctx, r)
+	}
+
+	entry, err := h.state.acquire(key)
+	if err != nil {
+		return err
+	}
+	defer h.state.release(entry)
+	return h.entryHandler(entry).Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *routingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := h.clone()
+	if !h.grouped {
+		for _, a := range attrs {
+			if a.Key == h.state.attrKey {
+				clone.routeValue = a.Value.Resolve().String()
+			}
+		}
+	}
+	clone.ops = append(clone.ops, func(handler slog.Handler) slog.Handler {
+		return handler.WithAttrs(attrs)
+	})
+	return clone
+}
+
+// WithGroup implements slog.Handler
+func (h *routingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := h.clone()
+	clone.grouped = true
+	clone.ops = append(clone.ops, func(handler slog.Handler) slog.Handler {
+		return handler.WithGroup(name)
+	})
+	return clone
+}
+
+func (h *routingHandler) clone() *routingHandler {
+	return &routingHandler{
+		state:      h.state,
+		ops:        append([]func(slog.Handler) slog.Handler(nil), h.ops...),
+		routeValue: h.routeValue,
+		grouped:    h.grouped,
+		derived:    &derivedRoutes{routes: make(map[string]derivedRoute)},
+	}
+}
+
+// fallbackHandler returns the default file's handler with the handler's ops applied
+func (h *routingHandler) fallbackHandler() slog.Handler {
+	if h.derived == nil {
+		return h.state.fallback
+	}
+	d := h.derived
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fallback == nil {
+		d.fallback = h.apply(h.state.fallback)
+	}
+	return d.fallback
+}
+
+// entryHandler returns the entry's handler with the handler's ops applied
+func (h *routingHandler) entryHandler(entry *routeEntry) slog.Handler {
+	if h.derived == nil {
+		return entry.handler
+	}
+	d := h.derived
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if route, ok := d.routes[entry.key]; ok && route.entry == entry {
+		return route.handler
+	}
+	// Only maxOpen entries are live at once, so a full cache mostly holds evicted ones
+	if len(d.routes) >= h.state.maxOpen {
+		clear(d.routes)
+	}
+	handler := h.apply(entry.handler)
+	d.routes[entry.key] = derivedRoute{entry: entry, handler: handler}
+	return handler
+}
+
+// apply replays the handler's attributes and groups on a destination handler
+func (h *routingHandler) apply(handler slog.Handler) slog.Handler {
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	return handler
+}
+
+// acquire returns the entry for key, creating its file handler on first use.
+// The caller must call release once the record is written.
+func (s *routingState) acquire(key string) (*routeEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("routing handler is closed")
+	}
+
+	now := s.now()
+	s.closeIdleLocked(now)
+
+	if elem, ok := s.routes[key]; ok {
+		entry := elem.Value.(*routeEntry)
+		s.lru.MoveToFront(elem)
+		entry.lastUsed = now
+		entry.refs++
+		return entry, nil
+	}
+
+	// Make room by closing the least recently used file
+	for s.lru.Len() >= s.maxOpen {
+		s.evictLocked(s.lru.Back())
+	}
+
+	path := strings.ReplaceAll(s.pathTemplate, "{"+s.attrKey+"}", key)
+	handler, closer, err := newFileHandlerAt(s.cfg, path)
+	if err != nil {
+		return nil, fmt.Errorf("routed file %s: %w", path, err)
+	}
+	entry := &routeEntry{
+		key:      key,
+		handler:  handler,
+		closer:   closer,
+		lastUsed: now,
+		refs:     1,
+	}
+	s.routes[key] = s.lru.PushFront(entry)
+	return entry, nil
+}
+
+// release marks a record as written and closes the entry if it was evicted meanwhile
+func (s *routingState) release(entry *routeEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		entry.closer.Close()
+	}
+}
+
+// closeIdleLocked evicts entries that have not been used within the idle timeout
+func (s *routingState) closeIdleLocked(now time.Time) {
+	for elem := s.lru.Back(); elem != nil; elem = s.lru.Back() {
+		if now.Sub(elem.Value.(*routeEntry).lastUsed) < s.idleTimeout {
+			return
+		}
+		s.evictLocked(elem)
+	}
+}
+
+// evictLocked removes an entry from the cache, closing it unless a write is still in progress
+func (s *routingState) evictLocked(elem *list.Element) {
+	entry := s.lru.Remove(elem).(*routeEntry)
+	delete(s.routes, entry.key)
+	entry.evicted = true
+	if entry.refs == 0 {
+		entry.closer.Close()
+	}
+}
+
+// Flush flushes all open routed files and the default file
+func (s *routingState) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+
+	var errs []error
+	for elem := s.lru.Front(); elem != nil; elem = elem.Next() {
+		if f, ok := elem.Value.(*routeEntry).closer.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if f, ok := s.fallbackCloser.(Flusher); ok {
+		if err := f.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes all routed files and the default file
+func (s *routingState) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	for elem := s.lru.Front(); elem != nil; elem = s.lru.Front() {
+		entry := s.lru.Remove(elem).(*routeEntry)
+		delete(s.routes, entry.key)
+		entry.evicted = true
+		if entry.refs == 0 {
+			if err := entry.closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.fallbackCloser.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// openRoutes returns the number of routed files currently cached
+func (s *routingState) openRoutes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// sanitizeRouteValue turns an attribute value into a safe file name component.
+// Anything other than letters, digits, '-', '_' and '.' is replaced so values can't escape the log directory.
+func sanitizeRouteValue(value string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+	if sanitized == "." || sanitized == ".." {
+		return "_"
+	}
+	return sanitized
+}
@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFileString(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+func TestWithRoutingKey_TwoTenants(t *testing.T) {
+	dir := t.TempDir()
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(filepath.Join(dir, "default.log")),
+		WithFileFormat(FormatText),
+		WithRoutingKey("tenant", filepath.Join(dir, "{tenant}.log")),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	log.Info("acme order", "tenant", "acme")
+	log.With("tenant", "globex").Info("globex order")
+	log.Info("no tenant")
+	log.WithGroup("req").Info("grouped", "tenant", "acme")
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	acme := readFileString(t, filepath.Join(dir, "acme.log"))
+	globex := readFileString(t, filepath.Join(dir, "globex.log"))
+	fallback := readFileString(t, filepath.Join(dir, "default.log"))
+
+	if !strings.Contains(acme, "acme order") || strings.Contains(acme, "globex") {
+		t.Errorf("Unexpected acme.log content: %q", acme)
+	}
+	if !strings.Contains(globex, "globex order") || !strings.Contains(globex, "tenant=globex") || strings.Contains(globex, "acme") {
+		t.Errorf("Unexpected globex.log content: %q", globex)
+	}
+	if !strings.Contains(fallback, "no tenant") {
+		t.Errorf("Expected record without tenant in default.log, got %q", fallback)
+	}
+	// A grouped attribute is req.tenant, not the top-level routing key
+	if !strings.Contains(fallback, "req.tenant=acme") {
+		t.Errorf("Expected grouped record in default.log, got %q", fallback)
+	}
+}
+
+func TestRoutingHandler_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	WithConsole(false)(cfg)
+	WithFilePath(filepath.Join(dir, "default.log"))(cfg)
+	WithFileFormat(FormatText)(cfg)
+	WithRoutingKey("tenant", filepath.Join(dir, "{tenant}.log"))(cfg)
+	WithRoutingMaxOpen(1)(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("validateConfig() failed: %v", err)
+	}
+
+	handler, closer, err := newRoutingHandler(cfg)
+	if err != nil {
+		t.Fatalf("newRoutingHandler() failed: %v", err)
+	}
+	state := closer.(*routingState)
+	l := slog.New(handler)
+	l.Info("first", "tenant", "a")
+	l.Info("second", "tenant", "b")
+	l.Info("third", "tenant", "a")
+	if n := state.openRoutes(); n != 1 {
+		t.Errorf("Expected 1 open route, got %d", n)
+	}
+
+	// Idle routes are closed on the next access
+	state.now = func() time.Time { return time.Now().Add(2 * routingIdleTimeout) }
+	l.Info("fourth", "tenant", "b")
+	if n := state.openRoutes(); n != 1 {
+		t.Errorf("Expected 1 open route after idle close, got %d", n)
+	}
+
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	a := readFileString(t, filepath.Join(dir, "a.log"))
+	if !strings.Contains(a, "first") || !strings.Contains(a, "third") {
+		t.Errorf("Expected a.log to keep records across reopen, got %q", a)
+	}
+	b := readFileString(t, filepath.Join(dir, "b.log"))
+	if !strings.Contains(b, "second") || !strings.Contains(b, "fourth") {
+		t.Errorf("Expected b.log to keep records across reopen, got %q", b)
+	}
+}
+
+func TestRoutingHandler_DerivedHandlersCached(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	WithConsole(false)(cfg)
+	WithFilePath(filepath.Join(dir, "default.log"))(cfg)
+	WithFileFormat(FormatText)(cfg)
+	WithRoutingKey("tenant", filepath.Join(dir, "{tenant}.log"))(cfg)
+	WithRoutingMaxOpen(1)(cfg)
+	if err := validateConfig(cfg); err != nil {
+		t.Fatalf("validateConfig() failed: %v", err)
+	}
+
+	handler, closer, err := newRoutingHandler(cfg)
+	if err != nil {
+		t.Fatalf("newRoutingHandler() failed: %v", err)
+	}
+	defer closer.Close()
+
+	// Count how often the derived chain is built
+	derived := handler.WithAttrs([]slog.Attr{slog.String("app", "api")}).(*routingHandler)
+	builds := 0
+	derived.ops = append(derived.ops, func(h slog.Handler) slog.Handler {
+		builds++
+		return h
+	})
+	l := slog.New(derived)
+
+	for i := 0; i < 3; i++ {
+		l.Info("routed", "tenant", "a")
+		l.Info("default")
+	}
+	if builds != 2 {
+		t.Errorf("Expected the chain built once per destination, got %d builds", builds)
+	}
+
+	// Evicting a's file gives it a new entry, whose chain is built again
+	l.Info("routed", "tenant", "b")
+	l.Info("routed", "tenant", "a")
+	if builds != 4 {
+		t.Errorf("Expected a rebuild for each new entry, got %d builds", builds)
+	}
+	if err := closer.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if a := readFileString(t, filepath.Join(dir, "a.log")); strings.Count(a, "app=api") != 4 {
+		t.Errorf("Expected all records of a with the derived attrs, got %q", a)
+	}
+}
+
+func TestSanitizeRouteValue(t *testing.T) {
+	tests := map[string]string{
+		"acme":         "acme",
+		"tenant-1_x.y": "tenant-1_x.y",
+		"../etc":       ".._etc",
+		"..":           "_",
+		"a/b\\c":       "a_b_c",
+		"":             "",
+	}
+	for in, want := range tests {
+		if got := sanitizeRouteValue(in); got != want {
+			t.Errorf("sanitizeRouteValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWithRoutingKey_Validation(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := New(
+		WithFilePath(filepath.Join(dir, "app.log")),
+		WithRoutingKey("tenant", filepath.Join(dir, "routed.log")),
+	); err == nil {
+		t.Error("Expected error for routing path without placeholder")
+	}
+	if _, err := New(WithRoutingKey("tenant", filepath.Join(dir, "{tenant}.log"))); err == nil {
+		t.Error("Expected error for routing without file logging")
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// samplingHandler is a slog.Handler that drops records according to per-level sampling rates
+type samplingHandler struct {
+	handler slog.Handler
+	state   *samplingState // Shared by handlers derived via WithAttrs/WithGroup
+}
+
+// samplingState holds the sampling rates and counters shared across derived handlers
+type samplingState struct {
+	rates      map[slog.Level]uint64
+	counters   map[slog.Level]*atomic.Uint64
+	suppressed *suppressionStats // Counts dropped records for WithSuppressionReport, may be nil
+}
+
+// newSamplingHandler wraps a handler so that, for each level in rates, only 1 in N records is kept.
+// Levels that are absent or have a rate <= 1 are never sampled.
+// Dropped records are counted in suppressed when it is non-nil.
+func newSamplingHandler(handler slog.Handler, rates map[slog.Level]int, suppressed *suppressionStats) slog.Handler {
+	state := &samplingState{
+		rates:      make(map[slog.Level]uint64, len(rates)),
+		counters:   make(map[slog.Level]*atomic.Uint64, len(rates)),
+		suppressed: suppressed,
+	}
+	for level, rate := range rates {
+		if rate > 1 {
+			state.rates[level] = uint64(rate)
+			state.counters[level] = new(atomic.Uint64)
+		}
+	}
+	return &samplingHandler{handler: handler, state: state}
+}
+
+// keep reports whether a record at the given level passes sampling.
+// The first record of each level is kept, then every Nth one.
+func (s *samplingState) keep(level slog.Level) bool {
+	counter, ok := s.counters[level]
+	if !ok {
+		return true
+	}
+	return (counter.Add(1)-1)%s.rates[level] == 0
+}
+
+// Enabled implements slog.Handler
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.state.keep(r.Level) {
+		h.state.suppressed.add(SuppressedSampled)
+		return nil
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &samplingHandler{handler: h.handler.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements slog.Handler
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &samplingHandler{handler: h.handler.WithGroup(name), state: h.state}
+}
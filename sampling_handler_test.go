@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	t.Run("Per-level rates", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler := newSamplingHandler(
+			slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+			map[slog.Level]int{
+				slog.LevelDebug: 100,
+				slog.LevelInfo:  10,
+				slog.LevelError: 0,
+			},
+			nil,
+		)
+		logger := slog.New(handler)
+
+		for i := 0; i < 1000; i++ {
+			logger.Debug("debug")
+			logger.Info("info")
+			logger.Warn("warn")
+			logger.Error("error")
+		}
+
+		output := buf.String()
+		expected := map[string]int{
+			"level=DEBUG": 10,
+			"level=INFO":  100,
+			"level=WARN":  1000,
+			"level=ERROR": 1000,
+		}
+		for level, want := range expected {
+			if got := strings.Count(output, level); got != want {
+				t.Errorf("Expected %d kept records for %s, got %d", want, level, got)
+			}
+		}
+	})
+
+	t.Run("Derived handlers share counters", func(t *testing.T) {
+		var buf bytes.Buffer
+		handler := newSamplingHandler(slog.NewTextHandler(&buf, nil), map[slog.Level]int{slog.LevelInfo: 4}, nil)
+		base := slog.New(handler)
+		child := base.With("component", "child").WithGroup("g")
+
+		for i := 0; i < 4; i++ {
+			base.Info("base")
+			child.Info("child")
+		}
+
+		if got := strings.Count(buf.String(), "level=INFO"); got != 2 {
+			t.Errorf("Expected 2 kept records across derived handlers, got %d", got)
+		}
+	})
+
+	t.Run("Concurrent sampling", func(t *testing.T) {
+		handler := &mockHandler{enabled: true, output: &bytes.Buffer{}}
+		logger := slog.New(newSamplingHandler(handler, map[slog.Level]int{slog.LevelInfo: 10}, nil))
+
+		var wg sync.WaitGroup
+		for g := 0; g < 10; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					logger.Info("concurrent")
+				}
+			}()
+		}
+		wg.Wait()
+
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		if got := strings.Count(handler.output.String(), "concurrent"); got != 100 {
+			t.Errorf("Expected 100 kept records, got %d", got)
+		}
+	})
+
+	t.Run("Negative rate rejected", func(t *testing.T) {
+		cfg := DefaultConfig()
+		WithLevelSampling(map[slog.Level]int{slog.LevelInfo: -1})(cfg)
+		if err := validateConfig(cfg); err == nil {
+			t.Error("Expected error for negative sampling rate")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// WithScope returns a child logger carrying attrs and a cleanup function that ends the scope.
+// Until cleanup is called, every record logged through the child (or loggers derived from it)
+// includes attrs; afterwards they are omitted. Pair it with NewContext to make the scoped
+// logger available to downstream code. Closing the child is a no-op; close the parent instead.
+func (l *Logger) WithScope(attrs ...slog.Attr) (*Logger, func()) {
+	active := &atomic.Bool{}
+	active.Store(true)
+
+	inner := l.Handler()
+	child := &Logger{
+		Logger: slog.New(&scopeHandler{
+			scoped: inner.WithAttrs(attrs),
+			plain:  inner,
+			active: active,
+		}),
+		filePath:   l.filePath,
+		levelVar:   l.levelVar,
+		fileWriter: l.fileWriter,
+		profile:    l.profile,
+	}
+
+	var once sync.Once
+	return child, func() {
+		once.Do(func() { active.Store(false) })
+	}
+}
+
+// scopeHandler is a slog.Handler that includes scoped attributes only while the scope is active
+type scopeHandler struct {
+	scoped slog.Handler // Handler with the scoped attributes
+	plain  slog.Handler // Same handler chain without them
+	active *atomic.Bool
+}
+
+func (h *scopeHandler) current() slog.Handler {
+	if h.active.Load() {
+		return h.scoped
+	}
+	return h.plain
+}
+
+// Enabled implements slog.Handler
+func (h *scopeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.current().Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *scopeHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.current().Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *scopeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &scopeHandler{
+		scoped: h.scoped.WithAttrs(attrs),
+		plain:  h.plain.WithAttrs(attrs),
+		active: h.active,
+	}
+}
+
+// WithGroup implements slog.Handler
+func (h *scopeHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &scopeHandler{
+		scoped: h.scoped.WithGroup(name),
+		plain:  h.plain.WithGroup(name),
+		active: h.active,
+	}
+}
+
+// loggerKey is the context key for a Logger
+type loggerKey struct{}
+
+// NewContext returns a context carrying the logger
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext, or Default() if there is none
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return Default()
+}
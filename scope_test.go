@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithScope(t *testing.T) {
+	newTestLogger := func(buf *bytes.Buffer) *Logger {
+		return &Logger{Logger: slog.New(slog.NewTextHandler(buf, nil))}
+	}
+
+	t.Run("Scoped attrs appear and disappear", func(t *testing.T) {
+		var buf bytes.Buffer
+		parent := newTestLogger(&buf)
+
+		scoped, cleanup := parent.WithScope(slog.String("request_id", "r-1"))
+		derived := scoped.With("step", "parse")
+
+		scoped.Info("inside")
+		derived.Info("inside derived")
+		parent.Info("parent")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("Expected 3 lines, got %d: %q", len(lines), buf.String())
+		}
+		if !strings.Contains(lines[0], "request_id=r-1") {
+			t.Errorf("Expected scoped attr in scoped logger, got %q", lines[0])
+		}
+		if !strings.Contains(lines[1], "request_id=r-1") || !strings.Contains(lines[1], "step=parse") {
+			t.Errorf("Expected scoped attr in derived logger, got %q", lines[1])
+		}
+		if strings.Contains(lines[2], "request_id") {
+			t.Errorf("Expected no scoped attr in parent logger, got %q", lines[2])
+		}
+
+		buf.Reset()
+		cleanup()
+		cleanup() // Idempotent
+
+		scoped.Info("after")
+		derived.Info("after derived")
+
+		output := buf.String()
+		if strings.Contains(output, "request_id") {
+			t.Errorf("Expected scoped attr to disappear after cleanup, got %q", output)
+		}
+		if !strings.Contains(output, "step=parse") {
+			t.Errorf("Expected non-scoped attrs to be kept after cleanup, got %q", output)
+		}
+	})
+
+	t.Run("Scoped attrs keep their group position", func(t *testing.T) {
+		var buf bytes.Buffer
+		scoped, cleanup := newTestLogger(&buf).WithScope(slog.String("tenant", "acme"))
+		defer cleanup()
+
+		scoped.WithGroup("req").Info("grouped", "id", 7)
+
+		output := buf.String()
+		if !strings.Contains(output, "tenant=acme") || !strings.Contains(output, "req.id=7") {
+			t.Errorf("Expected ungrouped scoped attr and grouped call attr, got %q", output)
+		}
+	})
+
+	t.Run("Context integration", func(t *testing.T) {
+		var buf bytes.Buffer
+		scoped, cleanup := newTestLogger(&buf).WithScope(slog.String("request_id", "r-2"))
+		ctx := NewContext(context.Background(), scoped)
+
+		downstream := func(ctx context.Context) {
+			FromContext(ctx).Info("downstream")
+		}
+
+		downstream(ctx)
+		if !strings.Contains(buf.String(), "request_id=r-2") {
+			t.Errorf("Expected downstream code to get scoped attrs, got %q", buf.String())
+		}
+
+		buf.Reset()
+		cleanup()
+		downstream(ctx)
+		if strings.Contains(buf.String(), "request_id") {
+			t.Errorf("Expected scoped attrs to be gone after cleanup, got %q", buf.String())
+		}
+	})
+
+	t.Run("FromContext without logger", func(t *testing.T) {
+		if l := FromContext(context.Background()); l == nil || l.Logger == nil {
+			t.Error("Expected default logger from empty context")
+		}
+	})
+}
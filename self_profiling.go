@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// Stats reports the logger's own overhead measured with WithSelfProfiling
+type Stats struct {
+	Records uint64        // Records handled since the logger was created
+	Total   time.Duration // Time spent handling them
+	Average time.Duration // Exponentially weighted moving average of the time per record
+	Max     time.Duration // Slowest record
+}
+
+// profileEWMAShift sets the moving average weight of each new sample to 1/2^shift
+const profileEWMAShift = 3
+
+// handleProfile accumulates handling durations. All fields are updated atomically so
+// concurrent records never contend on a lock.
+type handleProfile struct {
+	records atomic.Uint64
+	total   atomic.Int64
+	ewma    atomic.Int64
+	max     atomic.Int64
+}
+
+// record adds one sample
+func (p *handleProfile) record(d time.Duration) {
+	n := int64(d)
+	p.records.Add(1)
+	p.total.Add(n)
+	for {
+		old := p.ewma.Load()
+		avg := n // The first sample seeds the average
+		if old != 0 {
+			avg = old + (n-old)>>profileEWMAShift
+		}
+		if p.ewma.CompareAndSwap(old, avg) {
+			break
+		}
+	}
+	for {
+		old := p.max.Load()
+		if n <= old || p.max.CompareAndSwap(old, n) {
+			break
+		}
+	}
+}
+
+// stats returns a snapshot, the zero Stats for a nil profile
+func (p *handleProfile) stats() Stats {
+	if p == nil {
+		return Stats{}
+	}
+	return Stats{
+		Records: p.records.Load(),
+		Total:   time.Duration(p.total.Load()),
+		Average: time.Duration(p.ewma.Load()),
+		Max:     time.Duration(p.max.Load()),
+	}
+}
+
+// callbackTimerKey is the context key of the callbackTimer of the record being handled
+type callbackTimerKey struct{}
+
+// callbackTimer collects the time a record spent in user callbacks, so it can be left out
+type callbackTimer struct {
+	spent atomic.Int64
+}
+
+// runCallback runs a user callback such as OnRecord or OnError. While self-profiling,
+// its duration is subtracted from the measurement so only the logger's own work counts.
+func runCallback(ctx context.Context, fn func()) {
+	t, _ := ctx.Value(callbackTimerKey{}).(*callbackTimer)
+	if t == nil {
+		fn()
+		return
+	}
+	start := time.Now()
+	fn()
+	t.spent.Add(int64(time.Since(start)))
+}
+
+// profilingHandler is a slog.Handler that measures how long handling each record takes
+type profilingHandler struct {
+	handler slog.Handler
+	profile *handleProfile
+}
+
+// newProfilingHandler wraps a handler so the duration of each Handle is added to profile
+func newProfilingHandler(handler slog.Handler, profile *handleProfile) slog.Handler {
+	return &profilingHandler{handler: handler, profile: profile}
+}
+
+// Enabled implements slog.Handler
+func (h *profilingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *profilingHandler) Handle(ctx context.Context, r slog.Record) error {
+	timer := &callbackTimer{}
+	start := time.Now()
+	err := h.handler.Handle(context.WithValue(ctx, callbackTimerKey{}, timer), r)
+	h.profile.record(time.Since(start) - time.Duration(timer.spent.Load()))
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *profilingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &profilingHandler{handler: h.handler.WithAttrs(attrs), profile: h.profile}
+}
+
+// WithGroup implements slog.Handler
+func (h *profilingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &profilingHandler{handler: h.handler.WithGroup(name), profile: h.profile}
+}
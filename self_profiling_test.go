@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithSelfProfiling(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(WithConsole(false), WithWriter(&buf, FormatCustom), WithSelfProfiling(true))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	for i := 0; i < 10; i++ {
+		log.Info("profiled", "i", i)
+	}
+	log.Debug("below the level, never handled")
+
+	stats := log.Stats()
+	if stats.Records != 10 {
+		t.Errorf("Expected 10 records, got %d", stats.Records)
+	}
+	if stats.Total <= 0 || stats.Average <= 0 || stats.Max <= 0 {
+		t.Errorf("Expected non-zero durations, got %+v", stats)
+	}
+	if stats.Max > stats.Total {
+		t.Errorf("Expected max %v to be at most total %v", stats.Max, stats.Total)
+	}
+}
+
+func TestWithSelfProfiling_ExcludesCallbacks(t *testing.T) {
+	const callbackDelay = 50 * time.Millisecond
+	var buf bytes.Buffer
+	log, err := New(
+		WithConsole(false),
+		WithWriter(&buf, FormatCustom),
+		WithSelfProfiling(true),
+		WithOnRecord(func(slog.Level, int) { time.Sleep(callbackDelay) }),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	log.Info("slow callback")
+	if stats := log.Stats(); stats.Records != 1 || stats.Max >= callbackDelay {
+		t.Errorf("Expected the OnRecord time to be left out, got %+v", stats)
+	}
+}
+
+func TestSelfProfiling_DisabledAndConcurrent(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(WithConsole(false), WithWriter(&buf, FormatJSON))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("not profiled")
+	log.Close()
+	if stats := log.Stats(); stats != (Stats{}) {
+		t.Errorf("Expected zero Stats without self-profiling, got %+v", stats)
+	}
+
+	profile := &handleProfile{}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				profile.record(time.Microsecond)
+			}
+		}()
+	}
+	wg.Wait()
+	stats := profile.stats()
+	if stats.Records != 800 || stats.Total != 800*time.Microsecond || stats.Max != time.Microsecond || stats.Average != time.Microsecond {
+		t.Errorf("Unexpected concurrent stats: %+v", stats)
+	}
+}
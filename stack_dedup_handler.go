@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StackIDKey is the attribute key for the short hash identifying a stack trace
+const StackIDKey = "stack_id"
+
+// stackDedupPruneSize is the number of remembered stacks above which expired entries are pruned
+const stackDedupPruneSize = 1024
+
+// stackDedupHandler is a slog.Handler that abbreviates stack traces repeated within a window
+type stackDedupHandler struct {
+	handler slog.Handler
+	state   *stackDedupState // Shared by handlers derived via WithAttrs/WithGroup
+}
+
+// stackDedupState remembers when each stack was last emitted in full
+type stackDedupState struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+}
+
+// newStackDedupHandler wraps a handler so a stack attribute identical to one emitted in full
+// within window is replaced by its hash
+func newStackDedupHandler(handler slog.Handler, window time.Duration) slog.Handler {
+	return &stackDedupHandler{
+		handler: handler,
+		state: &stackDedupState{
+			window: window,
+			seen:   make(map[string]time.Time),
+			now:    time.Now,
+		},
+	}
+}
+
+// repeated reports whether the stack with this id was emitted in full within the window,
+// recording a full emission otherwise
+func (s *stackDedupState) repeated(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if last, ok := s.seen[id]; ok && now.Sub(last) < s.window {
+		return true
+	}
+	if len(s.seen) >= stackDedupPruneSize {
+		for key, last := range s.seen {
+			if now.Sub(last) >= s.window {
+				delete(s.seen, key)
+			}
+		}
+	}
+	s.seen[id] = now
+	return false
+}
+
+// stackID returns a short stable hash of a stack trace
+func stackID(stack string) string {
+	h := fnv.New64a()
+	h.Write([]byte(stack))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// Enabled implements slog.Handler
+func (h *stackDedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+// The first occurrence keeps the full stack and gains a stack_id; repeats within the
+// window carry only the stack_id, with the stack attribute reduced to the same hash.
+func (h *stackDedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	hasStack := false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == StackKey && a.Value.Kind() == slog.KindString {
+			hasStack = true
+			return false
+		}
+		return true
+	})
+	if !hasStack {
+		return h.handler.Handle(ctx, r)
+	}
+
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == StackKey && a.Value.Kind() == slog.KindString {
+			id := stackID(a.Value.String())
+			if h.state.repeated(id) {
+				out.AddAttrs(slog.String(StackKey, id))
+			} else {
+				out.AddAttrs(a)
+			}
+			out.AddAttrs(slog.String(StackIDKey, id))
+			return true
+		}
+		out.AddAttrs(a)
+		return true
+	})
+	return h.handler.Handle(ctx, out)
+}
+
+// WithAttrs implements slog.Handler
+func (h *stackDedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &stackDedupHandler{handler: h.handler.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup implements slog.Handler
+func (h *stackDedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &stackDedupHandler{handler: h.handler.WithGroup(name), state: h.state}
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestStackDedup_RepeatAbbreviated(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newStackDedupHandler(slog.NewJSONHandler(&buf, nil), time.Minute)
+	logger := &Logger{Logger: slog.New(handler)}
+
+	// Same call site twice, so both panics have identical stacks
+	for i := 0; i < 2; i++ {
+		func() {
+			defer logger.Recover()
+			panicWithPayload()
+		}()
+	}
+
+	entries := decodeLines(t, &buf)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(entries))
+	}
+	first, second := entries[0], entries[1]
+
+	full, _ := first[StackKey].(string)
+	if !strings.Contains(full, "panicWithPayload") {
+		t.Errorf("Expected full stack on first occurrence, got %q", full)
+	}
+	id, _ := first[StackIDKey].(string)
+	if id == "" || id != stackID(full) {
+		t.Errorf("Expected stack_id %q on first occurrence, got %q", stackID(full), id)
+	}
+
+	if second[StackKey] != id || second[StackIDKey] != id {
+		t.Errorf("Expected repeat abbreviated to its hash %q, got stack=%v stack_id=%v", id, second[StackKey], second[StackIDKey])
+	}
+	if second[PanicTypeKey] != first[PanicTypeKey] {
+		t.Errorf("Expected other attributes preserved, got %v", second)
+	}
+}
+
+func TestStackDedup_WindowExpires(t *testing.T) {
+	var buf bytes.Buffer
+	h := newStackDedupHandler(slog.NewJSONHandler(&buf, nil), time.Second).(*stackDedupHandler)
+	now := time.Now()
+	h.state.now = func() time.Time { return now }
+	logger := slog.New(h)
+
+	logger.Error("e", StackKey, "main.go:1")
+	logger.Error("e", StackKey, "main.go:2") // Different stack, emitted in full
+	now = now.Add(2 * time.Second)
+	logger.Error("e", StackKey, "main.go:1") // Window expired, emitted in full again
+	logger.Error("e", StackKey, "main.go:1")
+	logger.Error("no stack")
+
+	entries := decodeLines(t, &buf)
+	want := []any{"main.go:1", "main.go:2", "main.go:1", stackID("main.go:1"), nil}
+	for i, w := range want {
+		if got := entries[i][StackKey]; got != w {
+			t.Errorf("Record %d: expected stack %v, got %v", i, w, got)
+		}
+	}
+	if _, ok := entries[4][StackIDKey]; ok {
+		t.Error("Expected no stack_id on records without a stack")
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+)
+
+// stackTraceHandler is a slog.Handler that adds a stack attribute to records at or above a level
+type stackTraceHandler struct {
+	handler slog.Handler
+	level   slog.Leveler
+	bound   bool // A stack attribute was added via WithAttrs in the current group
+}
+
+// newStackTraceHandler wraps a handler so records at level or above carry the stack of the
+// goroutine that logged them under StackKey
+func newStackTraceHandler(handler slog.Handler, level slog.Leveler) slog.Handler {
+	return &stackTraceHandler{handler: handler, level: level}
+}
+
+// recordStack formats the current stack from the frame that logged the record at pc,
+// leaving out the logger's and slog's own frames. It reports false without a pc or when
+// pc isn't on the current stack, e.g. for a dedup summary emitted by a timer or a record
+// handed off to another goroutine, whose stack would only show the logger's internals.
+func recordStack(pc uintptr) (string, bool) {
+	if pc == 0 {
+		return "", false
+	}
+	pcs := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(3, pcs)
+	i := slices.Index(pcs[:n], pc)
+	if i < 0 {
+		return "", false
+	}
+	return formatStack(pcs[i:n]), true
+}
+
+// hasStack reports whether the record already carries a stack, e.g. from Recover
+func hasStack(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == StackKey
+		return !found
+	})
+	return found
+}
+
+// Enabled implements slog.Handler
+func (h *stackTraceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *stackTraceHandler) Handle(ctx context.Context, r slog.Record) error {
+	// Only qualifying records pay for walking the stack
+	if r.Level >= h.level.Level() && !h.bound && !hasStack(r) {
+		if stack, ok := recordStack(r.PC); ok {
+			r = r.Clone()
+			r.AddAttrs(slog.String(StackKey, stack))
+		}
+	}
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *stackTraceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	bound := h.bound || slices.ContainsFunc(attrs, func(a slog.Attr) bool { return a.Key == StackKey })
+	return &stackTraceHandler{handler: h.handler.WithAttrs(attrs), level: h.level, bound: bound}
+}
+
+// WithGroup implements slog.Handler
+func (h *stackTraceHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	// A stack added to the record would land in the group, next to no other stack
+	return &stackTraceHandler{handler: h.handler.WithGroup(name), level: h.level}
+}
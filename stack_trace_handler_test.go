@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+//go:noinline
+func chargeCard(log *Logger) {
+	log.Error("payment failed", "order", 42)
+}
+
+func TestWithStackTrace(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(WithConsole(false), WithWriter(&buf, FormatJSON), WithStackTrace(slog.LevelError))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	log.Warn("below the level")
+	chargeCard(log)
+	log.Error("recovered elsewhere", StackKey, "kept as is")
+
+	entries := decodeJSONLines(t, &buf)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(entries))
+	}
+	if _, ok := entries[0][StackKey]; ok {
+		t.Errorf("Expected no stack below the level, got %v", entries[0])
+	}
+
+	stack, ok := entries[1][StackKey].(string)
+	if !ok {
+		t.Fatalf("Expected a string stack attribute, got %v", entries[1][StackKey])
+	}
+	first, _, _ := strings.Cut(stack, "\n")
+	if !strings.HasSuffix(first, ".chargeCard") {
+		t.Errorf("Expected the stack to start at the calling function, got:\n%s", stack)
+	}
+	if !strings.Contains(stack, "stack_trace_handler_test.go:") || !strings.Contains(stack, ".TestWithStackTrace") {
+		t.Errorf("Expected the stack to reference the caller's file and the test, got:\n%s", stack)
+	}
+	if strings.Contains(stack, "log/slog.") || strings.Contains(stack, "stackTraceHandler") {
+		t.Errorf("Expected the logger's own frames to be left out, got:\n%s", stack)
+	}
+
+	if entries[2][StackKey] != "kept as is" {
+		t.Errorf("Expected an existing stack attribute to be kept, got %v", entries[2][StackKey])
+	}
+}
+
+func TestWithStackTrace_BoundAndHandedOff(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(
+		WithConsole(false),
+		WithWriter(&buf, FormatJSON),
+		WithStackTrace(slog.LevelError),
+		WithDedup(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	// A stack bound via With is not duplicated, unless a group was opened since
+	log.With(StackKey, "bound").Error("with stack")
+	log.With(StackKey, "bound").WithGroup("g").Error("in group")
+	// The dedup summary is emitted on Close, away from any logging call
+	log.Error("repeated")
+	log.Error("repeated")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 records, got %d:\n%s", len(lines), out)
+	}
+	if n := strings.Count(lines[0], `"stack":`); n != 1 || !strings.Contains(lines[0], `"stack":"bound"`) {
+		t.Errorf("Expected only the bound stack, got %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"g":{"stack":"`) {
+		t.Errorf("Expected a captured stack in the group, got %s", lines[1])
+	}
+	entries := decodeJSONLines(t, &buf)
+	if entries[3]["msg"] != "last message repeated 1 times" {
+		t.Fatalf("Expected the dedup summary last, got %v", entries[3])
+	}
+	if stack, ok := entries[3][StackKey]; ok {
+		t.Errorf("Expected no stack on a record emitted away from its logging call, got:\n%v", stack)
+	}
+
+	if _, ok := recordStack(0); ok {
+		t.Error("Expected no stack without a PC")
+	}
+	if _, ok := recordStack(0x1); ok {
+		t.Error("Expected no stack for a PC that isn't on the stack")
+	}
+}
+
+func TestWithStackTrace_CustomFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(WithConsole(false), WithWriter(&buf, FormatCustom), WithStackTrace(slog.LevelWarn))
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	log.Info("quiet")
+	log.Warn("loud")
+	out := buf.String()
+	lines := strings.SplitN(out, "\n", 2)
+	if strings.Contains(lines[0], "stack=") {
+		t.Errorf("Expected no stack on the Info line, got %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "stack=") || !strings.Contains(lines[1], ".TestWithStackTrace_CustomFormat") {
+		t.Errorf("Expected a stack on the Warn line, got %q", lines[1])
+	}
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultSuppressionReportMessage is the message of suppression report records
+const DefaultSuppressionReportMessage = "logs suppressed"
+
+// SuppressedSampled is the suppression report reason for records dropped by WithLevelSampling
+const SuppressedSampled = "sampled"
+
+// suppressionStats counts dropped records per reason, shared by every decorator that drops records.
+// A nil *suppressionStats ignores drops, so decorators need no check when reporting is disabled.
+type suppressionStats struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// add records a dropped record for reason
+func (s *suppressionStats) add(reason string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.counts == nil {
+		s.counts = make(map[string]uint64)
+	}
+	s.counts[reason]++
+	s.mu.Unlock()
+}
+
+// swap returns the counts so far and starts a new interval
+func (s *suppressionStats) swap() map[string]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := s.counts
+	s.counts = nil
+	return counts
+}
+
+// suppressionReport periodically logs how many records were dropped in the last interval.
+// Reports go to internalLogger so they never pass through the handlers doing the dropping.
+type suppressionReport struct {
+	stats    *suppressionStats
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// startSuppressionReport starts a goroutine reporting stats every interval
+func startSuppressionReport(stats *suppressionStats, interval time.Duration) *suppressionReport {
+	sr := &suppressionReport{
+		stats:    stats,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go sr.run()
+	return sr
+}
+
+func (sr *suppressionReport) run() {
+	defer close(sr.done)
+
+	ticker := time.NewTicker(sr.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sr.emit()
+		case <-sr.stop:
+			// Report what was dropped since the last tick rather than losing it
+			sr.emit()
+			return
+		}
+	}
+}
+
+// emit logs one record with a count per reason and their total.
+// Intervals in which nothing was dropped are not reported.
+func (sr *suppressionReport) emit() {
+	counts := sr.stats.swap()
+	if len(counts) == 0 {
+		return
+	}
+	var total uint64
+	dropped := make([]any, 0, len(counts))
+	for _, reason := range slices.Sorted(maps.Keys(counts)) {
+		total += counts[reason]
+		dropped = append(dropped, slog.Uint64(reason, counts[reason]))
+	}
+	internalLogger.Info(DefaultSuppressionReportMessage,
+		slog.Duration("interval", sr.interval),
+		slog.Uint64("total", total),
+		slog.Group("dropped", dropped...),
+	)
+}
+
+// Close stops the report goroutine, emitting a final report, and waits for it to exit
+func (sr *suppressionReport) Close() error {
+	sr.once.Do(func() {
+		close(sr.stop)
+	})
+	<-sr.done
+	return nil
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureInternalLogger redirects internalLogger to a JSON buffer for the duration of the test
+func captureInternalLogger(t *testing.T) *safeBuffer {
+	t.Helper()
+	buf := &safeBuffer{}
+	original := internalLogger
+	internalLogger = slog.New(slog.NewJSONHandler(buf, nil))
+	t.Cleanup(func() { internalLogger = original })
+	return buf
+}
+
+// suppressionReports decodes the suppression report records written to buf
+func suppressionReports(t *testing.T, buf *safeBuffer) []map[string]any {
+	t.Helper()
+	var reports []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse JSON line %q: %v", line, err)
+		}
+		if entry["msg"] == DefaultSuppressionReportMessage {
+			reports = append(reports, entry)
+		}
+	}
+	return reports
+}
+
+// sampledCount sums the sampled counters across reports, checking each total matches
+func sampledCount(t *testing.T, reports []map[string]any) int {
+	t.Helper()
+	sum := 0
+	for _, r := range reports {
+		dropped, _ := r["dropped"].(map[string]any)
+		sampled, _ := dropped[SuppressedSampled].(float64)
+		if total, _ := r["total"].(float64); total != sampled {
+			t.Errorf("Expected total %v to equal the sampled count %v", total, sampled)
+		}
+		sum += int(sampled)
+	}
+	return sum
+}
+
+func TestWithSuppressionReport(t *testing.T) {
+	buf := captureInternalLogger(t)
+
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(filepath.Join(t.TempDir(), "app.log")),
+		WithLevelSampling(map[slog.Level]int{slog.LevelInfo: 4}),
+		WithSuppressionReport(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	defer log.Close()
+
+	// 1 in 4 kept, so 6 of 8 records are dropped
+	for i := 0; i < 8; i++ {
+		log.Info("sampled")
+	}
+	log.Warn("not sampled")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for sampledCount(t, suppressionReports(t, buf)) < 6 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reports := suppressionReports(t, buf)
+	if got := sampledCount(t, reports); got != 6 {
+		t.Fatalf("Expected 6 sampled records reported, got %d in %v", got, reports)
+	}
+	if interval, _ := reports[0]["interval"].(float64); time.Duration(interval) != 20*time.Millisecond {
+		t.Errorf("Expected interval 20ms, got %v", reports[0]["interval"])
+	}
+}
+
+func TestSuppressionReport_FinalReportOnClose(t *testing.T) {
+	buf := captureInternalLogger(t)
+
+	stats := &suppressionStats{}
+	report := startSuppressionReport(stats, time.Hour)
+	logger := slog.New(newSamplingHandler(&mockHandler{enabled: true}, map[slog.Level]int{slog.LevelDebug: 10}, stats))
+
+	for i := 0; i < 10; i++ {
+		logger.Debug("sampled")
+	}
+	if err := report.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	reports := suppressionReports(t, buf)
+	if len(reports) != 1 {
+		t.Fatalf("Expected a single report on Close, got %d", len(reports))
+	}
+	if got := sampledCount(t, reports); got != 9 {
+		t.Errorf("Expected 9 sampled records reported, got %d", got)
+	}
+
+	// Close is idempotent and nothing new was dropped
+	report.Close()
+	if n := len(suppressionReports(t, buf)); n != 1 {
+		t.Errorf("Expected no report for an interval without drops, got %d reports", n)
+	}
+}
+
+func TestSuppressionReport_NegativeIntervalRejected(t *testing.T) {
+	cfg := DefaultConfig()
+	WithSuppressionReport(-time.Second)(cfg)
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected error for negative suppression report interval")
+	}
+}
@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogFacilityUser is the RFC5424 "user-level messages" facility
+	syslogFacilityUser = 1
+
+	// syslogSDID names the structured data element carrying the record's attributes.
+	// 32473 is the private enterprise number RFC5612 reserves for documentation.
+	syslogSDID = "attrs@32473"
+
+	// syslogTimeFormat is the RFC5424 timestamp, which allows at most six fractional digits
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// syslogHeaderField returns s as an RFC5424 header field: printable ASCII without
+// spaces, at most max bytes, or the NILVALUE "-" when empty
+func syslogHeaderField(s string, max int) string {
+	var b strings.Builder
+	for i := 0; i < len(s) && b.Len() < max; i++ {
+		if c := s[i]; c > ' ' && c < 0x7f {
+			b.WriteByte(c)
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// syslogParamName converts an attribute key into a valid SD-NAME: printable ASCII
+// other than '=', ' ', ']' and '"', at most 32 bytes
+func syslogParamName(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key) && b.Len() < 32; i++ {
+		c := key[i]
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b.WriteByte(c)
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// syslogParamEscaper escapes the characters RFC5424 reserves inside PARAM-VALUE
+var syslogParamEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogLineEscaper keeps multi-line messages on a single line
+var syslogLineEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// syslogHandler is a slog.Handler that writes RFC5424 syslog lines:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID - [attrs@32473 key="value" ...] MSG
+//
+// Severities follow the journald priority mapping and attributes, with groups
+// flattened using dots, become parameters of a single structured data element.
+type syslogHandler struct {
+	mu       *sync.Mutex
+	w        io.Writer
+	opts     slog.HandlerOptions
+	hostname string
+	appName  string
+	procID   string
+	attrs    flatAttrs // Groups and SD-PARAMs from WithAttrs/WithGroup
+}
+
+// newSyslogHandler creates a handler writing RFC5424 lines to w
+func newSyslogHandler(w io.Writer, opts *slog.HandlerOptions) *syslogHandler {
+	h := &syslogHandler{
+		mu:       &sync.Mutex{},
+		w:        w,
+		hostname: syslogHeaderField(hostname(), 255),
+		appName:  syslogHeaderField(filepath.Base(os.Args[0]), 48),
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	h.attrs = newFlatAttrs(h.opts.ReplaceAttr, appendSyslogAttr)
+	return h
+}
+
+// Enabled implements slog.Handler
+func (h *syslogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *syslogHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(syslogFacilityUser*8 + journaldPriority(r.Level)))
+	buf.WriteString(">1 ")
+	if r.Time.IsZero() {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteString(r.Time.Format(syslogTimeFormat))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(h.hostname)
+	buf.WriteByte(' ')
+	buf.WriteString(h.appName)
+	buf.WriteByte(' ')
+	buf.WriteString(h.procID)
+	buf.WriteString(" - ")
+
+	var params bytes.Buffer
+	if h.opts.AddSource {
+		if src := explicitSource(r); src != nil {
+			appendSyslogParam(&params, slog.SourceKey, fmt.Sprintf("%s:%d", src.File, src.Line))
+		} else if r.PC != 0 {
+			f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+			appendSyslogParam(&params, slog.SourceKey, fmt.Sprintf("%s:%d", f.File, f.Line))
+		}
+	}
+	h.attrs.appendRecord(&params, r)
+	if params.Len() == 0 {
+		buf.WriteByte('-')
+	} else {
+		buf.WriteString("[" + syslogSDID)
+		buf.Write(params.Bytes())
+		buf.WriteByte(']')
+	}
+
+	if r.Message != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(syslogLineEscaper.Replace(r.Message))
+	}
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// appendSyslogParam appends a single ` name="value"` SD-PARAM
+func appendSyslogParam(buf *bytes.Buffer, name, value string) {
+	buf.WriteByte(' ')
+	buf.WriteString(syslogParamName(name))
+	buf.WriteString(`="`)
+	buf.WriteString(syslogParamEscaper.Replace(syslogLineEscaper.Replace(value)))
+	buf.WriteByte('"')
+}
+
+// appendSyslogAttr encodes a flattened attribute as an SD-PARAM
+func appendSyslogAttr(buf *bytes.Buffer, name string, v slog.Value) {
+	var value string
+	switch v.Kind() {
+	case slog.KindTime:
+		value = v.Time().Format(time.RFC3339Nano)
+	default:
+		value = fmt.Sprintf("%v", v.Any())
+	}
+	appendSyslogParam(buf, name, value)
+}
+
+// WithAttrs implements slog.Handler
+func (h *syslogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	newHandler := *h
+	newHandler.attrs = h.attrs.withAttrs(attrs)
+	return &newHandler
+}
+
+// WithGroup implements slog.Handler
+func (h *syslogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	newHandler := *h
+	newHandler.attrs = h.attrs.withGroup(name)
+	return &newHandler
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc5424Line matches a syslog line as specified by RFC5424 section 6, capturing each field
+var rfc5424Line = regexp.MustCompile(`^<(\d{1,3})>1 ` +
+	`(-|\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d{1,6})?(?:Z|[+-]\d{2}:\d{2})) ` +
+	`([!-~]{1,255}) ([!-~]{1,48}) ([!-~]{1,128}) ([!-~]{1,32}) ` +
+	`(-|(?:\[[^ =\]"]{1,32}(?: [^ =\]"]{1,32}="(?:[^"\\\]]|\\["\\\]])*")*\])+)` +
+	`(?: (.*))?$`)
+
+// rfc5424Param matches one SD-PARAM inside a structured data element
+var rfc5424Param = regexp.MustCompile(`([^ =\]"]{1,32})="((?:[^"\\\]]|\\["\\\]])*)"`)
+
+// parseRFC5424 parses a syslog line, returning the priority, structured data parameters and message
+func parseRFC5424(t *testing.T, line string) (int, map[string]string, string) {
+	t.Helper()
+	m := rfc5424Line.FindStringSubmatch(line)
+	if m == nil {
+		t.Fatalf("Line is not valid RFC5424: %q", line)
+	}
+	pri, _ := strconv.Atoi(m[1])
+	params := make(map[string]string)
+	if m[7] != "-" {
+		for _, p := range rfc5424Param.FindAllStringSubmatch(m[7], -1) {
+			params[p[1]] = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\]`, `]`).Replace(p[2])
+		}
+	}
+	if want := strconv.Itoa(os.Getpid()); m[5] != want {
+		t.Errorf("Expected PROCID %s, got %s", want, m[5])
+	}
+	if want := syslogHeaderField(hostname(), 255); m[3] != want {
+		t.Errorf("Expected HOSTNAME %s, got %s", want, m[3])
+	}
+	return pri, params, m[8]
+}
+
+func TestSyslogHandler_RFC5424(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newSyslogHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger.With("service", "api").WithGroup("req").Warn("slow request",
+		"path", `/a"b]c\d`,
+		"took", 1500*time.Millisecond,
+	)
+	logger.Debug("no attrs")
+	logger.Error("multi\nline", "bad key", 1)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	pri, params, msg := parseRFC5424(t, lines[0])
+	if pri != 8+4 {
+		t.Errorf("Expected PRI 12 (user.warning), got %d", pri)
+	}
+	if msg != "slow request" {
+		t.Errorf("Expected message %q, got %q", "slow request", msg)
+	}
+	want := map[string]string{"service": "api", "req.path": `/a"b]c\d`, "req.took": "1.5s"}
+	for k, v := range want {
+		if params[k] != v {
+			t.Errorf("Expected param %s=%q, got %q (all: %v)", k, v, params[k], params)
+		}
+	}
+
+	pri, params, msg = parseRFC5424(t, lines[1])
+	if pri != 8+7 || len(params) != 0 || msg != "no attrs" {
+		t.Errorf("Unexpected debug line fields: pri=%d params=%v msg=%q", pri, params, msg)
+	}
+
+	pri, params, msg = parseRFC5424(t, lines[2])
+	if pri != 8+3 || msg != `multi\nline` || params["bad_key"] != "1" {
+		t.Errorf("Unexpected error line fields: pri=%d params=%v msg=%q", pri, params, msg)
+	}
+}
+
+func TestSyslogHandler_FileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := New(
+		WithConsole(false),
+		WithFilePath(path),
+		WithFileFormat(FormatSyslog),
+		WithAddSource(true),
+	)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	log.Info("hello", "user", "alice")
+	log.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	pri, params, msg := parseRFC5424(t, strings.TrimSuffix(string(data), "\n"))
+	if pri != 8+6 || msg != "hello" || params["user"] != "alice" {
+		t.Errorf("Unexpected file line fields: pri=%d params=%v msg=%q", pri, params, msg)
+	}
+	if !strings.Contains(params[slog.SourceKey], "syslog_handler_test.go:") {
+		t.Errorf("Expected source parameter pointing at the test file, got %q", params[slog.SourceKey])
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// dialSyslog reports that syslog is unavailable on this platform
+func dialSyslog(priority int, tag string) (syslogWriter, error) {
+	return nil, fmt.Errorf("syslog logging is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,30 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+// WithSyslog sends records to the local syslog daemon through log/syslog. priority sets the
+// facility, e.g. syslog.LOG_LOCAL0; its severity bits are ignored because each record's level
+// picks one: Debug→LOG_DEBUG, Info→LOG_INFO, Warn→LOG_WARNING, Error→LOG_ERR and levels above
+// Error→LOG_CRIT. An empty tag uses the program name. Creating the logger fails when no syslog
+// daemon is listening. Not available on Windows and Plan 9.
+func WithSyslog(priority syslog.Priority, tag string) Option {
+	return func(c *Config) {
+		c.Syslog.Enabled = true
+		c.Syslog.Priority = int(priority)
+		c.Syslog.Tag = tag
+	}
+}
+
+// dialSyslog connects to the local syslog daemon
+func dialSyslog(priority int, tag string) (syslogWriter, error) {
+	w, err := syslog.New(syslog.Priority(priority), tag)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to syslog: %w", err)
+	}
+	return w, nil
+}
@@ -0,0 +1,106 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"log/syslog"
+	"testing"
+)
+
+// syslogMessage is one message received by mockSyslogWriter
+type syslogMessage struct {
+	severity syslog.Priority
+	msg      string
+}
+
+// mockSyslogWriter records messages with the severity of the method they were sent through
+type mockSyslogWriter struct {
+	messages []syslogMessage
+	err      error // Returned by every send when set
+}
+
+func (w *mockSyslogWriter) log(severity syslog.Priority, m string) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, syslogMessage{severity, m})
+	return nil
+}
+
+func (w *mockSyslogWriter) Debug(m string) error   { return w.log(syslog.LOG_DEBUG, m) }
+func (w *mockSyslogWriter) Info(m string) error    { return w.log(syslog.LOG_INFO, m) }
+func (w *mockSyslogWriter) Warning(m string) error { return w.log(syslog.LOG_WARNING, m) }
+func (w *mockSyslogWriter) Err(m string) error     { return w.log(syslog.LOG_ERR, m) }
+func (w *mockSyslogWriter) Crit(m string) error    { return w.log(syslog.LOG_CRIT, m) }
+func (w *mockSyslogWriter) Close() error           { return nil }
+
+func TestSyslogDestHandler_SeverityMapping(t *testing.T) {
+	w := &mockSyslogWriter{}
+	logger := slog.New(newSyslogDestHandler(w, slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Warn("w")
+	logger.Error("e")
+	logger.Log(context.Background(), slog.LevelError+4, "fatal")
+
+	want := []syslogMessage{
+		{syslog.LOG_DEBUG, "d"},
+		{syslog.LOG_INFO, "i"},
+		{syslog.LOG_WARNING, "w"},
+		{syslog.LOG_ERR, "e"},
+		{syslog.LOG_CRIT, "fatal"},
+	}
+	if len(w.messages) != len(want) {
+		t.Fatalf("Expected %d messages, got %v", len(want), w.messages)
+	}
+	for i, m := range want {
+		if w.messages[i] != m {
+			t.Errorf("Message %d: expected %v, got %v", i, m, w.messages[i])
+		}
+	}
+}
+
+func TestSyslogDestHandler_OnError(t *testing.T) {
+	w := &mockSyslogWriter{err: errors.New("daemon gone")}
+	h := newSyslogDestHandler(w, slog.HandlerOptions{})
+	var errs []error
+	h.onError = func(err error) { errs = append(errs, err) }
+
+	slog.New(h).With("k", "v").Info("lost")
+	if len(errs) != 1 || errs[0] != w.err {
+		t.Errorf("Expected the send error to be reported, got %v", errs)
+	}
+}
+
+func TestSyslogDestHandler_Attributes(t *testing.T) {
+	w := &mockSyslogWriter{}
+	logger := slog.New(newSyslogDestHandler(w, slog.HandlerOptions{}))
+
+	logger.With("service", "api").WithGroup("req").Info("done", "path", "/login", "note", "two words")
+	logger.Debug("filtered")
+
+	if len(w.messages) != 1 {
+		t.Fatalf("Expected 1 message, got %v", w.messages)
+	}
+	want := `done service=api req.path=/login req.note="two words"`
+	if got := w.messages[0].msg; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWithSyslog(t *testing.T) {
+	cfg := DefaultConfig()
+	WithConsole(false)(cfg)
+	WithSyslog(syslog.LOG_LOCAL0, "myapp")(cfg)
+
+	if !cfg.Syslog.Enabled || cfg.Syslog.Priority != int(syslog.LOG_LOCAL0) || cfg.Syslog.Tag != "myapp" {
+		t.Errorf("Unexpected syslog config: %+v", cfg.Syslog)
+	}
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("Expected syslog-only config to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// syslogWriter is the subset of *syslog.Writer used by the syslog destination,
+// one method per severity so each record keeps its own
+type syslogWriter interface {
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+	Crit(m string) error
+	io.Closer
+}
+
+// syslogDestHandler is a slog.Handler that sends records to a syslog daemon.
+// The daemon adds the timestamp, host and tag, so each message is only the record's
+// message followed by its attributes as key=value pairs, with groups joined by dots.
+type syslogDestHandler struct {
+	w       syslogWriter
+	opts    slog.HandlerOptions
+	onError func(err error) // Receives failed sends, nil when no ErrorHandler is set
+	attrs   flatAttrs       // Groups and pairs from WithAttrs/WithGroup
+}
+
+// newSyslogDestination connects to the local syslog daemon and returns a handler and its closer
+func newSyslogDestination(cfg *Config) (slog.Handler, io.Closer, error) {
+	w, err := dialSyslog(cfg.Syslog.Priority, cfg.Syslog.Tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	h := newSyslogDestHandler(w, *newHandlerOptions(cfg))
+	if cfg.ErrorHandler != nil {
+		h.onError = cfg.reportError
+	}
+	return h, w, nil
+}
+
+// newSyslogDestHandler creates a syslog handler over an already connected writer
+func newSyslogDestHandler(w syslogWriter, opts slog.HandlerOptions) *syslogDestHandler {
+	if opts.Level == nil {
+		opts.Level = slog.LevelInfo
+	}
+	return &syslogDestHandler{w: w, opts: opts, attrs: newFlatAttrs(opts.ReplaceAttr, appendSyslogDestAttr)}
+}
+
+// Enabled implements slog.Handler
+func (h *syslogDestHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *syslogDestHandler) Handle(_ context.Context, r slog.Record) error {
+	var b bytes.Buffer
+	b.WriteString(r.Message)
+	if h.opts.AddSource {
+		if src := explicitSource(r); src != nil {
+			h.appendSource(&b, src.File, src.Line)
+		} else if r.PC != 0 {
+			f, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+			h.appendSource(&b, f.File, f.Line)
+		}
+	}
+	h.attrs.appendRecord(&b, r)
+
+	msg := b.String()
+	var err error
+	switch journaldPriority(r.Level) {
+	case journaldPriorityDebug:
+		err = h.w.Debug(msg)
+	case journaldPriorityInfo:
+		err = h.w.Info(msg)
+	case journaldPriorityWarning:
+		err = h.w.Warning(msg)
+	case journaldPriorityErr:
+		err = h.w.Err(msg)
+	default:
+		err = h.w.Crit(msg)
+	}
+	if err != nil && h.onError != nil {
+		h.onError(err)
+	}
+	return err
+}
+
+// appendSource appends the caller as " source=file.go:line"
+func (h *syslogDestHandler) appendSource(b *bytes.Buffer, file string, line int) {
+	b.WriteString(" " + slog.SourceKey + "=")
+	b.WriteString(filepath.Base(file) + ":" + strconv.Itoa(line))
+}
+
+// appendSyslogDestAttr encodes a flattened attribute as " key=value"
+func appendSyslogDestAttr(b *bytes.Buffer, key string, v slog.Value) {
+	b.WriteByte(' ')
+	b.WriteString(key)
+	b.WriteByte('=')
+	value := formatAttrValue(v, false)
+	if strings.ContainsAny(value, " \"=\n") {
+		value = strconv.Quote(value)
+	}
+	b.WriteString(value)
+}
+
+// WithAttrs implements slog.Handler
+func (h *syslogDestHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	newHandler := *h
+	newHandler.attrs = h.attrs.withAttrs(attrs)
+	return &newHandler
+}
+
+// WithGroup implements slog.Handler
+func (h *syslogDestHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	newHandler := *h
+	newHandler.attrs = h.attrs.withGroup(name)
+	return &newHandler
+}
@@ -0,0 +1,13 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// isTerminalWriter reports whether w is a file attached to a terminal, so ANSI codes written
+// to it are displayed rather than captured into a file or pipe
+func isTerminalWriter(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f.Fd())
+}
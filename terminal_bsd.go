@@ -0,0 +1,8 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import "syscall"
+
+// ioctlGetTermios is the ioctl request reading terminal attributes
+const ioctlGetTermios = syscall.TIOCGETA
@@ -0,0 +1,6 @@
+package logger
+
+import "syscall"
+
+// ioctlGetTermios is the ioctl request reading terminal attributes
+const ioctlGetTermios = syscall.TCGETS
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package logger
+
+// isTerminal reports false where terminals can't be detected, so automatic color stays off
+func isTerminal(fd uintptr) bool {
+	return false
+}
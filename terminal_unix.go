@@ -0,0 +1,15 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether fd refers to a terminal, i.e. it has terminal attributes
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlGetTermios, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
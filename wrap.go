@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// wrapIndent is the continuation indent for soft-wrapped lines
+const wrapIndent = "    "
+
+// visibleWidth returns the number of runes in s, ignoring ANSI escape sequences
+func visibleWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := ansiSequenceLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		width++
+	}
+	return width
+}
+
+// ansiSequenceLen returns the byte length of the ANSI CSI sequence at the start of s, or 0
+func ansiSequenceLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if c := s[i]; c >= 0x40 && c <= 0x7e {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// wrapLine soft-wraps s at width visible columns, breaking at spaces where possible and
+// indenting continuation lines. ANSI escape sequences do not count towards the width.
+func wrapLine(s string, width int) string {
+	if width <= len(wrapIndent) || visibleWidth(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/width*(len(wrapIndent)+1))
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		wrapSegment(&b, line, width)
+	}
+	return b.String()
+}
+
+// wrapSegment greedily wraps a single line without newlines
+func wrapSegment(b *strings.Builder, line string, width int) {
+	col := 0
+	for i, word := range strings.Split(line, " ") {
+		w := visibleWidth(word)
+		if i > 0 {
+			if col+1+w > width && col > len(wrapIndent) {
+				b.WriteByte('\n')
+				b.WriteString(wrapIndent)
+				col = len(wrapIndent)
+			} else {
+				b.WriteByte(' ')
+				col++
+			}
+		}
+		// Hard-split words that don't fit on a line of their own
+		for col+w > width {
+			head, rest := splitVisible(word, width-col)
+			b.WriteString(head)
+			b.WriteByte('\n')
+			b.WriteString(wrapIndent)
+			col = len(wrapIndent)
+			word = rest
+			w = visibleWidth(word)
+		}
+		b.WriteString(word)
+		col += w
+	}
+}
+
+// splitVisible splits s after n visible runes, keeping escape sequences intact
+func splitVisible(s string, n int) (string, string) {
+	count := 0
+	for i := 0; i < len(s); {
+		if l := ansiSequenceLen(s[i:]); l > 0 {
+			i += l
+			continue
+		}
+		if count == n {
+			return s[:i], s[i:]
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		count++
+	}
+	return s, ""
+}
+
+// lineEllipsis marks a line cut short by MaxLineBytes
+const lineEllipsis = "…"
+
+// truncateLine cuts s to at most n bytes, ending with an ellipsis. Cuts never split a UTF-8
+// rune or an ANSI escape sequence, and a reset is kept so colors don't leak past the cut.
+func truncateLine(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+
+	colored := strings.Contains(s, "\033[")
+	suffix := lineEllipsis
+	if colored {
+		suffix = ansiReset + lineEllipsis
+	}
+	keepSequences := true
+	if len(suffix) > n {
+		// Too short for the ellipsis: keep as many whole runes as fit, followed by the
+		// reset when it fits, or without any escape sequence when it doesn't
+		suffix = ""
+		if colored {
+			if n >= len(ansiReset) {
+				suffix = ansiReset
+			} else {
+				keepSequences = false
+			}
+		}
+	}
+	budget := n - len(suffix)
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		size := ansiSequenceLen(s[i:])
+		if size > 0 && !keepSequences {
+			i += size
+			continue
+		}
+		if size == 0 {
+			_, size = utf8.DecodeRuneInString(s[i:])
+		}
+		if b.Len()+size > budget {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		i += size
+	}
+	return b.String() + suffix
+}
@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestVisibleWidth(t *testing.T) {
+	tests := map[string]int{
+		"":                                   0,
+		"plain":                              5,
+		ansiBrightGreen + "INFO" + ansiReset: 4,
+		"héllo":                              5,
+		"\033[91;2mkey\033[0m=value":         9,
+	}
+	for s, want := range tests {
+		if got := visibleWidth(s); got != want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	t.Run("Short line unchanged", func(t *testing.T) {
+		if got := wrapLine("short line", 20); got != "short line" {
+			t.Errorf("Expected unchanged line, got %q", got)
+		}
+	})
+
+	t.Run("Breaks at spaces with indent", func(t *testing.T) {
+		got := wrapLine("aaaa bbbb cccc dddd eeee", 10)
+		want := "aaaa bbbb\n    cccc\n    dddd\n    eeee"
+		if got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("Hard splits long words", func(t *testing.T) {
+		got := wrapLine(strings.Repeat("x", 25), 10)
+		for _, line := range strings.Split(got, "\n") {
+			if visibleWidth(line) > 10 {
+				t.Errorf("Line %q exceeds width", line)
+			}
+		}
+		if strings.ReplaceAll(strings.ReplaceAll(got, "\n", ""), " ", "") != strings.Repeat("x", 25) {
+			t.Errorf("Expected content to be preserved, got %q", got)
+		}
+	})
+
+	t.Run("Ignores ANSI codes", func(t *testing.T) {
+		colored := ansiBrightGreen + "INFO" + ansiReset + " " + ansiFaint + "key" + ansiReset + "=value more text here"
+		got := wrapLine(colored, 20)
+		for _, line := range strings.Split(got, "\n") {
+			if w := visibleWidth(line); w > 20 {
+				t.Errorf("Line %q has visible width %d > 20", line, w)
+			}
+		}
+		if !strings.HasPrefix(got, ansiBrightGreen+"INFO"+ansiReset+" "+ansiFaint+"key"+ansiReset+"=value") {
+			t.Errorf("Expected escape sequences to be kept intact, got %q", got)
+		}
+	})
+}
+
+func TestCustomHandler_WrapWidth(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := newCustomHandler(&buf, DefaultConfig(), &mockOutputConfig{
+		format:    FormatCustom,
+		color:     true,
+		formatter: "{level} {message} {attrs}",
+		wrapWidth: 30,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	slog.New(handler).Info("a fairly long message that needs wrapping", "key", "value", "other", "attribute")
+
+	output := buf.String()
+	if !strings.HasSuffix(output, "\n") {
+		t.Errorf("Expected trailing newline, got %q", output)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("Expected wrapped output, got %q", output)
+	}
+	for i, line := range lines {
+		if w := visibleWidth(line); w > 30 {
+			t.Errorf("Line %d %q has visible width %d > 30", i, line, w)
+		}
+		if i > 0 && !strings.HasPrefix(line, wrapIndent) {
+			t.Errorf("Expected continuation indent on line %d: %q", i, line)
+		}
+	}
+}
+
+func TestFileOutputNeverWrapped(t *testing.T) {
+	fileCfg := &FileConfig{Format: FormatCustom}
+	if fileCfg.GetWrapWidth() != 0 {
+		t.Error("Expected file output to never wrap")
+	}
+
+	cfg := DefaultConfig()
+	WithWrapWidth(40)(cfg)
+	if cfg.Console.GetWrapWidth() != 40 {
+		t.Errorf("Expected console wrap width 40, got %d", cfg.Console.GetWrapWidth())
+	}
+}
+
+func TestTruncateLine(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		n    int
+		want string
+	}{
+		{"Short line unchanged", "short", 10, "short"},
+		{"Exact fit unchanged", "12345", 5, "12345"},
+		{"Cut with ellipsis", "abcdefghij", 8, "abcde" + lineEllipsis},
+		{"Rune safe", "ééééé", 7, "éé" + lineEllipsis},
+		{"Escape sequence kept whole", "ab" + ansiFaint + "cdefgh" + ansiReset, 14, "ab" + ansiFaint + "c" + ansiReset + lineEllipsis},
+		{"Too short for ellipsis", "abcdef", 2, "ab"},
+		{"Too short for ellipsis keeps reset", "ab" + ansiFaint + "cdefgh" + ansiReset, 6, "ab" + ansiReset},
+		{"Too short for reset drops colors", ansiFaint + "abcdefgh" + ansiReset, 3, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateLine(tt.in, tt.n)
+			if got != tt.want {
+				t.Errorf("truncateLine(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+			}
+			if len(got) > tt.n && len(tt.in) > tt.n {
+				t.Errorf("Result %q exceeds %d bytes", got, tt.n)
+			}
+		})
+	}
+}
+
+func TestCustomHandler_MaxLineBytes(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := DefaultConfig()
+	cfg.MaxLineBytes = 100
+	handler, err := newCustomHandler(&buf, cfg, &mockOutputConfig{
+		format:    FormatCustom,
+		formatter: "{level} {message} {attrs}",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	args := make([]any, 0, 100)
+	for i := 0; i < 50; i++ {
+		args = append(args, "key"+strings.Repeat("x", i%5), "välue")
+	}
+	slog.New(handler).Info("many attributes", args...)
+
+	output := buf.String()
+	if !strings.HasSuffix(output, lineEllipsis+"\n") {
+		t.Errorf("Expected ellipsis before the newline, got %q", output)
+	}
+	if line := strings.TrimSuffix(output, "\n"); len(line) > 100 {
+		t.Errorf("Expected line capped at 100 bytes, got %d: %q", len(line), line)
+	}
+	if !utf8.ValidString(output) {
+		t.Errorf("Expected valid UTF-8 after truncation, got %q", output)
+	}
+
+	// Short lines are untouched
+	buf.Reset()
+	slog.New(handler).Info("short")
+	if got := buf.String(); got != "INFO short\n" {
+		t.Errorf("Expected short line unchanged, got %q", got)
+	}
+}